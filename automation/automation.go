@@ -0,0 +1,61 @@
+// Package automation lets the bot stay connected and answer slash commands while it stops acting
+// on a guild's gateway events and due tasks, e.g. while its configuration does not match the guild.
+package automation
+
+import (
+	"astralHRBot/logger"
+	"sync"
+)
+
+var (
+	mu      sync.RWMutex
+	reasons = make(map[string]string) // guild ID -> why its automation is paused
+)
+
+// Pause stops a guild's gateway events and tasks from being acted on until Resume is called
+func Pause(guildID, why string) {
+	mu.Lock()
+	_, wasPaused := reasons[guildID]
+	reasons[guildID] = why
+	mu.Unlock()
+
+	if !wasPaused {
+		logger.Warn(logger.LogData{
+			"action":   "automation_paused",
+			"message":  "Automation paused",
+			"guild_id": guildID,
+			"reason":   why,
+		})
+	}
+}
+
+// Resume lets a guild's gateway events and tasks be acted on again
+func Resume(guildID string) {
+	mu.Lock()
+	_, wasPaused := reasons[guildID]
+	delete(reasons, guildID)
+	mu.Unlock()
+
+	if wasPaused {
+		logger.Info(logger.LogData{
+			"action":   "automation_resumed",
+			"message":  "Automation resumed",
+			"guild_id": guildID,
+		})
+	}
+}
+
+// Paused reports whether a guild's automation is paused
+func Paused(guildID string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, paused := reasons[guildID]
+	return paused
+}
+
+// Reason returns why a guild's automation was paused, or an empty string if it is running
+func Reason(guildID string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return reasons[guildID]
+}
@@ -3,14 +3,13 @@ package bot
 import (
 	"astralHRBot/bot/identity"
 	"astralHRBot/commands"
+	"astralHRBot/discord"
 	"astralHRBot/handlers"
-	"astralHRBot/helper"
 	"astralHRBot/logger"
-	discordAPIWorker "astralHRBot/workers/discordAPI"
-	"astralHRBot/workers/eventWorker"
+	"astralHRBot/metrics"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"sync/atomic"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -19,15 +18,10 @@ var (
 	Discord *discordgo.Session
 	// ReadyChan signals when Discord connection is established
 	ReadyChan chan struct{}
+	// connected tracks whether the gateway websocket is currently up
+	connected atomic.Bool
 )
 
-// GetGuildID safely retrieves the guild ID from the global Discord session,
-// preferring environment variable over state
-// Returns the guild ID and an error if it cannot be determined
-func GetGuildID() (string, error) {
-	return helper.GetGuildIDFromSession(Discord)
-}
-
 func Setup() {
 	botToken, exists := os.LookupEnv("BOT_TOKEN")
 	if !exists {
@@ -49,12 +43,23 @@ func Setup() {
 		os.Exit(1)
 	}
 
+	discord.SetClient(discord.FromSession(Discord))
+
 	// Add all handlers before opening the connection
 	Discord.AddHandler(handlers.MessageHandlers)
 	Discord.AddHandler(handlers.MemberLeaversAndJoiners)
 	Discord.AddHandler(handlers.GuildMemberUpdateHandlers)
 	Discord.AddHandler(handlers.ManageGuildChanges)
 	Discord.AddHandler(commands.SlashCommandHandlers)
+	Discord.AddHandler(func(s *discordgo.Session, e *discordgo.Event) { metrics.GatewayEvent(e.Type) })
+	Discord.AddHandler(func(s *discordgo.Session, c *discordgo.Connect) { connected.Store(true) })
+	Discord.AddHandler(func(s *discordgo.Session, d *discordgo.Disconnect) {
+		connected.Store(false)
+		logger.Warn(logger.LogData{
+			"action":  "gateway_disconnected",
+			"message": "Lost connection to the Discord gateway",
+		})
+	})
 
 	Discord.Identify.Intents = discordgo.IntentsAll
 
@@ -62,7 +67,26 @@ func Setup() {
 	ReadyChan = make(chan struct{})
 }
 
-func Start() {
+// Connected reports whether the gateway connection is currently open
+func Connected() bool {
+	return connected.Load()
+}
+
+// IsReady reports whether Start has connected to Discord and registered commands
+func IsReady() bool {
+	if ReadyChan == nil {
+		return false
+	}
+	select {
+	case <-ReadyChan:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start opens the gateway connection and registers slash commands
+func Start() error {
 	logger.Info(logger.LogData{
 		"action":  "server_startup",
 		"message": "Attempting to open connection to Discord...",
@@ -70,15 +94,10 @@ func Start() {
 
 	err := Discord.Open()
 	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "server_startup",
-			"message": "Error opening connection to Discord",
-			"error":   err.Error(),
-		})
-		os.Exit(1)
+		return fmt.Errorf("error opening connection to Discord: %w", err)
 	}
 
-	identity.SetupBotIdentity(Discord)
+	identity.SetupBotIdentity(discord.GetClient())
 
 	// Register slash commands
 	commands.RegisterAllSlashCommands()
@@ -102,28 +121,13 @@ func Start() {
 		"action":  "server_startup",
 		"message": "Astral HR Bot is running...",
 	})
+	return nil
+}
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
-
-	defer func() {
-		logger.Info(logger.LogData{
-			"action":  "server_shutdown",
-			"message": "Astral HR Bot is shutting down...",
-		})
-		eventWorker.Shutdown()
-		discordAPIWorker.Stop()
-		if err := Discord.Close(); err != nil {
-			logger.Error(logger.LogData{
-				"action":  "server_shutdown",
-				"message": "Error closing Discord session",
-				"error":   err.Error(),
-			})
-		}
-		logger.Info(logger.LogData{
-			"action":  "server_shutdown",
-			"message": "Astral HR Bot has shut down gracefully.",
-		})
-	}()
+// Stop closes the gateway connection so no new events arrive
+func Stop() error {
+	if Discord == nil {
+		return nil
+	}
+	return Discord.Close()
 }
@@ -1,9 +1,8 @@
 package identity
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/logger"
-
-	"github.com/bwmarrin/discordgo"
 )
 
 var BotID string
@@ -14,9 +13,9 @@ func GetBotID() string {
 }
 
 // SetupBotIdentity sets up the bot's identity
-func SetupBotIdentity(discord *discordgo.Session) error {
+func SetupBotIdentity(s discord.DiscordClient) error {
 	// Get bot's own user information
-	user, err := discord.User("@me")
+	user, err := s.User("@me")
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "server_startup",
@@ -1,11 +1,8 @@
 package channels
 
-import (
-	"log"
-	"os"
-)
+import "astralHRBot/config"
 
-// Channel references with environment variable names
+// Channel setting keys, which are also the environment variables they are first seeded from
 const (
 	// Announcement Channels
 	GeneralChannel = "GENERAL_CHANNEL_ID"
@@ -18,43 +15,62 @@ const (
 	RecruitmentHub     = "RECRUITMENT_HUB_ID"
 
 	HRChannel = "HR_CHANNEL_ID"
+
+	// Channels new members are pointed to in their welcome message
+	GuidesChannel        = "GUIDES_CHANNEL_ID"
+	LogisticsChannel     = "LOGISTICS_CHANNEL_ID"
+	ContentOptOutChannel = "CONTENT_OPT_OUT_CHANNEL_ID"
+
+	// LogChannel is optional; warnings and errors are forwarded to it when set
+	LogChannel = "LOG_CHANNEL_ID"
 )
 
-// GetChannelID returns the channel ID from environment variables
-func GetChannelID(channelEnvVar string) string {
-	id, exists := os.LookupEnv(channelEnvVar)
-	if !exists {
-		log.Printf("Warning: Channel ID not found for environment variable: %s", channelEnvVar)
-		return ""
-	}
-	return id
+func init() {
+	config.Register(
+		config.Setting{Key: GeneralChannel, Kind: config.KindChannel, Description: "Where new members are welcomed"},
+		config.Setting{Key: LandingChannel, Kind: config.KindChannel, Description: "Where joins are announced"},
+		config.Setting{Key: LeaversChannel, Kind: config.KindChannel, Description: "Where leaves are announced"},
+		config.Setting{Key: RecruitmentChannel, Kind: config.KindChannel, Description: "Where recruits are welcomed and reminded"},
+		config.Setting{Key: RecruitmentForum, Kind: config.KindChannel, Description: "Forum holding a thread per recruit"},
+		config.Setting{Key: RecruitmentHub, Kind: config.KindChannel, Description: "Where the recruitment team is notified"},
+		config.Setting{Key: HRChannel, Kind: config.KindChannel, Description: "Where HR staff are notified"},
+		config.Setting{Key: GuidesChannel, Kind: config.KindChannel, Description: "Guides linked in the member welcome"},
+		config.Setting{Key: LogisticsChannel, Kind: config.KindChannel, Description: "Logistics service linked in the member welcome"},
+		config.Setting{Key: ContentOptOutChannel, Kind: config.KindChannel, Description: "Content ping opt-outs linked in the member welcome"},
+		config.Setting{Key: LogChannel, Kind: config.KindChannel, Description: "Where warnings and errors are forwarded, read at startup", Global: true, Optional: true},
+	)
+}
+
+// GetChannelID returns the channel ID configured under a setting key in a guild
+func GetChannelID(guildID, key string) string {
+	return config.ChannelID(guildID, key)
 }
 
 // Helper functions for each channel
-func GetGeneralChannel() string {
-	return GetChannelID(GeneralChannel)
+func GetGeneralChannel(guildID string) string {
+	return GetChannelID(guildID, GeneralChannel)
 }
 
-func GetLandingChannel() string {
-	return GetChannelID(LandingChannel)
+func GetLandingChannel(guildID string) string {
+	return GetChannelID(guildID, LandingChannel)
 }
 
-func GetLeaversChannel() string {
-	return GetChannelID(LeaversChannel)
+func GetLeaversChannel(guildID string) string {
+	return GetChannelID(guildID, LeaversChannel)
 }
 
-func GetRecruitmentChannel() string {
-	return GetChannelID(RecruitmentChannel)
+func GetRecruitmentChannel(guildID string) string {
+	return GetChannelID(guildID, RecruitmentChannel)
 }
 
-func GetRecruitmentForum() string {
-	return GetChannelID(RecruitmentForum)
+func GetRecruitmentForum(guildID string) string {
+	return GetChannelID(guildID, RecruitmentForum)
 }
 
-func GetRecruitmentHub() string {
-	return GetChannelID(RecruitmentHub)
+func GetRecruitmentHub(guildID string) string {
+	return GetChannelID(guildID, RecruitmentHub)
 }
 
-func GetHRChannel() string {
-	return GetChannelID(HRChannel)
+func GetHRChannel(guildID string) string {
+	return GetChannelID(guildID, HRChannel)
 }
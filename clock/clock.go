@@ -0,0 +1,78 @@
+// Package clock is the time source for scheduling, monitoring windows and task leases.
+// Production code reads the time through Now so tests can swap in a Fake and advance it.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock reports the current time
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+var (
+	current   Clock = realClock{}
+	currentMu sync.RWMutex
+)
+
+// Get returns the clock in use
+func Get() Clock {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// Set replaces the clock in use; passing nil restores the system clock
+func Set(c Clock) {
+	if c == nil {
+		c = realClock{}
+	}
+	currentMu.Lock()
+	current = c
+	currentMu.Unlock()
+}
+
+// Now returns the current time according to the clock in use
+func Now() time.Time {
+	return Get().Now()
+}
+
+// Fake is a clock that only moves when told to
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake creates a fake clock stopped at start
+func NewFake(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the fake clock's current time
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Advance moves the fake clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
+
+// Set moves the fake clock to t
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	f.now = t
+	f.mu.Unlock()
+}
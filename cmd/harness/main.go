@@ -0,0 +1,35 @@
+// Command harness replays the built-in scenarios through the full event pipeline against a fake
+// guild and exits non-zero if any of them fail.
+package main
+
+import (
+	"astralHRBot/harness"
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	verbose := flag.Bool("v", false, "enable debug logging")
+	flag.Parse()
+
+	h, err := harness.Start(harness.Config{Verbose: *verbose})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start harness: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, scenario := range harness.Scenarios() {
+		if err := h.Run(scenario); err != nil {
+			failed++
+			fmt.Printf("FAIL %s\n%v\n", scenario.Name, err)
+			continue
+		}
+		fmt.Printf("ok   %s\n", scenario.Name)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -24,10 +24,26 @@ var commandDefinitions = []struct {
 	{GetRebuildNewRecruitScenariosCommandDefinition(), RebuildNewRecruitScenariosCommand},
 	{GetRebuildRecruitmentProcessScenariosCommandDefinition(), RebuildRecruitmentProcessScenariosCommand},
 	{GetRebuildAnalyticsCommandDefinition(), RebuildAnalyticsCommand},
+	{GetDeadLetterTasksCommandDefinition(), DeadLetterTasksCommand},
+	{GetTasksCommandDefinition(), TasksCommand},
+	{GetDBConsistencyCheckCommandDefinition(), DBConsistencyCheckCommand},
+	{GetConfigCommandDefinition(), ConfigCommand},
+	{GetTemplateCommandDefinition(), TemplateCommand},
 	// Add more commands here as you create them
 	// {GetAnotherCommandDefinition(), AnotherCommand},
 }
 
+// Autocomplete handlers keyed by the name of the command whose options they complete
+var autocompleteHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+	"tasks":  TasksAutocomplete,
+	"config": ConfigAutocomplete,
+}
+
+// Modal submit handlers keyed by the custom ID prefix of the modal they handle
+var modalHandlers = map[string]func(s *discordgo.Session, i *discordgo.InteractionCreate){
+	templateEditModal: TemplateEditSubmit,
+}
+
 // RegisterAllSlashCommands registers all slash commands with the bot
 func RegisterAllSlashCommands() {
 	// Auto-register all command handlers
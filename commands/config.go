@@ -0,0 +1,315 @@
+package commands
+
+import (
+	"astralHRBot/config"
+	"astralHRBot/logger"
+	"astralHRBot/validation"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// configHistoryFetched is how many recent changes /config history looks through
+	configHistoryFetched = 200
+	// configHistoryShown is the number of changes shown by /config history
+	configHistoryShown = 15
+)
+
+// ConfigCommand handles the /config slash command
+func ConfigCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger.Debug(logger.LogData{
+		"action":  "config_command",
+		"message": "Config command executed",
+		"user_id": i.Member.User.ID,
+	})
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		RespondToInteraction(s, i, "Please choose a subcommand", true)
+		return
+	}
+
+	subcommand := options[0]
+	switch subcommand.Name {
+	case "get":
+		getConfigSetting(s, i, subcommand.Options)
+	case "set":
+		setConfigSetting(s, i, subcommand.Options)
+	case "list":
+		listConfigSettings(s, i)
+	case "history":
+		showConfigHistory(s, i, subcommand.Options)
+	case "validate":
+		RespondToInteractionWithEmbed(s, i, validation.Validate(i.GuildID).Embed(), true)
+	}
+}
+
+func getConfigSetting(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	key := getStringOption(options, "key")
+	setting, exists := config.Lookup(key)
+	if !exists {
+		RespondToInteraction(s, i, fmt.Sprintf("Unknown setting `%s`", key), true)
+		return
+	}
+
+	content := fmt.Sprintf("`%s` (%s): %s\n%s", setting.Key, setting.Kind, formatConfigValue(setting, config.Get(i.GuildID, key)), setting.Description)
+	RespondToInteraction(s, i, content, true)
+}
+
+func setConfigSetting(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	key := getStringOption(options, "key")
+	setting, exists := config.Lookup(key)
+	if !exists {
+		RespondToInteraction(s, i, fmt.Sprintf("Unknown setting `%s`", key), true)
+		return
+	}
+
+	// Roles and channels are picked so admins never have to copy IDs; value is the fallback
+	value := getStringOption(options, "value")
+	for _, opt := range options {
+		switch {
+		case opt.Name == "role" && setting.Kind == config.KindRole:
+			value = opt.RoleValue(nil, "").ID
+		case opt.Name == "channel" && setting.Kind == config.KindChannel:
+			value = opt.ChannelValue(nil).ID
+		}
+	}
+	if value == "" {
+		RespondToInteraction(s, i, fmt.Sprintf("Please pick a %s for `%s`", setting.Kind, key), true)
+		return
+	}
+
+	change, err := config.Set(context.Background(), i.GuildID, key, value, i.Member.User.ID)
+	if err != nil {
+		RespondToInteraction(s, i, fmt.Sprintf("Failed to update setting: %s", err.Error()), true)
+		return
+	}
+
+	content := fmt.Sprintf("✅ `%s` changed from %s to %s", key, formatConfigValue(setting, change.OldValue), formatConfigValue(setting, change.NewValue))
+	RespondToInteraction(s, i, content, true)
+}
+
+func listConfigSettings(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	groups := map[config.Kind][]string{}
+	var kinds []config.Kind
+	for _, setting := range config.Settings() {
+		if _, seen := groups[setting.Kind]; !seen {
+			kinds = append(kinds, setting.Kind)
+		}
+		groups[setting.Kind] = append(groups[setting.Kind], fmt.Sprintf("`%s` %s", setting.Key, formatConfigValue(setting, config.Get(i.GuildID, setting.Key))))
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "Configuration",
+		Color: 0x000000,
+	}
+	for _, kind := range kinds {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  configKindHeadings[kind],
+			Value: strings.Join(groups[kind], "\n"),
+		})
+	}
+
+	RespondToInteractionWithEmbed(s, i, embed, true)
+}
+
+func showConfigHistory(s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	key := getStringOption(options, "key")
+
+	changes, err := config.History(context.Background(), i.GuildID, configHistoryFetched)
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "config_command",
+			"message": "Failed to fetch config history",
+			"error":   err.Error(),
+		})
+		RespondToInteraction(s, i, "Error retrieving setting history", true)
+		return
+	}
+
+	var lines []string
+	for _, change := range changes {
+		if key != "" && change.Key != key {
+			continue
+		}
+		setting, _ := config.Lookup(change.Key)
+		lines = append(lines, fmt.Sprintf("<t:%d:f> <@%s> `%s`: %s → %s",
+			change.Timestamp, change.Actor, change.Key, formatConfigValue(setting, change.OldValue), formatConfigValue(setting, change.NewValue)))
+		if len(lines) == configHistoryShown {
+			break
+		}
+	}
+
+	if len(lines) == 0 {
+		RespondToInteraction(s, i, "No settings have been changed yet", true)
+		return
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title:       "Setting history",
+		Description: strings.Join(lines, "\n"),
+		Color:       0x000000,
+	}
+	RespondToInteractionWithEmbed(s, i, embed, true)
+}
+
+// configKindHeadings titles the groups shown by /config list
+var configKindHeadings = map[config.Kind]string{
+	config.KindRole:     "Roles",
+	config.KindChannel:  "Channels",
+	config.KindDays:     "Durations",
+	config.KindLogLevel: "Logging",
+	config.KindText:     "Text",
+	config.KindTemplate: "Templates",
+}
+
+// formatConfigValue shows roles and channels as mentions
+func formatConfigValue(setting config.Setting, value string) string {
+	if value == "" {
+		return "*not set*"
+	}
+	switch setting.Kind {
+	case config.KindRole:
+		return fmt.Sprintf("<@&%s>", value)
+	case config.KindChannel:
+		return fmt.Sprintf("<#%s>", value)
+	case config.KindTemplate:
+		// Templates are too long to list; /template preview shows them
+		if value == setting.Default {
+			return "*default*"
+		}
+		return "*edited*"
+	}
+	return fmt.Sprintf("`%s`", value)
+}
+
+// ConfigAutocomplete suggests setting keys matching what has been typed
+func ConfigAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var typed string
+	options := i.ApplicationCommandData().Options
+	if len(options) > 0 {
+		for _, opt := range options[0].Options {
+			if opt.Focused {
+				typed = strings.ToLower(opt.StringValue())
+			}
+		}
+	}
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+	for _, setting := range config.Settings() {
+		if typed != "" && !strings.Contains(strings.ToLower(setting.Key), typed) {
+			continue
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s (%s)", setting.Key, setting.Kind),
+			Value: setting.Key,
+		})
+		if len(choices) == maxAutocompleteChoices {
+			break
+		}
+	}
+
+	discordAPIWorker.NewRequest(interactionEvent(i), func() error {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{
+				Choices: choices,
+			},
+		})
+		if err != nil {
+			logger.Error(logger.LogData{
+				"action":  "config_autocomplete",
+				"message": "Failed to respond to autocomplete",
+				"error":   err.Error(),
+			})
+		}
+		return err
+	}, interactionRequest(i)...)
+}
+
+// GetConfigCommandDefinition returns the config command definition
+func GetConfigCommandDefinition() *discordgo.ApplicationCommand {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	keyOption := func(description string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "key",
+			Description:  description,
+			Required:     true,
+			Autocomplete: true,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "config",
+		Description:              "View and change the bot's settings",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "get",
+				Description: "Show the current value of a setting",
+				Options: []*discordgo.ApplicationCommandOption{
+					keyOption("Setting to show"),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "Change a setting",
+				Options: []*discordgo.ApplicationCommandOption{
+					keyOption("Setting to change"),
+					{
+						Type:        discordgo.ApplicationCommandOptionRole,
+						Name:        "role",
+						Description: "New role, for role settings",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionChannel,
+						Name:        "channel",
+						Description: "New channel, for channel settings",
+						ChannelTypes: []discordgo.ChannelType{
+							discordgo.ChannelTypeGuildText,
+							discordgo.ChannelTypeGuildNews,
+							discordgo.ChannelTypeGuildForum,
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "value",
+						Description: "New value, for settings that are not a role or channel",
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "Show every setting",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "history",
+				Description: "Show who changed settings and when",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:         discordgo.ApplicationCommandOptionString,
+						Name:         "key",
+						Description:  "Only show changes to this setting",
+						Autocomplete: true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "validate",
+				Description: "Check the role and channel settings against the server",
+			},
+		},
+	}
+}
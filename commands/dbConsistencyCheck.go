@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"astralHRBot/db"
+	"astralHRBot/logger"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// consistencyExamplesShown is the number of affected IDs listed per problem type
+const consistencyExamplesShown = 5
+
+// DBConsistencyCheckCommand handles the /db-consistency-check slash command
+func DBConsistencyCheckCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger.Debug(logger.LogData{
+		"action":  "db_consistency_check_command",
+		"message": "DBConsistencyCheck command executed",
+		"user_id": i.Member.User.ID,
+	})
+
+	repair := false
+	for _, opt := range i.ApplicationCommandData().Options {
+		if opt.Name == "repair" {
+			repair = opt.BoolValue()
+		}
+	}
+
+	RespondToInteraction(s, i, "🔍 **Running database consistency check...**", true)
+
+	report, err := db.CheckConsistency(db.WithGuild(context.Background(), i.GuildID), repair)
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "db_consistency_check_command",
+			"message": "Consistency check failed",
+			"error":   err.Error(),
+		})
+		FollowUpMessage(s, i, fmt.Sprintf("❌ Consistency check failed: %s", err.Error()), true)
+		return
+	}
+
+	if repair {
+		logger.Info(logger.LogData{
+			"action":   "db_consistency_check_command",
+			"message":  "Repaired database inconsistencies",
+			"problems": report.Total(),
+			"user_id":  i.Member.User.ID,
+		})
+	}
+
+	// Quarantined tasks are already isolated from the queue, so they are reported but not repaired
+	quarantine := ""
+	quarantined, err := db.GetQuarantinedTaskIDs(db.WithGuild(context.Background(), i.GuildID))
+	if err == nil && len(quarantined) > 0 {
+		quarantine = fmt.Sprintf("\n🧪 %d quarantined tasks (%d since startup)\n", len(quarantined), db.QuarantinedTaskCount())
+		quarantine += formatConsistencyProblem("Quarantined tasks", quarantined)
+	}
+
+	if report.Total() == 0 {
+		FollowUpMessage(s, i, "✅ No inconsistencies found"+quarantine, true)
+		return
+	}
+
+	response := fmt.Sprintf("⚠️ **Found %d inconsistencies**\n\n", report.Total())
+	response += formatConsistencyProblem("Queue entries without a task", report.DanglingQueueEntries)
+	response += formatConsistencyProblem("Task blobs not in any queue", report.OrphanedTaskBlobs)
+	response += formatConsistencyProblem("Stale task index entries", report.StaleIndexEntries)
+	response += formatConsistencyProblem("Stale monitoring session entries", report.StaleSessionEntries)
+	response += formatConsistencyProblem("Users with sessions but not tracked", report.UntrackedUsers)
+	response += formatConsistencyProblem("Tracked users without a session", report.TrackedUsersWithoutSession)
+
+	if repair {
+		response += "\n🔧 All of the above have been repaired"
+	} else {
+		response += "\nRun again with `repair: True` to fix them"
+	}
+	response += quarantine
+
+	FollowUpMessage(s, i, response, true)
+}
+
+func formatConsistencyProblem(title string, affected []string) string {
+	if len(affected) == 0 {
+		return ""
+	}
+
+	shown := affected
+	if len(shown) > consistencyExamplesShown {
+		shown = shown[:consistencyExamplesShown]
+	}
+
+	line := fmt.Sprintf("**%s** (%d)\n`%s`", title, len(affected), strings.Join(shown, "`, `"))
+	if len(affected) > len(shown) {
+		line += fmt.Sprintf(" and %d more", len(affected)-len(shown))
+	}
+	return line + "\n"
+}
+
+// GetDBConsistencyCheckCommandDefinition returns the db-consistency-check command definition
+func GetDBConsistencyCheckCommandDefinition() *discordgo.ApplicationCommand {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "db-consistency-check",
+		Description:              "Find orphaned or dangling task and monitoring data in Redis (Administrator only)",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "repair",
+				Description: "Repair the problems found instead of only reporting them",
+				Required:    false,
+			},
+		},
+	}
+}
@@ -0,0 +1,189 @@
+package commands
+
+import (
+	"astralHRBot/db"
+	"astralHRBot/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// deadLetterErrorMaxLength is how much of each task's last error /task-dead-letter list shows
+const deadLetterErrorMaxLength = 200
+
+// DeadLetterTasksCommand handles the /task-dead-letter slash command
+func DeadLetterTasksCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger.Debug(logger.LogData{
+		"action":  "dead_letter_tasks_command",
+		"message": "DeadLetterTasks command executed",
+		"user_id": i.Member.User.ID,
+	})
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		RespondToInteraction(s, i, "Please choose a subcommand", true)
+		return
+	}
+
+	subcommand := options[0]
+	ctx := db.WithGuild(context.Background(), i.GuildID)
+
+	switch subcommand.Name {
+	case "list":
+		listDeadLetterTasks(ctx, s, i)
+	case "retry":
+		taskID := subcommand.Options[0].StringValue()
+		if err := db.GetStore().RequeueDeadLetterTask(ctx, taskID); err != nil {
+			logger.Error(logger.LogData{
+				"action":  "dead_letter_tasks_command",
+				"message": "Failed to requeue dead-letter task",
+				"error":   err.Error(),
+				"task_id": taskID,
+			})
+			RespondToInteraction(s, i, fmt.Sprintf("Failed to retry task: %s", err.Error()), true)
+			return
+		}
+		logger.Info(logger.LogData{
+			"action":  "dead_letter_tasks_command",
+			"message": "Requeued dead-letter task",
+			"task_id": taskID,
+			"user_id": i.Member.User.ID,
+		})
+		RespondToInteraction(s, i, fmt.Sprintf("✅ Task `%s` has been requeued and will run shortly", taskID), true)
+	case "purge":
+		purgeDeadLetterTasks(ctx, s, i, subcommand.Options)
+	}
+}
+
+func listDeadLetterTasks(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	tasks, err := db.GetStore().GetDeadLetterTasks(ctx)
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "dead_letter_tasks_command",
+			"message": "Failed to get dead-letter tasks",
+			"error":   err.Error(),
+		})
+		RespondToInteraction(s, i, "Error retrieving dead-letter tasks", true)
+		return
+	}
+
+	if len(tasks) == 0 {
+		RespondToInteraction(s, i, "The dead-letter queue is empty", true)
+		return
+	}
+
+	response := fmt.Sprintf("☠️ **Dead-letter tasks (%d)**\n\n", len(tasks))
+	for n, task := range tasks {
+		entry := fmt.Sprintf("**%s** `%s`\n", string(task.FunctionName), task.TaskID)
+		entry += fmt.Sprintf("• Attempts: `%d`\n", task.Retries)
+		entry += fmt.Sprintf("• Last scheduled: `%s`\n", time.Unix(task.ScheduledTime, 0).Format("2006-01-02 15:04:05"))
+		if task.LastError != "" {
+			lastError := task.LastError
+			if runes := []rune(lastError); len(runes) > deadLetterErrorMaxLength {
+				lastError = string(runes[:deadLetterErrorMaxLength-1]) + "…"
+			}
+			entry += fmt.Sprintf("• Last error: `%s`\n", lastError)
+		}
+		entry += "\n"
+
+		if len(response)+len(entry) > taskListMaxLength {
+			response += fmt.Sprintf("…and %d more.", len(tasks)-n)
+			break
+		}
+		response += entry
+	}
+
+	RespondToInteraction(s, i, response, true)
+}
+
+func purgeDeadLetterTasks(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Purge a single task when an ID is given, otherwise purge everything
+	if len(options) > 0 {
+		taskID := options[0].StringValue()
+		if err := db.GetStore().PurgeDeadLetterTask(ctx, taskID); err != nil {
+			RespondToInteraction(s, i, fmt.Sprintf("Failed to purge task: %s", err.Error()), true)
+			return
+		}
+		logger.Info(logger.LogData{
+			"action":  "dead_letter_tasks_command",
+			"message": "Purged dead-letter task",
+			"task_id": taskID,
+			"user_id": i.Member.User.ID,
+		})
+		RespondToInteraction(s, i, fmt.Sprintf("🗑️ Task `%s` has been purged", taskID), true)
+		return
+	}
+
+	tasks, err := db.GetStore().GetDeadLetterTasks(ctx)
+	if err != nil {
+		RespondToInteraction(s, i, "Error retrieving dead-letter tasks", true)
+		return
+	}
+
+	purged := 0
+	for _, task := range tasks {
+		if err := db.GetStore().PurgeDeadLetterTask(ctx, task.TaskID); err != nil {
+			logger.Error(logger.LogData{
+				"action":  "dead_letter_tasks_command",
+				"message": "Failed to purge dead-letter task",
+				"error":   err.Error(),
+				"task_id": task.TaskID,
+			})
+			continue
+		}
+		purged++
+	}
+
+	logger.Info(logger.LogData{
+		"action":  "dead_letter_tasks_command",
+		"message": "Purged dead-letter queue",
+		"purged":  purged,
+		"user_id": i.Member.User.ID,
+	})
+	RespondToInteraction(s, i, fmt.Sprintf("🗑️ Purged %d dead-letter tasks", purged), true)
+}
+
+// GetDeadLetterTasksCommandDefinition returns the task-dead-letter command definition
+func GetDeadLetterTasksCommandDefinition() *discordgo.ApplicationCommand {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "task-dead-letter",
+		Description:              "Inspect, retry or purge tasks that exhausted their retries",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List tasks in the dead-letter queue",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "retry",
+				Description: "Move a dead-letter task back into the queue",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "task_id",
+						Description: "The ID of the task to retry",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "purge",
+				Description: "Delete dead-letter tasks permanently",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "task_id",
+						Description: "The ID of the task to purge (purges all when omitted)",
+						Required:    false,
+					},
+				},
+			},
+		},
+	}
+}
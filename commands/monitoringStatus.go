@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"astralHRBot/clock"
 	"astralHRBot/db"
 	"astralHRBot/logger"
 	"context"
@@ -20,10 +21,10 @@ func MonitoringStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 
 	RespondToInteraction(s, i, "🔄 **Fetching all monitored users...**", true)
 
-	ctx := context.Background()
+	ctx := db.WithGuild(context.Background(), i.GuildID)
 
 	// Get all tracked users
-	trackedUsers, err := db.GetTrackedUsers(ctx)
+	trackedUsers, err := db.GetStore().GetTrackedUsers(ctx)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "monitoring_status_command",
@@ -46,7 +47,7 @@ func MonitoringStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 
 	for _, userID := range trackedUsers {
 		// Get user monitoring data
-		monitoringData, err := db.GetUserMonitoring(ctx, userID)
+		monitoringData, err := db.GetStore().GetUserMonitoring(ctx, userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "monitoring_status_command",
@@ -81,7 +82,7 @@ func MonitoringStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 		}
 
 		// Get user's tasks
-		tasks, err := db.GetTasksForUser(ctx, userID)
+		tasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "monitoring_status_command",
@@ -116,7 +117,7 @@ func MonitoringStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 		// Add expiration information
 		if monitoringData.ExpiresAt > 0 {
 			expirationTime := time.Unix(monitoringData.ExpiresAt, 0)
-			now := time.Now()
+			now := clock.Now()
 
 			if expirationTime.After(now) {
 				// Future expiration
@@ -143,7 +144,7 @@ func MonitoringStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 			taskDetails := []string{}
 			for _, task := range tasks {
 				scheduledTime := time.Unix(task.ScheduledTime, 0)
-				timeUntilTask := time.Until(scheduledTime)
+				timeUntilTask := scheduledTime.Sub(clock.Now())
 				scenarioLabel := task.Scenario
 				if scenarioLabel == "" {
 					scenarioLabel = "unspecified"
@@ -21,10 +21,10 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 		"user_id": i.Member.User.ID,
 	})
 
-	ctx := context.Background()
+	ctx := db.WithGuild(context.Background(), i.GuildID)
 
 	// Get all tracked users
-	trackedUsers, err := db.GetTrackedUsers(ctx)
+	trackedUsers, err := db.GetStore().GetTrackedUsers(ctx)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "rebuild_all_user_events_command",
@@ -51,7 +51,7 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 
 	for _, userID := range trackedUsers {
 		// Get user monitoring data
-		monitoringData, err := db.GetUserMonitoring(ctx, userID)
+		monitoringData, err := db.GetStore().GetUserMonitoring(ctx, userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_all_user_events_command",
@@ -74,7 +74,7 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 		}
 
 		// Check existing tasks
-		existingTasks, err := db.GetTasksForUser(ctx, userID)
+		existingTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_all_user_events_command",
@@ -114,15 +114,15 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 					monitoringData.ExpiresAt = earliest
 					defaultDays := 7
 					if monitoringData.HasScenario(models.MonitoringScenarioNewRecruit) {
-						defaultDays = globals.GetNewRecruitTrackingDays()
+						defaultDays = globals.GetNewRecruitTrackingDays(i.GuildID)
 					} else if monitoringData.HasScenario(models.MonitoringScenarioRecruitmentProcess) {
-						defaultDays = globals.GetRecruitmentCleanupDelay()
+						defaultDays = globals.GetRecruitmentCleanupDelay(i.GuildID)
 					}
 					monitoringData.StartedAt = time.Unix(earliest, 0).Add(-time.Duration(defaultDays) * 24 * time.Hour).Unix()
 				}
 			}
 
-			if err := db.SaveUserMonitoring(ctx, monitoringData); err != nil {
+			if err := db.GetStore().SaveUserMonitoring(ctx, monitoringData); err != nil {
 				logger.Error(logger.LogData{
 					"action":  "rebuild_all_user_events_command",
 					"message": "Failed to save backfilled monitoring data",
@@ -135,7 +135,7 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 		// Remove existing tasks
 		tasksRemoved := 0
 		for _, task := range existingTasks {
-			err = db.DeleteTaskFromRedis(ctx, task.TaskID)
+			err = db.GetStore().DeleteTask(ctx, task.TaskID)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"action":  "rebuild_all_user_events_command",
@@ -157,7 +157,7 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 			"scenarios": monitoringData.GetScenarios(),
 		})
 
-		err = monitoring.RecreateTasksForUser(userID, monitoringData)
+		err = monitoring.RecreateTasksForUser(i.GuildID, userID, monitoringData)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_all_user_events_command",
@@ -170,7 +170,7 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 		}
 
 		// Get new task count
-		newTasks, err := db.GetTasksForUser(ctx, userID)
+		newTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_all_user_events_command",
@@ -187,7 +187,7 @@ func RebuildAllUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionC
 		totalTasksCreated += len(newTasks)
 
 		// Get updated monitoring data to check for removed scenarios
-		updatedMonitoringData, err := db.GetUserMonitoring(ctx, userID)
+		updatedMonitoringData, err := db.GetStore().GetUserMonitoring(ctx, userID)
 		var activeScenarios []string
 		if err == nil && updatedMonitoringData != nil {
 			scenarios := updatedMonitoringData.GetScenarios()
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/logger"
 	"astralHRBot/workers/eventWorker"
 	"astralHRBot/workers/monitoring"
@@ -33,8 +34,8 @@ func RebuildAnalyticsCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 	RespondToInteraction(s, i, fmt.Sprintf("🔄 **Rebuilding analytics for %s...**", userName), true)
 
 	// Trigger analytics rebuild via event worker
-	eventWorker.Submit(userID, func(e eventWorker.Event) {
-		ctx := context.Background()
+	eventWorker.Submit(i.GuildID, userID, func(e eventWorker.Event) {
+		ctx := db.WithGuild(context.Background(), e.GuildID)
 
 		logger.Info(logger.LogData{
 			"trace_id": e.TraceID,
@@ -44,7 +45,7 @@ func RebuildAnalyticsCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 		})
 
 		// Get user monitoring data to determine the time period
-		monitoringData, err := db.GetUserMonitoring(ctx, e.UserID)
+		monitoringData, err := db.GetStore().GetUserMonitoring(ctx, e.UserID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": e.TraceID,
@@ -67,7 +68,7 @@ func RebuildAnalyticsCommand(s *discordgo.Session, i *discordgo.InteractionCreat
 		}
 
 		// Rebuild analytics for the user
-		result, err := monitoring.RebuildUserAnalytics(e.UserID, monitoringData, s, e.TraceID)
+		result, err := monitoring.RebuildUserAnalytics(e.GuildID, e.UserID, monitoringData, discord.FromSession(s), e.TraceID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": e.TraceID,
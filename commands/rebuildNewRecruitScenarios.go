@@ -2,7 +2,9 @@ package commands
 
 import (
 	"astralHRBot/channels"
+	"astralHRBot/clock"
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/globals"
 	"astralHRBot/logger"
 	"astralHRBot/models"
@@ -27,10 +29,10 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 
 	RespondToInteraction(s, i, "🔄 **Scanning archived forum posts for new recruit scenarios...**", true)
 
-	ctx := context.Background()
+	ctx := db.WithGuild(context.Background(), i.GuildID)
 
 	// Get the recruitment forum channel ID from environment variables
-	forumChannelID := channels.GetRecruitmentForum()
+	forumChannelID := channels.GetRecruitmentForum(i.GuildID)
 	if forumChannelID == "" {
 		logger.Error(logger.LogData{
 			"action":  "rebuild_new_recruit_scenarios_command",
@@ -41,8 +43,8 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 	}
 
 	// Get the default tracking period
-	defaultTrackingDays := globals.GetNewRecruitTrackingDays()
-	cutoffTime := time.Now().Add(-time.Duration(defaultTrackingDays) * 24 * time.Hour)
+	defaultTrackingDays := globals.GetNewRecruitTrackingDays(i.GuildID)
+	cutoffTime := clock.Now().Add(-time.Duration(defaultTrackingDays) * 24 * time.Hour)
 
 	// Pattern to match the "Character Joined Corporation" message
 	pattern := regexp.MustCompile(`Character Joined Corporation\.`)
@@ -201,7 +203,7 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 		expirationTime := messageTime.Add(time.Duration(defaultTrackingDays) * 24 * time.Hour)
 
 		// Check if this would result in a task in the past
-		if expirationTime.Before(time.Now()) {
+		if expirationTime.Before(clock.Now()) {
 			logger.Debug(logger.LogData{
 				"action":          "rebuild_new_recruit_scenarios_command",
 				"message":         "Thread is too old, skipping",
@@ -215,7 +217,7 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 		}
 
 		// Check if user already has new_recruit scenario and remove it if so
-		userMonitoring, err := db.GetUserMonitoring(ctx, userID)
+		userMonitoring, err := db.GetStore().GetUserMonitoring(ctx, userID)
 		if err == nil && userMonitoring != nil && userMonitoring.HasScenario(models.MonitoringScenarioNewRecruit) {
 			logger.Debug(logger.LogData{
 				"action":    "rebuild_new_recruit_scenarios_command",
@@ -225,22 +227,23 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 			})
 
 			// Remove the existing scenario to recreate it with correct timing
-			monitoring.RemoveScenario(userID, models.MonitoringScenarioNewRecruit)
+			monitoring.RemoveScenario(i.GuildID, userID, models.MonitoringScenarioNewRecruit)
 		}
 
 		// Create the monitoring scenario
-		monitoring.AddUserTracking(userID, models.MonitoringScenarioNewRecruit, time.Duration(defaultTrackingDays)*24*time.Hour)
+		monitoring.AddUserTracking(i.GuildID, userID, models.MonitoringScenarioNewRecruit, time.Duration(defaultTrackingDays)*24*time.Hour)
 
 		// Persist scenario and window using helper
 		_ = monitoring.EnsureScenarioWindow(ctx, userID, models.MonitoringScenarioNewRecruit, messageTime, expirationTime)
 
 		// Fetch fresh monitoring data for analytics context
-		userMonitoring, _ = db.GetUserMonitoring(ctx, userID)
+		userMonitoring, _ = db.GetStore().GetUserMonitoring(ctx, userID)
 
 		// Create the user checkin task
 		params := &models.UserCheckinParams{UserID: userID}
 		scheduledTime := expirationTime.Unix()
 		newTask, err := models.NewTaskWithScenario(
+			i.GuildID,
 			models.TaskUserCheckin,
 			params,
 			scheduledTime,
@@ -258,7 +261,7 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 			return true // Continue processing other threads
 		}
 
-		err = db.SaveTaskToRedis(ctx, *newTask)
+		err = db.GetStore().SaveTask(ctx, *newTask)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_new_recruit_scenarios_command",
@@ -276,7 +279,7 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 			userName, expirationTime.Format("2006-01-02 15:04:05")))
 
 		// Trigger analytics rebuild for this user with the correct start time
-		eventWorker.Submit(userID, func(e eventWorker.Event) {
+		eventWorker.Submit(i.GuildID, userID, func(e eventWorker.Event) {
 			logger.Info(logger.LogData{
 				"trace_id": e.TraceID,
 				"action":   "rebuild_analytics_for_new_recruit",
@@ -285,7 +288,7 @@ func RebuildNewRecruitScenariosCommand(s *discordgo.Session, i *discordgo.Intera
 			})
 
 			// Use the monitoring data with the correct start time
-			result, err := monitoring.RebuildUserAnalytics(e.UserID, userMonitoring, s, e.TraceID)
+			result, err := monitoring.RebuildUserAnalytics(e.GuildID, e.UserID, userMonitoring, discord.FromSession(s), e.TraceID)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"trace_id": e.TraceID,
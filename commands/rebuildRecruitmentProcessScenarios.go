@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"astralHRBot/clock"
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/globals"
 	"astralHRBot/logger"
 	"astralHRBot/models"
@@ -25,7 +27,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 
 	RespondToInteraction(s, i, "🔄 **Scanning forum posts for recruitment process scenarios...**", true)
 
-	ctx := context.Background()
+	ctx := db.WithGuild(context.Background(), i.GuildID)
 
 	// Get all threads in the guild
 	threads, err := s.GuildThreadsActive(i.GuildID)
@@ -49,7 +51,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 	errors := 0
 	userDetails := []string{}
 
-	defaultDelay := globals.GetRecruitmentCleanupDelay()
+	defaultDelay := globals.GetRecruitmentCleanupDelay(i.GuildID)
 
 	logger.Info(logger.LogData{
 		"action":        "rebuild_recruitment_process_scenarios_command",
@@ -137,7 +139,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 
 		// Calculate expiration time based on when the message was sent
 		messageTime := recruitmentMessage.Timestamp
-		now := time.Now()
+		now := clock.Now()
 
 		// Calculate natural expiration (message time + default delay)
 		naturalExpiration := messageTime.Add(time.Duration(defaultDelay) * 24 * time.Hour)
@@ -153,7 +155,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 		}
 
 		// Check if user already has recruitment_process scenario
-		userMonitoring, err := db.GetUserMonitoring(ctx, userID)
+		userMonitoring, err := db.GetStore().GetUserMonitoring(ctx, userID)
 		if err == nil && userMonitoring != nil && userMonitoring.HasScenario(models.MonitoringScenarioRecruitmentProcess) {
 			logger.Debug(logger.LogData{
 				"action":    "rebuild_recruitment_process_scenarios_command",
@@ -172,6 +174,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 		params := &models.RecruitmentCleanupParams{UserID: userID}
 		scheduledTime := expirationTime.Unix()
 		newTask, err := models.NewTaskWithScenario(
+			i.GuildID,
 			models.TaskRecruitmentCleanup,
 			params,
 			scheduledTime,
@@ -189,7 +192,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 			continue
 		}
 
-		err = db.SaveTaskToRedis(ctx, *newTask)
+		err = db.GetStore().SaveTask(ctx, *newTask)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_recruitment_process_scenarios_command",
@@ -229,7 +232,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 		_ = monitoring.EnsureScenarioWindow(ctx, userID, models.MonitoringScenarioRecruitmentProcess, messageTime, expirationTime)
 
 		// Trigger analytics rebuild for this user using scenario-based monitoring data
-		eventWorker.Submit(userID, func(e eventWorker.Event) {
+		eventWorker.Submit(i.GuildID, userID, func(e eventWorker.Event) {
 			logger.Info(logger.LogData{
 				"trace_id": e.TraceID,
 				"action":   "rebuild_analytics_for_recruitment_process",
@@ -242,7 +245,7 @@ func RebuildRecruitmentProcessScenariosCommand(s *discordgo.Session, i *discordg
 			um.SetStartTime(messageTime)
 			um.SetExpiry(expirationTime)
 
-			result, err := monitoring.RebuildUserAnalytics(e.UserID, um, s, e.TraceID)
+			result, err := monitoring.RebuildUserAnalytics(e.GuildID, e.UserID, um, discord.FromSession(s), e.TraceID)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"trace_id": e.TraceID,
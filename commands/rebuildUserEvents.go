@@ -24,10 +24,10 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 	// Get the user ID from the command options
 	userID := i.ApplicationCommandData().Options[0].UserValue(s).ID
 
-	ctx := context.Background()
+	ctx := db.WithGuild(context.Background(), i.GuildID)
 
 	// Get user monitoring data
-	monitoringData, err := db.GetUserMonitoring(ctx, userID)
+	monitoringData, err := db.GetStore().GetUserMonitoring(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "rebuild_user_events_command",
@@ -41,7 +41,7 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 
 	if monitoringData == nil {
 		// Try to infer scenarios from existing tasks for this user
-		existingTasks, err := db.GetTasksForUser(ctx, userID)
+		existingTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_user_events_command",
@@ -59,7 +59,7 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 		}
 
 		// Backfill new monitoring record
-		monitoringData = models.NewUserMonitoring(userID)
+		monitoringData = models.NewUserMonitoring(i.GuildID, userID)
 
 		scenarioMap := map[models.TaskType]models.MonitoringScenario{
 			models.TaskRecruitmentCleanup: models.MonitoringScenarioRecruitmentProcess,
@@ -82,14 +82,14 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 			monitoringData.ExpiresAt = earliest
 			defaultDays := 7
 			if monitoringData.HasScenario(models.MonitoringScenarioNewRecruit) {
-				defaultDays = globals.GetNewRecruitTrackingDays()
+				defaultDays = globals.GetNewRecruitTrackingDays(i.GuildID)
 			} else if monitoringData.HasScenario(models.MonitoringScenarioRecruitmentProcess) {
-				defaultDays = globals.GetRecruitmentCleanupDelay()
+				defaultDays = globals.GetRecruitmentCleanupDelay(i.GuildID)
 			}
 			monitoringData.StartedAt = time.Unix(earliest, 0).Add(-time.Duration(defaultDays) * 24 * time.Hour).Unix()
 		}
 
-		if err := db.SaveUserMonitoring(ctx, monitoringData); err != nil {
+		if err := db.GetStore().SaveUserMonitoring(ctx, monitoringData); err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_user_events_command",
 				"message": "Failed to save inferred monitoring data",
@@ -100,7 +100,7 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	// Check existing tasks
-	existingTasks, err := db.GetTasksForUser(ctx, userID)
+	existingTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "rebuild_user_events_command",
@@ -144,16 +144,16 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 				// Derive a reasonable start time using scenario defaults (prefer new_recruit if present)
 				defaultDays := 7
 				if monitoringData.HasScenario(models.MonitoringScenarioNewRecruit) {
-					defaultDays = globals.GetNewRecruitTrackingDays()
+					defaultDays = globals.GetNewRecruitTrackingDays(i.GuildID)
 				} else if monitoringData.HasScenario(models.MonitoringScenarioRecruitmentProcess) {
-					defaultDays = globals.GetRecruitmentCleanupDelay()
+					defaultDays = globals.GetRecruitmentCleanupDelay(i.GuildID)
 				}
 				monitoringData.StartedAt = time.Unix(earliest, 0).Add(-time.Duration(defaultDays) * 24 * time.Hour).Unix()
 			}
 		}
 
 		// Persist backfilled monitoring data
-		if err := db.SaveUserMonitoring(ctx, monitoringData); err != nil {
+		if err := db.GetStore().SaveUserMonitoring(ctx, monitoringData); err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_user_events_command",
 				"message": "Failed to save backfilled monitoring data",
@@ -166,7 +166,7 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 	// Remove existing tasks first
 	tasksRemoved := 0
 	for _, task := range existingTasks {
-		err = db.DeleteTaskFromRedis(ctx, task.TaskID)
+		err = db.GetStore().DeleteTask(ctx, task.TaskID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "rebuild_user_events_command",
@@ -181,7 +181,7 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	// Recreate tasks using the monitoring system's recreation logic
-	err = monitoring.RecreateTasksForUser(userID, monitoringData)
+	err = monitoring.RecreateTasksForUser(i.GuildID, userID, monitoringData)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "rebuild_user_events_command",
@@ -194,7 +194,7 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	// Get the new tasks to show what was created
-	newTasks, err := db.GetTasksForUser(ctx, userID)
+	newTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "rebuild_user_events_command",
@@ -207,7 +207,7 @@ func RebuildUserEventsCommand(s *discordgo.Session, i *discordgo.InteractionCrea
 	}
 
 	// Get updated monitoring data to check for removed scenarios
-	updatedMonitoringData, err := db.GetUserMonitoring(ctx, userID)
+	updatedMonitoringData, err := db.GetStore().GetUserMonitoring(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "rebuild_user_events_command",
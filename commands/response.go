@@ -2,6 +2,8 @@ package commands
 
 import (
 	"astralHRBot/logger"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"astralHRBot/workers/eventWorker"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -20,14 +22,17 @@ func RespondToInteraction(s *discordgo.Session, i *discordgo.InteractionCreate,
 		response.Data.Flags = 0
 	}
 
-	err := s.InteractionRespond(i.Interaction, response)
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "slash_command_error",
-			"message": "Failed to respond to interaction",
-			"error":   err.Error(),
-		})
-	}
+	discordAPIWorker.NewRequest(interactionEvent(i), func() error {
+		err := s.InteractionRespond(i.Interaction, response)
+		if err != nil {
+			logger.Error(logger.LogData{
+				"action":  "slash_command_error",
+				"message": "Failed to respond to interaction",
+				"error":   err.Error(),
+			})
+		}
+		return err
+	}, interactionRequest(i)...)
 }
 
 // RespondToInteractionWithEmbed responds to an interaction with an embed
@@ -44,14 +49,17 @@ func RespondToInteractionWithEmbed(s *discordgo.Session, i *discordgo.Interactio
 		response.Data.Flags = 0
 	}
 
-	err := s.InteractionRespond(i.Interaction, response)
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "slash_command_error",
-			"message": "Failed to respond to interaction with embed",
-			"error":   err.Error(),
-		})
-	}
+	discordAPIWorker.NewRequest(interactionEvent(i), func() error {
+		err := s.InteractionRespond(i.Interaction, response)
+		if err != nil {
+			logger.Error(logger.LogData{
+				"action":  "slash_command_error",
+				"message": "Failed to respond to interaction with embed",
+				"error":   err.Error(),
+			})
+		}
+		return err
+	}, interactionRequest(i)...)
 }
 
 // FollowUpMessage sends a follow-up message
@@ -61,15 +69,38 @@ func FollowUpMessage(s *discordgo.Session, i *discordgo.InteractionCreate, conte
 		flags = discordgo.MessageFlagsEphemeral
 	}
 
-	_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-		Content: content,
-		Flags:   flags,
-	})
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "slash_command_error",
-			"message": "Failed to send follow-up message",
-			"error":   err.Error(),
+	discordAPIWorker.NewRequest(interactionEvent(i), func() error {
+		_, err := s.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: content,
+			Flags:   flags,
 		})
+		if err != nil {
+			logger.Error(logger.LogData{
+				"action":  "slash_command_error",
+				"message": "Failed to send follow-up message",
+				"error":   err.Error(),
+			})
+		}
+		return err
+	}, interactionRequest(i)...)
+}
+
+// interactionEvent identifies an interaction's Discord requests in the logs
+func interactionEvent(i *discordgo.InteractionCreate) eventWorker.Event {
+	e := eventWorker.Event{TraceID: i.ID}
+	if i.Member != nil && i.Member.User != nil {
+		e.UserID = i.Member.User.ID
+	} else if i.User != nil {
+		e.UserID = i.User.ID
+	}
+	return e
+}
+
+// interactionRequest puts replies in the interactive lane, ordered so a follow-up never
+// overtakes the initial response
+func interactionRequest(i *discordgo.InteractionCreate) []discordAPIWorker.RequestOption {
+	return []discordAPIWorker.RequestOption{
+		discordAPIWorker.WithRoute(discordAPIWorker.InteractionRoute(i.Interaction)),
+		discordAPIWorker.WithPriority(discordAPIWorker.PriorityInteractive),
 	}
 }
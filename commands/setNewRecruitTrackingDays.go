@@ -1,8 +1,11 @@
 package commands
 
 import (
+	"astralHRBot/config"
 	"astralHRBot/globals"
 	"astralHRBot/logger"
+	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/bwmarrin/discordgo"
@@ -23,20 +26,16 @@ func SetNewRecruitTrackingDaysCommand(s *discordgo.Session, i *discordgo.Interac
 		return
 	}
 
-	daysStr := options[0].StringValue()
-	days, err := strconv.Atoi(daysStr)
-	if err != nil {
-		RespondToInteraction(s, i, "Please provide a valid number of days", true)
-		return
-	}
-
+	days := int(options[0].IntValue())
 	if days < 1 {
 		RespondToInteraction(s, i, "New recruit tracking days must be at least 1 day", true)
 		return
 	}
 
-	// Update the global setting safely
-	globals.SetNewRecruitTrackingDays(days)
+	if _, err := config.Set(context.Background(), i.GuildID, globals.NewRecruitTrackingDays, strconv.Itoa(days), i.Member.User.ID); err != nil {
+		RespondToInteraction(s, i, fmt.Sprintf("Failed to save setting: %s", err.Error()), true)
+		return
+	}
 
 	content := "New recruit tracking days has been set to " + strconv.Itoa(days) + " days"
 	RespondToInteraction(s, i, content, true)
@@ -1,8 +1,11 @@
 package commands
 
 import (
+	"astralHRBot/config"
 	"astralHRBot/globals"
 	"astralHRBot/logger"
+	"context"
+	"fmt"
 	"strconv"
 
 	"github.com/bwmarrin/discordgo"
@@ -23,20 +26,16 @@ func SetRecruitmentCleanupDelayCommand(s *discordgo.Session, i *discordgo.Intera
 		return
 	}
 
-	daysStr := options[0].StringValue()
-	days, err := strconv.Atoi(daysStr)
-	if err != nil {
-		RespondToInteraction(s, i, "Please provide a valid number of days", true)
-		return
-	}
-
+	days := int(options[0].IntValue())
 	if days < 1 {
 		RespondToInteraction(s, i, "Recruitment cleanup delay must be at least 1 day", true)
 		return
 	}
 
-	// Update the global setting safely
-	globals.SetRecruitmentCleanupDelay(days)
+	if _, err := config.Set(context.Background(), i.GuildID, globals.RecruitmentCleanupDelay, strconv.Itoa(days), i.Member.User.ID); err != nil {
+		RespondToInteraction(s, i, fmt.Sprintf("Failed to save setting: %s", err.Error()), true)
+		return
+	}
 
 	content := "Recruitment cleanup delay has been set to " + strconv.Itoa(days) + " days"
 	RespondToInteraction(s, i, content, true)
@@ -2,12 +2,25 @@ package commands
 
 import (
 	"astralHRBot/logger"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // SlashCommandHandlers handles all slash command interactions
 func SlashCommandHandlers(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+	case discordgo.InteractionApplicationCommandAutocomplete:
+		handleAutocomplete(s, i)
+		return
+	case discordgo.InteractionModalSubmit:
+		handleModalSubmit(s, i)
+		return
+	default:
+		return
+	}
+
 	logger.Debug(logger.LogData{
 		"action":     "slash_command_handler",
 		"message":    "Received slash command",
@@ -34,3 +47,38 @@ func SlashCommandHandlers(s *discordgo.Session, i *discordgo.InteractionCreate)
 	// Execute the command handler
 	handler(s, i)
 }
+
+// handleAutocomplete routes autocomplete requests to the handler for their command
+func handleAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	commandName := i.ApplicationCommandData().Name
+
+	handler, exists := autocompleteHandlers[commandName]
+	if !exists {
+		logger.Debug(logger.LogData{
+			"action":  "slash_command_autocomplete",
+			"message": "No autocomplete handler for command",
+			"command": commandName,
+		})
+		return
+	}
+
+	handler(s, i)
+}
+
+// handleModalSubmit routes submitted modals to the handler registered for their custom ID prefix
+func handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.ModalSubmitData().CustomID
+
+	for prefix, handler := range modalHandlers {
+		if strings.HasPrefix(customID, prefix) {
+			handler(s, i)
+			return
+		}
+	}
+
+	logger.Error(logger.LogData{
+		"action":    "modal_submit_error",
+		"message":   "Unknown modal",
+		"custom_id": customID,
+	})
+}
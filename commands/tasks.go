@@ -0,0 +1,402 @@
+package commands
+
+import (
+	"astralHRBot/clock"
+	"astralHRBot/db"
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// taskTimeLayout is the format used to show and accept task times (UTC)
+	taskTimeLayout = "2006-01-02 15:04"
+	// taskListMaxLength keeps /tasks list replies under Discord's 2000 character limit
+	taskListMaxLength = 1800
+	// taskAuditShown is the number of audit entries shown by /tasks history
+	taskAuditShown = 15
+	// maxAutocompleteChoices is the most choices Discord accepts in an autocomplete reply
+	maxAutocompleteChoices = 25
+)
+
+// TasksCommand handles the /tasks slash command
+func TasksCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger.Debug(logger.LogData{
+		"action":  "tasks_command",
+		"message": "Tasks command executed",
+		"user_id": i.Member.User.ID,
+	})
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		RespondToInteraction(s, i, "Please choose a subcommand", true)
+		return
+	}
+
+	subcommand := options[0]
+	ctx := db.WithGuild(context.Background(), i.GuildID)
+
+	switch subcommand.Name {
+	case "list":
+		listTasks(ctx, s, i, subcommand.Options)
+	case "reschedule":
+		rescheduleTask(ctx, s, i, subcommand.Options)
+	case "run-now":
+		taskID := getStringOption(subcommand.Options, "task_id")
+		task, err := db.GetStore().RunTaskNow(ctx, taskID, i.Member.User.ID)
+		if err != nil {
+			RespondToInteraction(s, i, fmt.Sprintf("Failed to run task: %s", err.Error()), true)
+			return
+		}
+		RespondToInteraction(s, i, fmt.Sprintf("▶️ Task `%s` is due now and will run within a few seconds", task.TaskID), true)
+	case "cancel":
+		taskID := getStringOption(subcommand.Options, "task_id")
+		task, err := db.GetStore().CancelTask(ctx, taskID, i.Member.User.ID)
+		if err != nil {
+			RespondToInteraction(s, i, fmt.Sprintf("Failed to cancel task: %s", err.Error()), true)
+			return
+		}
+		RespondToInteraction(s, i, fmt.Sprintf("🗑️ Task `%s` has been cancelled", task.TaskID), true)
+	case "history":
+		showTaskHistory(ctx, s, i)
+	}
+}
+
+func listTasks(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	// Read the optional filters
+	taskType := getStringOption(options, "type")
+	scenario := getStringOption(options, "scenario")
+	userID := ""
+	var dueBefore int64
+	for _, opt := range options {
+		switch opt.Name {
+		case "user":
+			userID = opt.UserValue(nil).ID
+		case "due_within_hours":
+			dueBefore = clock.Now().Add(time.Duration(opt.IntValue()) * time.Hour).Unix()
+		}
+	}
+
+	// Use the user or scenario index when possible instead of loading every task
+	var tasks []models.Task
+	var err error
+	switch {
+	case userID != "":
+		tasks, err = db.GetStore().GetTasksForUser(ctx, userID)
+	case scenario != "":
+		tasks, err = db.GetStore().GetTasksForScenario(ctx, scenario)
+	default:
+		tasks, err = db.GetStore().FetchAllTasks(ctx)
+	}
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "tasks_command",
+			"message": "Failed to fetch tasks",
+			"error":   err.Error(),
+		})
+		RespondToInteraction(s, i, "Error retrieving tasks", true)
+		return
+	}
+
+	var filtered []models.Task
+	for _, task := range tasks {
+		if taskType != "" && string(task.FunctionName) != taskType {
+			continue
+		}
+		if scenario != "" && !task.IsForScenario(scenario) {
+			continue
+		}
+		if userID != "" && !task.IsForUser(userID) {
+			continue
+		}
+		if dueBefore > 0 && task.ScheduledTime > dueBefore {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+
+	if len(filtered) == 0 {
+		RespondToInteraction(s, i, "No tasks match those filters", true)
+		return
+	}
+
+	sort.Slice(filtered, func(a, b int) bool {
+		return filtered[a].ScheduledTime < filtered[b].ScheduledTime
+	})
+
+	response := fmt.Sprintf("📋 **Scheduled tasks (%d)**\n\n", len(filtered))
+	for n, task := range filtered {
+		entry := fmt.Sprintf("**%s** `%s`\n", string(task.FunctionName), task.TaskID)
+		entry += fmt.Sprintf("• Status: `%s` • Due: `%s`", task.Status, formatTaskTime(task.ScheduledTime))
+		if task.Scenario != "" {
+			entry += fmt.Sprintf(" • Scenario: `%s`", task.Scenario)
+		}
+		entry += "\n"
+
+		if len(response)+len(entry) > taskListMaxLength {
+			response += fmt.Sprintf("\n…and %d more. Narrow the filters to see them.", len(filtered)-n)
+			break
+		}
+		response += entry
+	}
+
+	RespondToInteraction(s, i, response, true)
+}
+
+func rescheduleTask(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	taskID := getStringOption(options, "task_id")
+
+	runAt, err := parseTaskTime(getStringOption(options, "when"), clock.Now())
+	if err != nil {
+		RespondToInteraction(s, i, err.Error(), true)
+		return
+	}
+
+	task, err := db.GetStore().RescheduleTask(ctx, taskID, runAt, i.Member.User.ID)
+	if err != nil {
+		RespondToInteraction(s, i, fmt.Sprintf("Failed to reschedule task: %s", err.Error()), true)
+		return
+	}
+
+	RespondToInteraction(s, i, fmt.Sprintf("🕒 Task `%s` now runs at `%s` UTC", task.TaskID, formatTaskTime(task.ScheduledTime)), true)
+}
+
+func showTaskHistory(ctx context.Context, s *discordgo.Session, i *discordgo.InteractionCreate) {
+	entries, err := db.GetStore().GetTaskAuditLog(ctx, taskAuditShown)
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "tasks_command",
+			"message": "Failed to get task audit log",
+			"error":   err.Error(),
+		})
+		RespondToInteraction(s, i, "Error retrieving task history", true)
+		return
+	}
+
+	if len(entries) == 0 {
+		RespondToInteraction(s, i, "No manual task changes have been recorded", true)
+		return
+	}
+
+	response := "📜 **Recent task changes**\n\n"
+	for _, entry := range entries {
+		response += fmt.Sprintf("`%s` <@%s> **%s** `%s`", formatTaskTime(entry.Timestamp), entry.Actor, string(entry.Action), entry.TaskID)
+		if entry.NewTime > 0 {
+			response += fmt.Sprintf(" → `%s`", formatTaskTime(entry.NewTime))
+		}
+		response += "\n"
+	}
+
+	RespondToInteraction(s, i, response, true)
+}
+
+// TasksAutocomplete suggests queued task IDs for the /tasks task_id option
+func TasksAutocomplete(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var typed string
+	options := i.ApplicationCommandData().Options
+	if len(options) > 0 {
+		for _, opt := range options[0].Options {
+			if opt.Focused {
+				typed = strings.ToLower(opt.StringValue())
+			}
+		}
+	}
+
+	choices := []*discordgo.ApplicationCommandOptionChoice{}
+
+	tasks, err := db.GetStore().FetchAllTasks(db.WithGuild(context.Background(), i.GuildID))
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "tasks_autocomplete",
+			"message": "Failed to fetch tasks",
+			"error":   err.Error(),
+		})
+	}
+
+	sort.Slice(tasks, func(a, b int) bool {
+		return tasks[a].ScheduledTime < tasks[b].ScheduledTime
+	})
+
+	for _, task := range tasks {
+		// Only tasks still waiting in the queue can be changed
+		if task.Status != models.TaskStatusPending {
+			continue
+		}
+		if typed != "" && !strings.Contains(strings.ToLower(task.TaskID), typed) {
+			continue
+		}
+
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s (due %s)", task.TaskID, formatTaskTime(task.ScheduledTime)),
+			Value: task.TaskID,
+		})
+		if len(choices) == maxAutocompleteChoices {
+			break
+		}
+	}
+
+	discordAPIWorker.NewRequest(interactionEvent(i), func() error {
+		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionApplicationCommandAutocompleteResult,
+			Data: &discordgo.InteractionResponseData{
+				Choices: choices,
+			},
+		})
+		if err != nil {
+			logger.Error(logger.LogData{
+				"action":  "tasks_autocomplete",
+				"message": "Failed to respond to autocomplete",
+				"error":   err.Error(),
+			})
+		}
+		return err
+	}, interactionRequest(i)...)
+}
+
+// getStringOption returns the value of a named string option, or "" when it was not given
+func getStringOption(options []*discordgo.ApplicationCommandInteractionDataOption, name string) string {
+	for _, opt := range options {
+		if opt.Name == name {
+			return opt.StringValue()
+		}
+	}
+	return ""
+}
+
+// parseTaskTime accepts either an absolute UTC time ("2006-01-02 15:04") or an offset from now
+// such as "90m", "36h" or "3d"
+func parseTaskTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+
+	if t, err := time.ParseInLocation(taskTimeLayout, value, time.UTC); err == nil {
+		if t.Before(now) {
+			return time.Time{}, fmt.Errorf("`%s` is in the past", value)
+		}
+		return t, nil
+	}
+
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return time.Time{}, fmt.Errorf("`%s` is not a valid number of days", value)
+		}
+		return now.Add(time.Duration(n) * 24 * time.Hour), nil
+	}
+
+	offset, err := time.ParseDuration(value)
+	if err != nil || offset < 0 {
+		return time.Time{}, fmt.Errorf("Please give a time as `YYYY-MM-DD HH:MM` (UTC) or an offset like `36h` or `3d`")
+	}
+	return now.Add(offset), nil
+}
+
+func formatTaskTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(taskTimeLayout)
+}
+
+// GetTasksCommandDefinition returns the tasks command definition
+func GetTasksCommandDefinition() *discordgo.ApplicationCommand {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	typeChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(models.TaskTypes))
+	for _, taskType := range models.TaskTypes {
+		typeChoices = append(typeChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  string(taskType),
+			Value: string(taskType),
+		})
+	}
+
+	taskIDOption := func(description string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:         discordgo.ApplicationCommandOptionString,
+			Name:         "task_id",
+			Description:  description,
+			Required:     true,
+			Autocomplete: true,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "tasks",
+		Description:              "List and manage scheduled tasks",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "List scheduled tasks",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "type",
+						Description: "Only show tasks of this type",
+						Choices:     typeChoices,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "Only show tasks for this user",
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "scenario",
+						Description: "Only show tasks created by this scenario",
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "New Recruit", Value: string(models.MonitoringScenarioNewRecruit)},
+							{Name: "Recruitment Process", Value: string(models.MonitoringScenarioRecruitmentProcess)},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "due_within_hours",
+						Description: "Only show tasks due within this many hours (includes overdue tasks)",
+						MinValue:    &[]float64{1}[0],
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reschedule",
+				Description: "Move a scheduled task to a new time",
+				Options: []*discordgo.ApplicationCommandOption{
+					taskIDOption("The task to reschedule"),
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "when",
+						Description: "New time as YYYY-MM-DD HH:MM (UTC) or an offset from now like 36h or 3d",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "run-now",
+				Description: "Run a scheduled task immediately",
+				Options: []*discordgo.ApplicationCommandOption{
+					taskIDOption("The task to run"),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "cancel",
+				Description: "Cancel a scheduled task",
+				Options: []*discordgo.ApplicationCommandOption{
+					taskIDOption("The task to cancel"),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "history",
+				Description: "Show recent manual changes to scheduled tasks",
+			},
+		},
+	}
+}
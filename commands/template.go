@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"astralHRBot/config"
+	"astralHRBot/helper"
+	"astralHRBot/logger"
+	"astralHRBot/templates"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// templateEditModal prefixes the custom ID of the /template edit modal; the template name follows
+	templateEditModal = "template_edit:"
+	// templateTextInput is the custom ID of the modal's text box
+	templateTextInput = "text"
+	// templateMaxLength is Discord's limit on message length
+	templateMaxLength = templates.MaxLength
+	// previewMaxLength is Discord's limit on an embed description, which previews are shown in
+	// because a template can expand past templateMaxLength once its placeholders are filled in
+	previewMaxLength = 4096
+)
+
+// TemplateCommand handles the /template slash command
+func TemplateCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	logger.Debug(logger.LogData{
+		"action":  "template_command",
+		"message": "Template command executed",
+		"user_id": i.Member.User.ID,
+	})
+
+	options := i.ApplicationCommandData().Options
+	if len(options) == 0 {
+		RespondToInteraction(s, i, "Please choose a subcommand", true)
+		return
+	}
+
+	subcommand := options[0]
+	name := templates.Name(getStringOption(subcommand.Options, "name"))
+	if templates.Default(name) == "" {
+		RespondToInteraction(s, i, fmt.Sprintf("Unknown template `%s`", name), true)
+		return
+	}
+
+	switch subcommand.Name {
+	case "edit":
+		showTemplateEditor(s, i, name)
+	case "preview":
+		previewTemplate(s, i, name, subcommand.Options)
+	case "reset":
+		if _, err := config.Set(context.Background(), i.GuildID, templates.SettingKey(name), templates.Default(name), i.Member.User.ID); err != nil {
+			RespondToInteraction(s, i, fmt.Sprintf("Failed to reset template: %s", err.Error()), true)
+			return
+		}
+		RespondToInteraction(s, i, fmt.Sprintf("↩️ Template `%s` has been reset to the default", name), true)
+	}
+}
+
+// showTemplateEditor opens a modal holding the template's current text
+func showTemplateEditor(s *discordgo.Session, i *discordgo.InteractionCreate, name templates.Name) {
+	response := &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: templateEditModal + string(name),
+			Title:    "Edit " + string(name),
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    templateTextInput,
+							Label:       "Template",
+							Style:       discordgo.TextInputParagraph,
+							Value:       config.Get(i.GuildID, templates.SettingKey(name)),
+							Placeholder: `{{.Mention}} {{.DisplayName}} {{.Days}} {{channel "HR_CHANNEL_ID"}}`,
+							Required:    true,
+							MaxLength:   templateMaxLength,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	discordAPIWorker.NewRequest(interactionEvent(i), func() error {
+		err := s.InteractionRespond(i.Interaction, response)
+		if err != nil {
+			logger.Error(logger.LogData{
+				"action":   "template_command",
+				"message":  "Failed to open template editor",
+				"template": string(name),
+				"error":    err.Error(),
+			})
+		}
+		return err
+	}, interactionRequest(i)...)
+}
+
+// TemplateEditSubmit saves the text entered in the /template edit modal
+func TemplateEditSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	name := templates.Name(strings.TrimPrefix(data.CustomID, templateEditModal))
+	if templates.Default(name) == "" {
+		RespondToInteraction(s, i, fmt.Sprintf("Unknown template `%s`", name), true)
+		return
+	}
+
+	text, found := modalTextInput(data, templateTextInput)
+	if !found {
+		RespondToInteraction(s, i, "The template text was missing from the form", true)
+		return
+	}
+
+	if _, err := config.Set(context.Background(), i.GuildID, templates.SettingKey(name), text, i.Member.User.ID); err != nil {
+		RespondToInteraction(s, i, fmt.Sprintf("Failed to save template: %s", err.Error()), true)
+		return
+	}
+
+	preview := templates.Render(name, templates.Data{
+		GuildID:     i.GuildID,
+		UserID:      i.Member.User.ID,
+		DisplayName: i.Member.DisplayName(),
+	})
+	RespondToInteractionWithEmbed(s, i, previewEmbed(fmt.Sprintf("✅ Template %s saved", name), preview), true)
+}
+
+// previewTemplate renders a template as it would be sent to the chosen member
+func previewTemplate(s *discordgo.Session, i *discordgo.InteractionCreate, name templates.Name, options []*discordgo.ApplicationCommandInteractionDataOption) {
+	var userID string
+	for _, opt := range options {
+		if opt.Name == "member" {
+			userID = opt.UserValue(nil).ID
+		}
+	}
+
+	data := templates.Data{GuildID: i.GuildID, UserID: userID}
+	resolved := i.ApplicationCommandData().Resolved
+	if resolved != nil {
+		user := resolved.Users[userID]
+		if member := resolved.Members[userID]; member != nil && user != nil {
+			member.User = user
+			data.DisplayName = member.DisplayName()
+		} else if user != nil {
+			data.DisplayName = helper.GetDisplayName(user)
+		}
+	}
+
+	RespondToInteractionWithEmbed(s, i, previewEmbed(fmt.Sprintf("Preview of %s", name), templates.Render(name, data)), true)
+}
+
+// previewEmbed shows rendered template text, cut short if it is too long for an embed
+func previewEmbed(title, text string) *discordgo.MessageEmbed {
+	if runes := []rune(text); len(runes) > previewMaxLength {
+		text = string(runes[:previewMaxLength-1]) + "…"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: text,
+		Color:       0x000000,
+	}
+}
+
+// modalTextInput finds a text box in a submitted modal by its custom ID
+func modalTextInput(data discordgo.ModalSubmitInteractionData, customID string) (string, bool) {
+	for _, component := range data.Components {
+		row, ok := component.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, inner := range row.Components {
+			if input, ok := inner.(*discordgo.TextInput); ok && input.CustomID == customID {
+				return input.Value, true
+			}
+		}
+	}
+	return "", false
+}
+
+// GetTemplateCommandDefinition returns the template command definition
+func GetTemplateCommandDefinition() *discordgo.ApplicationCommand {
+	adminPerm := int64(discordgo.PermissionAdministrator)
+
+	nameChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(templates.Names()))
+	for _, name := range templates.Names() {
+		nameChoices = append(nameChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  string(name),
+			Value: string(name),
+		})
+	}
+
+	nameOption := func(description string) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        "name",
+			Description: description,
+			Required:    true,
+			Choices:     nameChoices,
+		}
+	}
+
+	return &discordgo.ApplicationCommand{
+		Name:                     "template",
+		Description:              "Edit and preview the messages the bot sends",
+		DefaultMemberPermissions: &adminPerm,
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "edit",
+				Description: "Edit a message template",
+				Options: []*discordgo.ApplicationCommandOption{
+					nameOption("Template to edit"),
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "preview",
+				Description: "Show a message template as it would be sent to a member",
+				Options: []*discordgo.ApplicationCommandOption{
+					nameOption("Template to preview"),
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "member",
+						Description: "Member to fill the template in for",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "reset",
+				Description: "Restore a message template to its default text",
+				Options: []*discordgo.ApplicationCommandOption{
+					nameOption("Template to reset"),
+				},
+			},
+		},
+	}
+}
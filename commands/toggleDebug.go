@@ -1,8 +1,11 @@
 package commands
 
 import (
-	"astralHRBot/globals"
+	"astralHRBot/config"
 	"astralHRBot/logger"
+	"context"
+	"fmt"
+	"log/slog"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -15,8 +18,17 @@ func ToggleDebugCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 		"user_id": i.Member.User.ID,
 	})
 
-	currentMode := globals.GetDebugMode()
-	globals.SetDebugMode(!currentMode)
+	currentMode := logger.GetLevel() <= slog.LevelDebug
+	newLevel := slog.LevelDebug
+	if currentMode {
+		newLevel = slog.LevelInfo
+	}
+
+	// Saving the level applies it and keeps it across restarts
+	if _, err := config.Set(context.Background(), i.GuildID, config.LogLevel, logger.LevelName(newLevel), i.Member.User.ID); err != nil {
+		RespondToInteraction(s, i, fmt.Sprintf("Failed to save debug mode: %s", err.Error()), true)
+		return
+	}
 
 	content := ""
 	if !currentMode {
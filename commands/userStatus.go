@@ -21,10 +21,10 @@ func UserStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	// Get the user ID from the command options
 	userID := i.ApplicationCommandData().Options[0].UserValue(s).ID
 
-	ctx := context.Background()
+	ctx := db.WithGuild(context.Background(), i.GuildID)
 
 	// Get user monitoring data
-	monitoring, err := db.GetUserMonitoring(ctx, userID)
+	monitoring, err := db.GetStore().GetUserMonitoring(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "user_status_command",
@@ -37,7 +37,7 @@ func UserStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	// Get user tasks
-	tasks, err := db.GetTasksForUser(ctx, userID)
+	tasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "user_status_command",
@@ -143,7 +143,7 @@ func UserStatusCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
 
 	// Add analytics if monitoring is active
 	if monitoring != nil && !monitoring.IsExpired() {
-		analytics, err := db.GetUserAnalytics(ctx, userID)
+		analytics, err := db.GetStore().GetUserAnalytics(ctx, userID)
 		if err == nil {
 			analyticsInfo := fmt.Sprintf("• Messages: `%d`\n", analytics.Messages)
 			analyticsInfo += fmt.Sprintf("• Voice Joins: `%d`\n", analytics.VoiceJoins)
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"astralHRBot/clock"
+	"astralHRBot/db"
+	"astralHRBot/guilds"
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+var (
+	mu       sync.RWMutex
+	settings = make(map[string]Setting)
+	values   map[string]map[string]string // guild ID -> key -> value; nil until Load has run
+
+	listenersMu sync.RWMutex
+	listeners   []func(models.ConfigChange)
+)
+
+// Register adds settings that can be read and changed at runtime. Packages that own settings
+// register them from init so they are known before Load seeds the store.
+func Register(newSettings ...Setting) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, setting := range newSettings {
+		settings[setting.Key] = setting
+	}
+}
+
+// Lookup returns a registered setting
+func Lookup(key string) (Setting, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	setting, exists := settings[key]
+	return setting, exists
+}
+
+// Settings returns every registered setting ordered by key
+func Settings() []Setting {
+	mu.RLock()
+	list := make([]Setting, 0, len(settings))
+	for _, setting := range settings {
+		list = append(list, setting)
+	}
+	mu.RUnlock()
+
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].Key < list[b].Key
+	})
+	return list
+}
+
+// Load reads every guild's stored values into memory. Registered settings that are set in the
+// environment but not yet stored are first copied to the home guild, so the first boot picks up
+// the existing deployment; other guilds start from the defaults and are set up with /config set.
+func Load(ctx context.Context) error {
+	if len(guilds.IDs()) == 0 {
+		return errors.New("GUILD_IDS or GUILD_ID must be set")
+	}
+
+	loaded := make(map[string]map[string]string)
+	for _, guildID := range guilds.IDs() {
+		guildCtx := db.WithGuild(ctx, guildID)
+
+		if guildID == guilds.Home() {
+			seed := make(map[string]string)
+			for _, setting := range Settings() {
+				if value, exists := os.LookupEnv(setting.Key); exists && value != "" {
+					seed[setting.Key] = value
+				}
+			}
+
+			seeded, err := db.GetStore().SeedConfigValues(guildCtx, seed)
+			if err != nil {
+				return err
+			}
+			if len(seeded) > 0 {
+				sort.Strings(seeded)
+				logger.Info(logger.LogData{
+					"action":   "config_load",
+					"message":  "Seeded configuration from the environment",
+					"guild_id": guildID,
+					"keys":     seeded,
+				})
+			}
+		}
+
+		stored, err := db.GetStore().GetConfigValues(guildCtx)
+		if err != nil {
+			return err
+		}
+		loaded[guildID] = stored
+
+		logger.Info(logger.LogData{
+			"action":   "config_load",
+			"message":  "Configuration loaded",
+			"guild_id": guildID,
+			"count":    len(stored),
+		})
+	}
+
+	mu.Lock()
+	values = loaded
+	mu.Unlock()
+
+	for _, setting := range Settings() {
+		if value, exists := loaded[guilds.Home()][setting.Key]; exists && setting.Apply != nil {
+			setting.Apply(value)
+		}
+	}
+	return nil
+}
+
+// settingGuild is the guild a setting is stored with: the home guild for global settings and
+// when no guild is given, otherwise guildID
+func settingGuild(setting Setting, guildID string) string {
+	if setting.Global || guildID == "" {
+		return guilds.Home()
+	}
+	return guildID
+}
+
+// Get returns a setting's value in a guild, or in the home guild if guildID is empty. Before Load
+// has run, or for a setting that has never been stored, the home guild falls back to the
+// environment variable of the same name; every guild then falls back to the setting's default.
+func Get(guildID, key string) string {
+	mu.RLock()
+	setting := settings[key]
+	guildID = settingGuild(setting, guildID)
+	value, exists := values[guildID][key]
+	mu.RUnlock()
+	if exists {
+		return value
+	}
+	if guildID == guilds.Home() {
+		if value, exists := os.LookupEnv(key); exists && value != "" {
+			return value
+		}
+	}
+	return setting.Default
+}
+
+// Int returns a numeric setting in a guild, falling back to its default if the value cannot be read
+func Int(guildID, key string) int {
+	value := Get(guildID, key)
+	if n, err := strconv.Atoi(value); err == nil {
+		return n
+	}
+
+	setting, _ := Lookup(key)
+	logger.Warn(logger.LogData{
+		"action":   "config_invalid",
+		"message":  "Setting is not a number, using the default",
+		"guild_id": guildID,
+		"key":      key,
+		"value":    value,
+	})
+	n, _ := strconv.Atoi(setting.Default)
+	return n
+}
+
+// RoleID returns the role ID stored under key in a guild, warning if it is not configured
+func RoleID(guildID, key string) string {
+	return getRequired(guildID, key, KindRole)
+}
+
+// ChannelID returns the channel ID stored under key in a guild, warning if it is not configured
+func ChannelID(guildID, key string) string {
+	return getRequired(guildID, key, KindChannel)
+}
+
+func getRequired(guildID, key string, kind Kind) string {
+	value := Get(guildID, key)
+	if value == "" {
+		logger.Warn(logger.LogData{
+			"action":   "config_missing",
+			"message":  fmt.Sprintf("No %s configured", kind),
+			"guild_id": guildID,
+			"key":      key,
+		})
+	}
+	return value
+}
+
+// Set validates and stores a new value for a registered setting in a guild, records who changed
+// it, then applies it and notifies listeners. Global settings are always stored with the home guild.
+func Set(ctx context.Context, guildID, key, value, actor string) (models.ConfigChange, error) {
+	setting, exists := Lookup(key)
+	if !exists {
+		return models.ConfigChange{}, fmt.Errorf("unknown setting %q", key)
+	}
+	if err := setting.Kind.Validate(value); err != nil {
+		return models.ConfigChange{}, fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	if setting.Validate != nil {
+		if err := setting.Validate(value); err != nil {
+			return models.ConfigChange{}, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+
+	guildID = settingGuild(setting, guildID)
+	change := models.ConfigChange{
+		GuildID:   guildID,
+		Key:       key,
+		OldValue:  Get(guildID, key),
+		NewValue:  value,
+		Actor:     actor,
+		Timestamp: clock.Now().Unix(),
+	}
+	if err := db.GetStore().SetConfigValue(db.WithGuild(ctx, guildID), change); err != nil {
+		return models.ConfigChange{}, err
+	}
+
+	mu.Lock()
+	if values == nil {
+		values = make(map[string]map[string]string)
+	}
+	if values[guildID] == nil {
+		values[guildID] = make(map[string]string)
+	}
+	values[guildID][key] = value
+	mu.Unlock()
+
+	logger.Info(logger.LogData{
+		"action":    "config_set",
+		"message":   "Setting changed",
+		"guild_id":  guildID,
+		"key":       key,
+		"old_value": change.OldValue,
+		"new_value": change.NewValue,
+		"actor":     actor,
+	})
+	if setting.Apply != nil {
+		setting.Apply(value)
+	}
+	notify(change)
+	return change, nil
+}
+
+// History returns up to limit of the most recent setting changes in a guild, newest first. Changes
+// to global settings are in the home guild's history.
+func History(ctx context.Context, guildID string, limit int64) ([]models.ConfigChange, error) {
+	return db.GetStore().GetConfigHistory(db.WithGuild(ctx, settingGuild(Setting{}, guildID)), limit)
+}
+
+// OnChange calls fn after every successful Set
+func OnChange(fn func(models.ConfigChange)) {
+	listenersMu.Lock()
+	listeners = append(listeners, fn)
+	listenersMu.Unlock()
+}
+
+func notify(change models.ConfigChange) {
+	listenersMu.RLock()
+	defer listenersMu.RUnlock()
+	for _, fn := range listeners {
+		fn(change)
+	}
+}
@@ -0,0 +1,20 @@
+package config
+
+import "astralHRBot/logger"
+
+// LogLevel is the logger's threshold, kept here so /toggle-debug-mode survives restarts
+const LogLevel = "LOG_LEVEL"
+
+func init() {
+	Register(Setting{
+		Key:         LogLevel,
+		Kind:        KindLogLevel,
+		Description: "Lowest level of log message written",
+		Global:      true,
+		Apply: func(value string) {
+			if level, err := logger.ParseLevel(value); err == nil {
+				logger.SetLevel(level)
+			}
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"astralHRBot/logger"
+	"fmt"
+	"strconv"
+)
+
+// Kind is the type of value a setting holds
+type Kind int
+
+const (
+	KindRole Kind = iota
+	KindChannel
+	// KindDays is a whole number of days, at least 1
+	KindDays
+	// KindLogLevel is a level name such as INFO or DEBUG
+	KindLogLevel
+	// KindText is free text such as a URL
+	KindText
+	// KindTemplate is a message template, checked by the setting's own Validate
+	KindTemplate
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindRole:
+		return "role"
+	case KindChannel:
+		return "channel"
+	case KindDays:
+		return "days"
+	case KindLogLevel:
+		return "log level"
+	case KindText:
+		return "text"
+	case KindTemplate:
+		return "template"
+	}
+	return "unknown"
+}
+
+// Validate checks that value is acceptable for a setting of this kind
+func (k Kind) Validate(value string) error {
+	switch k {
+	case KindRole, KindChannel:
+		if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+			return fmt.Errorf("%q is not a Discord %s ID", value, k)
+		}
+	case KindDays:
+		if days, err := strconv.Atoi(value); err != nil || days < 1 {
+			return fmt.Errorf("%q is not a whole number of days of at least 1", value)
+		}
+	case KindLogLevel:
+		if _, err := logger.ParseLevel(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Setting is a value that can be read and changed at runtime. Key doubles as the name of the
+// environment variable the value is seeded from.
+type Setting struct {
+	Key         string
+	Kind        Kind
+	Description string
+	// Default is used when the setting is neither stored nor in the environment
+	Default string
+	// Global settings are shared by every guild and stored with the home guild
+	Global bool
+	// Optional settings may be left empty without being reported as a problem
+	Optional bool
+	// Validate, if set, is checked after the kind's own validation
+	Validate func(value string) error
+	// Apply, if set, is called with the stored value once it is loaded and with every new value,
+	// for settings that have to be pushed into another package rather than read on each use
+	Apply func(value string)
+}
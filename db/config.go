@@ -0,0 +1,98 @@
+package db
+
+import (
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// configKey is the hash of runtime configuration values, keyed by setting name
+	configKey = "config"
+	// configHistoryKey holds the most recent setting changes as JSON entries, newest first
+	configHistoryKey = "configHistory"
+	// configHistoryLimit is the number of history entries kept
+	configHistoryLimit = 1000
+)
+
+// GetConfigValues returns every stored configuration value
+func GetConfigValues(ctx context.Context) (map[string]string, error) {
+	values, err := RedisDB.HGetAll(ctx, guildKey(ctx, configKey)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config values: %w", err)
+	}
+	return values, nil
+}
+
+// SetConfigValue stores a setting's new value and records the change in the history
+func SetConfigValue(ctx context.Context, change models.ConfigChange) error {
+	entryJSON, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config change: %w", err)
+	}
+
+	_, err = RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, guildKey(ctx, configKey), change.Key, change.NewValue)
+		pipe.LPush(ctx, guildKey(ctx, configHistoryKey), entryJSON)
+		pipe.LTrim(ctx, guildKey(ctx, configHistoryKey), 0, configHistoryLimit-1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set config value %s: %w", change.Key, err)
+	}
+	return nil
+}
+
+// GetConfigHistory returns up to limit of the most recent setting changes, newest first
+func GetConfigHistory(ctx context.Context, limit int64) ([]models.ConfigChange, error) {
+	raw, err := RedisDB.LRange(ctx, guildKey(ctx, configHistoryKey), 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch config history: %w", err)
+	}
+
+	changes := make([]models.ConfigChange, 0, len(raw))
+	for _, data := range raw {
+		var change models.ConfigChange
+		if err := json.Unmarshal([]byte(data), &change); err != nil {
+			logger.Warn(logger.LogData{
+				"action":  "get_config_history",
+				"message": "Skipping unreadable history entry",
+				"error":   err.Error(),
+			})
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// SeedConfigValues stores the values whose keys are not set yet and returns the keys it stored
+func SeedConfigValues(ctx context.Context, values map[string]string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	results := make(map[string]*redis.BoolCmd, len(values))
+	_, err := RedisDB.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			results[key] = pipe.HSetNX(ctx, guildKey(ctx, configKey), key, value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to seed config values: %w", err)
+	}
+
+	var seeded []string
+	for key, result := range results {
+		if result.Val() {
+			seeded = append(seeded, key)
+		}
+	}
+	return seeded, nil
+}
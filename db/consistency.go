@@ -0,0 +1,260 @@
+package db
+
+import (
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// consistencyScanCount is the COUNT hint used when scanning keys during a consistency check
+const consistencyScanCount = 500
+
+// ConsistencyReport lists the problems found by CheckConsistency.
+// Each slice holds the IDs or keys affected so they can be shown to an admin.
+type ConsistencyReport struct {
+	// Queue, in-flight or dead-letter entries whose task blob no longer exists
+	DanglingQueueEntries []string
+	// Task blobs that are not done and are not referenced by any queue
+	OrphanedTaskBlobs []string
+	// User or scenario index entries that point at a task which is no longer live
+	StaleIndexEntries []string
+	// Monitoring session set entries whose session blob no longer exists
+	StaleSessionEntries []string
+	// Users with live monitoring sessions who are missing from the tracked users set
+	UntrackedUsers []string
+	// Tracked users with no live monitoring session
+	TrackedUsersWithoutSession []string
+	// Whether the problems above were repaired
+	Repaired bool
+}
+
+// Total returns the number of problems found
+func (r ConsistencyReport) Total() int {
+	return len(r.DanglingQueueEntries) + len(r.OrphanedTaskBlobs) + len(r.StaleIndexEntries) +
+		len(r.StaleSessionEntries) + len(r.UntrackedUsers) + len(r.TrackedUsersWithoutSession)
+}
+
+// CheckConsistency looks for data left behind by partial writes and, when repair is true, fixes it.
+// Dangling references are removed, orphaned blobs are deleted, users with live sessions are
+// re-tracked and tracked users without a session are removed along with their data.
+func CheckConsistency(ctx context.Context, repair bool) (ConsistencyReport, error) {
+	report := ConsistencyReport{Repaired: repair}
+
+	live, err := checkTaskSets(ctx, &report, repair)
+	if err != nil {
+		return report, err
+	}
+	if err := checkTaskBlobs(ctx, &report, live, repair); err != nil {
+		return report, err
+	}
+	if err := checkTaskIndexes(ctx, &report, live, repair); err != nil {
+		return report, err
+	}
+	if err := checkMonitoringSessions(ctx, &report, repair); err != nil {
+		return report, err
+	}
+
+	logger.Info(logger.LogData{
+		"action":   "check_consistency",
+		"message":  "Consistency check completed",
+		"problems": report.Total(),
+		"repaired": repair,
+	})
+
+	return report, nil
+}
+
+// checkTaskSets removes queue, in-flight and dead-letter entries without a blob.
+// It returns every referenced task ID mapped to whether it is live (queued or in flight).
+func checkTaskSets(ctx context.Context, report *ConsistencyReport, repair bool) (map[string]bool, error) {
+	live := make(map[string]bool)
+
+	for _, setKey := range []string{guildKey(ctx, taskQueueKey), guildKey(ctx, taskInFlightKey), guildKey(ctx, taskDeadLetterKey)} {
+		taskIDs, err := RedisDB.ZRange(ctx, setKey, 0, -1).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", setKey, err)
+		}
+
+		for _, taskID := range taskIDs {
+			exists, err := RedisDB.Exists(ctx, guildKey(ctx, "task:"+taskID)).Result()
+			if err != nil {
+				return nil, err
+			}
+
+			if exists == 0 {
+				report.DanglingQueueEntries = append(report.DanglingQueueEntries, taskID)
+				if repair {
+					RedisDB.ZRem(ctx, setKey, taskID)
+				}
+				continue
+			}
+
+			// Dead-lettered tasks are referenced but not live, so they are dropped from the indexes
+			if setKey == guildKey(ctx, taskDeadLetterKey) {
+				live[taskID] = false
+			} else {
+				live[taskID] = true
+			}
+		}
+	}
+
+	return live, nil
+}
+
+// checkTaskBlobs deletes task blobs that are not done and not referenced by any queue set
+func checkTaskBlobs(ctx context.Context, report *ConsistencyReport, referenced map[string]bool, repair bool) error {
+	iter := RedisDB.Scan(ctx, 0, guildKey(ctx, "task:*"), consistencyScanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		taskID := strings.TrimPrefix(key, guildKey(ctx, "task:"))
+		if _, ok := referenced[taskID]; ok {
+			continue
+		}
+
+		data, err := RedisDB.Get(ctx, key).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		// Done tasks are kept for a while on purpose and expire on their own
+		var task models.Task
+		if json.Unmarshal([]byte(data), &task) == nil && task.Status == models.TaskStatusDone {
+			continue
+		}
+
+		// The task may have been saved after the queues were read
+		if _, isReferenced := taskState(ctx, taskID); isReferenced {
+			continue
+		}
+
+		report.OrphanedTaskBlobs = append(report.OrphanedTaskBlobs, taskID)
+		if repair {
+			RedisDB.Del(ctx, key)
+		}
+	}
+
+	return iter.Err()
+}
+
+// checkTaskIndexes removes user and scenario index entries for tasks that are no longer live
+func checkTaskIndexes(ctx context.Context, report *ConsistencyReport, live map[string]bool, repair bool) error {
+	for _, pattern := range []string{userTasksKey(ctx, "*"), scenarioTasksKey(ctx, "*")} {
+		iter := RedisDB.Scan(ctx, 0, pattern, consistencyScanCount).Iterator()
+		for iter.Next(ctx) {
+			indexKey := iter.Val()
+
+			taskIDs, err := RedisDB.SMembers(ctx, indexKey).Result()
+			if err != nil {
+				return err
+			}
+
+			for _, taskID := range taskIDs {
+				if live[taskID] {
+					continue
+				}
+				if isLive, _ := taskState(ctx, taskID); isLive {
+					continue
+				}
+				report.StaleIndexEntries = append(report.StaleIndexEntries, fmt.Sprintf("%s → %s", indexKey, taskID))
+				if repair {
+					RedisDB.SRem(ctx, indexKey, taskID)
+				}
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkMonitoringSessions reconciles monitoring session sets with their blobs and with the tracked users set
+func checkMonitoringSessions(ctx context.Context, report *ConsistencyReport, repair bool) error {
+	trackedUsers, err := GetTrackedUsers(ctx)
+	if err != nil {
+		return err
+	}
+	tracked := make(map[string]bool, len(trackedUsers))
+	for _, userID := range trackedUsers {
+		tracked[userID] = true
+	}
+
+	withSession := make(map[string]bool)
+
+	iter := RedisDB.Scan(ctx, 0, guildKey(ctx, "user:*:monitoring_sessions"), consistencyScanCount).Iterator()
+	for iter.Next(ctx) {
+		sessionsKey := iter.Val()
+		userID := strings.TrimSuffix(strings.TrimPrefix(sessionsKey, guildKey(ctx, "user:")), ":monitoring_sessions")
+
+		sessionKeys, err := RedisDB.SMembers(ctx, sessionsKey).Result()
+		if err != nil {
+			return err
+		}
+
+		for _, sessionKey := range sessionKeys {
+			data, err := RedisDB.Get(ctx, sessionKey).Result()
+			if err == redis.Nil {
+				report.StaleSessionEntries = append(report.StaleSessionEntries, sessionKey)
+				if repair {
+					RedisDB.SRem(ctx, sessionsKey, sessionKey)
+				}
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			var session models.UserMonitoring
+			if json.Unmarshal([]byte(data), &session) == nil && !session.IsExpired() {
+				withSession[userID] = true
+			}
+		}
+
+		if withSession[userID] && !tracked[userID] {
+			report.UntrackedUsers = append(report.UntrackedUsers, userID)
+			if repair {
+				AddTrackedUser(ctx, userID)
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	for _, userID := range trackedUsers {
+		if withSession[userID] {
+			continue
+		}
+		report.TrackedUsersWithoutSession = append(report.TrackedUsersWithoutSession, userID)
+		if repair {
+			RemoveTrackedUser(ctx, userID)
+		}
+	}
+
+	return nil
+}
+
+// taskState reports whether a task is live (queued or in flight) and whether any task set,
+// including the dead-letter and quarantine sets, references it
+func taskState(ctx context.Context, taskID string) (live bool, referenced bool) {
+	for _, setKey := range []string{guildKey(ctx, taskQueueKey), guildKey(ctx, taskInFlightKey)} {
+		if _, err := RedisDB.ZScore(ctx, setKey, taskID).Result(); err == nil {
+			return true, true
+		}
+	}
+	for _, setKey := range []string{guildKey(ctx, taskDeadLetterKey), guildKey(ctx, taskQuarantineKey)} {
+		if _, err := RedisDB.ZScore(ctx, setKey, taskID).Result(); err == nil {
+			return false, true
+		}
+	}
+	return false, false
+}
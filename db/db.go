@@ -1,6 +1,7 @@
 package db
 
 import (
+	"astralHRBot/clock"
 	"astralHRBot/logger"
 	"astralHRBot/models"
 	"context"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -49,6 +51,14 @@ func InitRedis() {
 	})
 }
 
+// CloseRedis closes the Redis connection pool
+func CloseRedis() error {
+	if RedisDB == nil {
+		return nil
+	}
+	return RedisDB.Close()
+}
+
 func GetRedisClient() *redis.Client {
 	if RedisDB == nil {
 		logger.Error(logger.LogData{
@@ -63,7 +73,7 @@ func GetRedisClient() *redis.Client {
 
 func GetUserFromRedis(ctx context.Context, userID string) (*models.User, error) {
 
-	key := "User:" + userID
+	key := guildKey(ctx, "User:"+userID)
 
 	data, err := RedisDB.HGetAll(ctx, key).Result()
 	if err != nil {
@@ -97,7 +107,7 @@ func GetUserFromRedis(ctx context.Context, userID string) (*models.User, error)
 }
 
 func SaveUserToRedis(ctx context.Context, user *models.User) error {
-	key := "User:" + user.DiscordID
+	key := guildKey(ctx, "User:"+user.DiscordID)
 
 	userMap, err := structToMap(user)
 	if err != nil {
@@ -198,49 +208,836 @@ func structToMap(input interface{}) (map[string]interface{}, error) {
 	return result, nil
 }
 
-func FetchLatestTasks(ctx context.Context) ([]models.Task, error) {
-	taskQueue := "taskQueue"
-	now := time.Now().Unix()
+const (
+	// taskQueueKey holds pending task IDs scored by their scheduled unix time
+	taskQueueKey = "taskQueue"
+	// taskInFlightKey holds claimed task IDs scored by the unix time their lease expires
+	taskInFlightKey = "taskInFlight"
+	// taskDeadLetterKey holds task IDs that exhausted their retries, scored by the unix time they failed
+	taskDeadLetterKey = "taskDeadLetter"
+	// finishedTaskRetention is how long done task blobs are kept for inspection
+	finishedTaskRetention = 24 * time.Hour
+	// taskAuditKey holds the most recent manual task changes as JSON entries, newest first
+	taskAuditKey = "taskAudit"
+	// taskAuditLimit is the number of audit entries kept
+	taskAuditLimit = 1000
+	// taskUpdateAttempts is how many times a queued task update is retried after a conflict
+	taskUpdateAttempts = 3
+	// taskQuarantineKey holds IDs of tasks whose blob was missing or unreadable, scored by the unix time they were found
+	taskQuarantineKey = "taskQuarantine"
+	// taskBatchSize is the maximum number of task blobs fetched per MGET
+	taskBatchSize = 500
+	// trackedUsersKey is the set of users with live monitoring sessions
+	trackedUsersKey = "trackedUsers"
+)
+
+// quarantinedTasks counts tasks quarantined since startup
+var quarantinedTasks atomic.Int64
 
-	taskIDs, err := RedisDB.ZRangeByScore(ctx, taskQueue, &redis.ZRangeBy{
-		Min:    "0",
-		Max:    fmt.Sprintf("%d", now),
-		Offset: 0,
-		Count:  100,
-	}).Result()
+// QuarantinedTaskCount returns the number of tasks quarantined since startup
+func QuarantinedTaskCount() int64 {
+	return quarantinedTasks.Load()
+}
+
+// claimTasksScript atomically moves due tasks from the queue into the in-flight set
+// so that only one worker (or bot replica) can pick each task up.
+// KEYS[1] = task queue, KEYS[2] = in-flight set
+// ARGV[1] = now, ARGV[2] = lease expiry, ARGV[3] = max tasks to claim
+var claimTasksScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[3]))
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[2], id)
+end
+return ids
+`)
+
+// requeueExpiredScript atomically moves tasks whose lease has expired back into the queue.
+// KEYS[1] = task queue, KEYS[2] = in-flight set
+// ARGV[1] = now
+var requeueExpiredScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', ARGV[1])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[2], id)
+	redis.call('ZADD', KEYS[1], ARGV[1], id)
+end
+return ids
+`)
+
+// retryTaskScript releases a task's lease and queues it again, but only if the lease is still held.
+// Returns 1 if the task was requeued and 0 if it was no longer in flight.
+// KEYS[1] = in-flight set, KEYS[2] = task queue, KEYS[3] = task blob
+// ARGV[1] = task ID, ARGV[2] = task blob, ARGV[3] = scheduled time
+var retryTaskScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[3], ARGV[2])
+redis.call('ZADD', KEYS[2], ARGV[3], ARGV[1])
+return 1
+`)
+
+// ackTaskScript releases a finished task's lease and marks it done, but only if the lease is still
+// held. The blob is only rewritten if it still exists, so a task removed while it ran stays gone.
+// Returns 1 if the task was acknowledged and 0 if it was no longer in flight.
+// KEYS[1] = in-flight set, KEYS[2] = task queue, KEYS[3] = task blob, KEYS[4..] = index sets
+// ARGV[1] = task ID, ARGV[2] = finished task blob, ARGV[3] = blob retention in milliseconds
+var ackTaskScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('SET', KEYS[3], ARGV[2], 'XX', 'PX', ARGV[3])
+for i = 4, #KEYS do
+	redis.call('SREM', KEYS[i], ARGV[1])
+end
+return 1
+`)
+
+// requeueDeadLetterScript moves a task out of the dead-letter set and back into the queue, but only
+// if it is still dead-lettered and its blob has not changed since it was read. Returns 1 if the
+// task was requeued and 0 otherwise.
+// KEYS[1] = dead-letter set, KEYS[2] = task queue, KEYS[3] = task blob, KEYS[4..] = index sets
+// ARGV[1] = task ID, ARGV[2] = blob as read, ARGV[3] = new blob, ARGV[4] = scheduled time
+var requeueDeadLetterScript = redis.NewScript(`
+if redis.call('GET', KEYS[3]) ~= ARGV[2] then
+	return 0
+end
+if redis.call('ZREM', KEYS[1], ARGV[1]) == 0 then
+	return 0
+end
+redis.call('SET', KEYS[3], ARGV[3])
+redis.call('ZADD', KEYS[2], ARGV[4], ARGV[1])
+for i = 4, #KEYS do
+	redis.call('SADD', KEYS[i], ARGV[1])
+end
+return 1
+`)
+
+// quarantineTasksScript moves task IDs that are still referenced by the queue, in-flight or
+// dead-letter sets into the quarantine set. IDs no longer referenced were removed concurrently
+// and are skipped. Returns the IDs that were moved.
+// KEYS[1] = task queue, KEYS[2] = in-flight set, KEYS[3] = dead-letter set, KEYS[4] = quarantine set
+// ARGV[1] = now, ARGV[2..] = task IDs
+var quarantineTasksScript = redis.NewScript(`
+local moved = {}
+for i = 2, #ARGV do
+	local id = ARGV[i]
+	local found = 0
+	for k = 1, 3 do
+		found = found + redis.call('ZREM', KEYS[k], id)
+	end
+	if found > 0 then
+		redis.call('ZADD', KEYS[4], ARGV[1], id)
+		table.insert(moved, id)
+	end
+end
+return moved
+`)
+
+// setTaskBlobsScript replaces task blobs that still hold the value they were read with, keeping
+// their TTL. Blobs that were changed or deleted in the meantime are left alone so a finished or
+// removed task is never written back. Returns the 1-based positions of the keys that were written.
+// KEYS[i] = task blob
+// ARGV[2i-1] = blob as read, ARGV[2i] = new blob
+var setTaskBlobsScript = redis.NewScript(`
+local written = {}
+for i = 1, #KEYS do
+	if redis.call('GET', KEYS[i]) == ARGV[2 * i - 1] then
+		redis.call('SET', KEYS[i], ARGV[2 * i], 'KEEPTTL')
+		table.insert(written, i)
+	end
+end
+return written
+`)
+
+// updateQueuedTaskScript rewrites or deletes a task that is still queued and whose blob has not
+// changed since it was read, and records the audit entry. An empty new blob deletes the task and
+// removes it from the index sets. Returns 1 if the task was updated and 0 if it was claimed or
+// changed in the meantime.
+// KEYS[1] = task queue, KEYS[2] = task blob, KEYS[3] = audit list, KEYS[4..] = index sets
+// ARGV[1] = task ID, ARGV[2] = blob as read, ARGV[3] = new blob, ARGV[4] = new scheduled time,
+// ARGV[5] = audit entry, ARGV[6] = audit limit
+var updateQueuedTaskScript = redis.NewScript(`
+if not redis.call('ZSCORE', KEYS[1], ARGV[1]) then
+	return 0
+end
+if redis.call('GET', KEYS[2]) ~= ARGV[2] then
+	return 0
+end
+if ARGV[3] == '' then
+	redis.call('DEL', KEYS[2])
+	redis.call('ZREM', KEYS[1], ARGV[1])
+	for i = 4, #KEYS do
+		redis.call('SREM', KEYS[i], ARGV[1])
+	end
+else
+	redis.call('SET', KEYS[2], ARGV[3])
+	redis.call('ZADD', KEYS[1], ARGV[4], ARGV[1])
+end
+redis.call('LPUSH', KEYS[3], ARGV[5])
+redis.call('LTRIM', KEYS[3], 0, tonumber(ARGV[6]) - 1)
+return 1
+`)
+
+// ClaimDueTasks leases up to limit tasks whose scheduled time has passed.
+// Claimed tasks are moved to the in-flight set and marked as running; they must be
+// acknowledged with AckTask, RetryTask or DeadLetterTask before the lease expires, otherwise
+// RequeueExpiredTasks will put them back in the queue.
+func ClaimDueTasks(ctx context.Context, lease time.Duration, limit int) ([]models.Task, error) {
+	now := clock.Now()
+
+	taskIDs, err := claimTasksScript.Run(ctx, RedisDB,
+		[]string{guildKey(ctx, taskQueueKey), guildKey(ctx, taskInFlightKey)},
+		now.Unix(), now.Add(lease).Unix(), limit,
+	).StringSlice()
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "claim_due_tasks",
+			"message": "Failed to claim due tasks",
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
 
+	tasks, err := setTaskStatuses(ctx, taskIDs, models.TaskStatusRunning)
 	if err != nil {
 		logger.Error(logger.LogData{
-			"action":  "fetch_latest_tasks",
-			"message": "Failed to fetch latest tasks",
+			"action":  "claim_due_tasks",
+			"message": "Failed to mark claimed tasks as running",
 			"error":   err.Error(),
 		})
 		return nil, err
 	}
 
-	tasks := make([]models.Task, len(taskIDs))
+	return tasks, nil
+}
 
-	for i, taskID := range taskIDs {
-		task, err := getTaskByID(ctx, taskID)
-		if err != nil {
-			logger.Error(logger.LogData{
-				"action":  "fetch_latest_tasks",
-				"message": "Failed to get task by ID",
+// RequeueExpiredTasks returns tasks whose lease has expired to the queue so they can be claimed again
+func RequeueExpiredTasks(ctx context.Context) ([]string, error) {
+	taskIDs, err := requeueExpiredScript.Run(ctx, RedisDB,
+		[]string{guildKey(ctx, taskQueueKey), guildKey(ctx, taskInFlightKey)},
+		clock.Now().Unix(),
+	).StringSlice()
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "requeue_expired_tasks",
+			"message": "Failed to requeue tasks with expired leases",
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+
+	if _, err := setTaskStatuses(ctx, taskIDs, models.TaskStatusPending); err != nil {
+		logger.Error(logger.LogData{
+			"action":  "requeue_expired_tasks",
+			"message": "Failed to mark requeued tasks as pending",
+			"error":   err.Error(),
+		})
+	}
+
+	return taskIDs, nil
+}
+
+// RetryTask releases the lease on a failed task and puts it back in the queue to run at runAt.
+// Nothing is written if the lease is no longer held, because the task was acknowledged, requeued
+// or removed while it ran.
+func RetryTask(ctx context.Context, task models.Task, runAt time.Time) error {
+	task.Status = models.TaskStatusPending
+	task.ScheduledTime = runAt.Unix()
+
+	taskJSON, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	retried, err := retryTaskScript.Run(ctx, RedisDB,
+		[]string{guildKey(ctx, taskInFlightKey), guildKey(ctx, taskQueueKey), guildKey(ctx, "task:"+task.TaskID)},
+		task.TaskID, taskJSON, task.ScheduledTime,
+	).Bool()
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "retry_task",
+			"message": "Failed to reschedule task",
+			"error":   err.Error(),
+			"task_id": task.TaskID,
+		})
+		return err
+	}
+	if !retried {
+		logger.Warn(logger.LogData{
+			"action":  "retry_task",
+			"message": "Task lease was lost before its retry could be scheduled",
+			"task_id": task.TaskID,
+		})
+		return fmt.Errorf("task %s is no longer leased", task.TaskID)
+	}
+
+	return nil
+}
+
+// DeadLetterTask moves a task that exhausted its retries into the dead-letter set
+func DeadLetterTask(ctx context.Context, task models.Task) error {
+	task.Status = models.TaskStatusFailed
+
+	taskJSON, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	_, err = RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, guildKey(ctx, "task:"+task.TaskID), taskJSON, 0)
+		pipe.ZRem(ctx, guildKey(ctx, taskInFlightKey), task.TaskID)
+		pipe.ZRem(ctx, guildKey(ctx, taskQueueKey), task.TaskID)
+		pipe.ZAdd(ctx, guildKey(ctx, taskDeadLetterKey), redis.Z{
+			Score:  float64(clock.Now().Unix()),
+			Member: task.TaskID,
+		})
+		unindexTask(ctx, pipe, task)
+		return nil
+	})
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "dead_letter_task",
+			"message": "Failed to move task to dead-letter queue",
+			"error":   err.Error(),
+			"task_id": task.TaskID,
+		})
+		return err
+	}
+
+	return nil
+}
+
+// GetDeadLetterTasks returns all tasks in the dead-letter set, oldest first
+func GetDeadLetterTasks(ctx context.Context) ([]models.Task, error) {
+	taskIDs, err := RedisDB.ZRange(ctx, guildKey(ctx, taskDeadLetterKey), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dead-letter tasks: %w", err)
+	}
+
+	return loadTasks(ctx, taskIDs)
+}
+
+// RequeueDeadLetterTask moves a dead-lettered task back into the queue with its retries reset.
+// The move is made by requeueDeadLetterScript, so a task purged or requeued by someone else in the
+// meantime is left alone.
+func RequeueDeadLetterTask(ctx context.Context, taskID string) error {
+	if _, err := RedisDB.ZScore(ctx, guildKey(ctx, taskDeadLetterKey), taskID).Result(); err != nil {
+		return fmt.Errorf("task %s is not in the dead-letter queue", taskID)
+	}
+
+	data, err := RedisDB.Get(ctx, guildKey(ctx, "task:"+taskID)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to load task %s: %w", taskID, err)
+	}
+	var task models.Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return fmt.Errorf("failed to decode task %s: %w", taskID, err)
+	}
+
+	task.Status = models.TaskStatusPending
+	task.Retries = 0
+	task.LastError = ""
+	task.ScheduledTime = clock.Now().Unix()
+
+	taskJSON, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	keys := []string{guildKey(ctx, taskDeadLetterKey), guildKey(ctx, taskQueueKey), guildKey(ctx, "task:"+taskID)}
+	if userID := task.UserID(); userID != "" {
+		keys = append(keys, userTasksKey(ctx, userID))
+	}
+	if task.Scenario != "" {
+		keys = append(keys, scenarioTasksKey(ctx, task.Scenario))
+	}
+
+	requeued, err := requeueDeadLetterScript.Run(ctx, RedisDB, keys,
+		taskID, data, taskJSON, task.ScheduledTime,
+	).Bool()
+	if err != nil {
+		return err
+	}
+	if !requeued {
+		return fmt.Errorf("task %s is not in the dead-letter queue", taskID)
+	}
+	return nil
+}
+
+// PurgeDeadLetterTask permanently deletes a dead-lettered task
+func PurgeDeadLetterTask(ctx context.Context, taskID string) error {
+	removed, err := RedisDB.ZRem(ctx, guildKey(ctx, taskDeadLetterKey), taskID).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("task %s is not in the dead-letter queue", taskID)
+	}
+
+	return RedisDB.Del(ctx, guildKey(ctx, "task:"+taskID)).Err()
+}
+
+// GetTask returns a single task by ID
+func GetTask(ctx context.Context, taskID string) (models.Task, error) {
+	return getTaskByID(ctx, taskID)
+}
+
+// RescheduleTask moves a queued task to a new scheduled time and records the change in the audit trail
+func RescheduleTask(ctx context.Context, taskID string, runAt time.Time, actor string) (models.Task, error) {
+	return updateQueuedTask(ctx, taskID, models.TaskAuditReschedule, actor, func(task *models.Task) bool {
+		task.ScheduledTime = runAt.Unix()
+		return true
+	})
+}
+
+// RunTaskNow makes a queued task due immediately so the task processor picks it up on its next poll
+func RunTaskNow(ctx context.Context, taskID string, actor string) (models.Task, error) {
+	return updateQueuedTask(ctx, taskID, models.TaskAuditRunNow, actor, func(task *models.Task) bool {
+		task.ScheduledTime = clock.Now().Unix()
+		return true
+	})
+}
+
+// CancelTask deletes a queued task and records the change in the audit trail
+func CancelTask(ctx context.Context, taskID string, actor string) (models.Task, error) {
+	return updateQueuedTask(ctx, taskID, models.TaskAuditCancel, actor, func(task *models.Task) bool {
+		task.ScheduledTime = 0
+		return false
+	})
+}
+
+// GetTaskAuditLog returns up to limit of the most recent manual task changes, newest first
+func GetTaskAuditLog(ctx context.Context, limit int64) ([]models.TaskAuditEntry, error) {
+	raw, err := RedisDB.LRange(ctx, guildKey(ctx, taskAuditKey), 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch task audit log: %w", err)
+	}
+
+	entries := make([]models.TaskAuditEntry, 0, len(raw))
+	for _, data := range raw {
+		var entry models.TaskAuditEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			logger.Warn(logger.LogData{
+				"action":  "get_task_audit_log",
+				"message": "Skipping unreadable audit entry",
 				"error":   err.Error(),
 			})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// updateQueuedTask applies change to a task that is still waiting in the queue and appends an
+// audit entry. change returns false to delete the task instead of requeueing it. The write is
+// made by updateQueuedTaskScript, which checks the task is still queued and unchanged, so a task
+// claimed by the task processor mid-update is never written back into the queue.
+func updateQueuedTask(ctx context.Context, taskID string, action models.TaskAuditAction, actor string, change func(task *models.Task) bool) (models.Task, error) {
+	var updated models.Task
+
+	update := func() (bool, error) {
+		if _, err := RedisDB.ZScore(ctx, guildKey(ctx, taskQueueKey), taskID).Result(); err == redis.Nil {
+			if _, err := RedisDB.ZScore(ctx, guildKey(ctx, taskInFlightKey), taskID).Result(); err == nil {
+				return false, fmt.Errorf("task %s is currently running", taskID)
+			}
+			return false, fmt.Errorf("task %s is not waiting in the queue", taskID)
+		} else if err != nil {
+			return false, err
+		}
+
+		data, err := RedisDB.Get(ctx, guildKey(ctx, "task:"+taskID)).Result()
+		if err != nil {
+			return false, fmt.Errorf("failed to load task %s: %w", taskID, err)
+		}
+		var task models.Task
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			return false, fmt.Errorf("failed to decode task %s: %w", taskID, err)
+		}
+
+		entry := models.TaskAuditEntry{
+			TaskID:       taskID,
+			TaskType:     task.FunctionName,
+			Action:       action,
+			Actor:        actor,
+			PreviousTime: task.ScheduledTime,
+			Timestamp:    clock.Now().Unix(),
+		}
+
+		keep := change(&task)
+		task.Status = models.TaskStatusPending
+		entry.NewTime = task.ScheduledTime
+
+		// An empty blob tells the script to delete the task
+		newBlob := ""
+		if keep {
+			taskJSON, err := json.Marshal(task)
+			if err != nil {
+				return false, err
+			}
+			newBlob = string(taskJSON)
+		}
+		entryJSON, err := json.Marshal(entry)
+		if err != nil {
+			return false, err
+		}
+
+		keys := []string{guildKey(ctx, taskQueueKey), guildKey(ctx, "task:"+taskID), guildKey(ctx, taskAuditKey)}
+		if !keep {
+			if userID := task.UserID(); userID != "" {
+				keys = append(keys, userTasksKey(ctx, userID))
+			}
+			if task.Scenario != "" {
+				keys = append(keys, scenarioTasksKey(ctx, task.Scenario))
+			}
+		}
+
+		applied, err := updateQueuedTaskScript.Run(ctx, RedisDB, keys,
+			taskID, data, newBlob, task.ScheduledTime, entryJSON, taskAuditLimit,
+		).Bool()
+		if err != nil || !applied {
+			return false, err
+		}
+
+		updated = task
+		return true, nil
+	}
+
+	var err error
+	applied := false
+	for attempt := 0; attempt < taskUpdateAttempts && err == nil && !applied; attempt++ {
+		applied, err = update()
+	}
+	if err == nil && !applied {
+		err = fmt.Errorf("task %s kept changing, giving up after %d attempts", taskID, taskUpdateAttempts)
+	}
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":     "update_queued_task",
+			"message":    "Failed to update task",
+			"error":      err.Error(),
+			"task_id":    taskID,
+			"task_audit": string(action),
+		})
+		return models.Task{}, err
+	}
+
+	logger.Info(logger.LogData{
+		"action":     "update_queued_task",
+		"message":    "Task updated",
+		"task_id":    taskID,
+		"task_audit": string(action),
+		"actor":      actor,
+	})
+
+	return updated, nil
+}
+
+// AckTask marks a claimed task as done, records its result summary and removes it from the queue.
+// The task blob is kept for finishedTaskRetention so its outcome can be inspected. Nothing is
+// written if the lease is no longer held, because the task was requeued or removed while it ran.
+func AckTask(ctx context.Context, taskID string, result models.TaskResult) error {
+	task, err := getTaskByID(ctx, taskID)
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to load task %s: %w", taskID, err)
+	}
+
+	keys := []string{guildKey(ctx, taskInFlightKey), guildKey(ctx, taskQueueKey), guildKey(ctx, "task:"+taskID)}
+	if userID := task.UserID(); userID != "" {
+		keys = append(keys, userTasksKey(ctx, userID))
+	}
+	if task.Scenario != "" {
+		keys = append(keys, scenarioTasksKey(ctx, task.Scenario))
+	}
+
+	// The blob may already be gone if the task was removed along with its scenario, in which case
+	// the script's SET XX leaves it that way
+	task.TaskID = taskID
+	task.Status = models.TaskStatusDone
+	task.Result = result.Summary
+	taskJSON, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	acked, err := ackTaskScript.Run(ctx, RedisDB, keys,
+		taskID, taskJSON, finishedTaskRetention.Milliseconds(),
+	).Bool()
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "ack_task",
+			"message": "Failed to acknowledge task",
+			"error":   err.Error(),
+			"task_id": taskID,
+		})
+		return err
+	}
+	if !acked {
+		logger.Warn(logger.LogData{
+			"action":  "ack_task",
+			"message": "Task lease was lost before it could be acknowledged",
+			"task_id": taskID,
+		})
+		return fmt.Errorf("task %s is no longer leased", taskID)
+	}
+
+	logger.Debug(logger.LogData{
+		"action":  "ack_task",
+		"message": "Task acknowledged",
+		"task_id": taskID,
+	})
+
+	return nil
+}
+
+// setTaskStatuses updates the status stored in each task blob and returns the updated tasks.
+// Tasks whose blob is missing or unreadable are quarantined and left out of the result, as are
+// tasks whose blob was changed or removed after it was read.
+func setTaskStatuses(ctx context.Context, taskIDs []string, status models.TaskStatus) ([]models.Task, error) {
+	tasks, blobs, err := loadTaskBlobs(ctx, taskIDs)
+	if err != nil || len(tasks) == 0 {
+		return tasks, err
+	}
+
+	updated := make([]models.Task, 0, len(tasks))
+	for start := 0; start < len(tasks); start += taskBatchSize {
+		batch := tasks[start:min(start+taskBatchSize, len(tasks))]
+
+		keys := make([]string, len(batch))
+		args := make([]interface{}, 0, 2*len(batch))
+		for i := range batch {
+			batch[i].Status = status
+			taskJSON, err := json.Marshal(batch[i])
+			if err != nil {
+				return nil, err
+			}
+			keys[i] = guildKey(ctx, "task:"+batch[i].TaskID)
+			args = append(args, blobs[start+i], taskJSON)
+		}
+
+		written, err := setTaskBlobsScript.Run(ctx, RedisDB, keys, args...).Int64Slice()
+		if err != nil {
 			return nil, err
 		}
-		tasks[i] = task
+		for _, i := range written {
+			updated = append(updated, batch[i-1])
+		}
+	}
+
+	if skipped := len(tasks) - len(updated); skipped > 0 {
+		logger.Debug(logger.LogData{
+			"action":  "set_task_statuses",
+			"message": "Skipped tasks that changed while their status was being set",
+			"status":  string(status),
+			"skipped": skipped,
+		})
+	}
+
+	return updated, nil
+}
+
+// loadTasks fetches task blobs in batches with MGET. A missing or corrupt blob does not fail the
+// whole call: its ID is quarantined so it stops blocking the queue, and the other tasks are returned.
+func loadTasks(ctx context.Context, taskIDs []string) ([]models.Task, error) {
+	tasks, _, err := loadTaskBlobs(ctx, taskIDs)
+	return tasks, err
+}
+
+// loadTaskBlobs works like loadTasks and also returns the raw blob each task was decoded from
+func loadTaskBlobs(ctx context.Context, taskIDs []string) ([]models.Task, []string, error) {
+	tasks := make([]models.Task, 0, len(taskIDs))
+	blobs := make([]string, 0, len(taskIDs))
+	var bad []string
+
+	for start := 0; start < len(taskIDs); start += taskBatchSize {
+		batch := taskIDs[start:min(start+taskBatchSize, len(taskIDs))]
+
+		keys := make([]string, len(batch))
+		for i, taskID := range batch {
+			keys[i] = guildKey(ctx, "task:"+taskID)
+		}
+
+		values, err := RedisDB.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for i, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				logger.Warn(logger.LogData{
+					"action":  "load_tasks",
+					"message": "Task blob is missing",
+					"task_id": batch[i],
+				})
+				bad = append(bad, batch[i])
+				continue
+			}
+
+			var task models.Task
+			if err := json.Unmarshal([]byte(data), &task); err != nil {
+				logger.Warn(logger.LogData{
+					"action":  "load_tasks",
+					"message": "Task blob is corrupt",
+					"error":   err.Error(),
+					"task_id": batch[i],
+				})
+				bad = append(bad, batch[i])
+				continue
+			}
+			tasks = append(tasks, task)
+			blobs = append(blobs, data)
+		}
+	}
+
+	if len(bad) > 0 {
+		quarantineTasks(ctx, bad)
+	}
+
+	return tasks, blobs, nil
+}
+
+// quarantineTasks moves task IDs out of the queue, in-flight and dead-letter sets into the
+// quarantine set. Any blob that still exists is kept so it can be inspected.
+func quarantineTasks(ctx context.Context, taskIDs []string) {
+	args := make([]interface{}, 0, len(taskIDs)+1)
+	args = append(args, clock.Now().Unix())
+	for _, taskID := range taskIDs {
+		args = append(args, taskID)
+	}
+
+	moved, err := quarantineTasksScript.Run(ctx, RedisDB,
+		[]string{guildKey(ctx, taskQueueKey), guildKey(ctx, taskInFlightKey), guildKey(ctx, taskDeadLetterKey), guildKey(ctx, taskQuarantineKey)},
+		args...,
+	).StringSlice()
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":   "quarantine_tasks",
+			"message":  "Failed to quarantine tasks",
+			"error":    err.Error(),
+			"task_ids": taskIDs,
+		})
+		return
+	}
+	if len(moved) == 0 {
+		return
+	}
+
+	quarantinedTasks.Add(int64(len(moved)))
+	logger.Warn(logger.LogData{
+		"action":   "quarantine_tasks",
+		"message":  "Quarantined unreadable tasks",
+		"task_ids": moved,
+	})
+}
+
+// GetTaskQueueStats counts the queued, in-flight and dead-lettered tasks
+func GetTaskQueueStats(ctx context.Context) (models.TaskQueueStats, error) {
+	pipe := RedisDB.Pipeline()
+	queued := pipe.ZCard(ctx, guildKey(ctx, taskQueueKey))
+	inFlight := pipe.ZCard(ctx, guildKey(ctx, taskInFlightKey))
+	deadLetter := pipe.ZCard(ctx, guildKey(ctx, taskDeadLetterKey))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return models.TaskQueueStats{}, err
+	}
+	return models.TaskQueueStats{
+		Queued:     queued.Val(),
+		InFlight:   inFlight.Val(),
+		DeadLetter: deadLetter.Val(),
+	}, nil
+}
+
+// GetQuarantinedTaskIDs returns the IDs of quarantined tasks, oldest first
+func GetQuarantinedTaskIDs(ctx context.Context) ([]string, error) {
+	return RedisDB.ZRange(ctx, guildKey(ctx, taskQuarantineKey), 0, -1).Result()
+}
+
+// userTasksKey is the set of live task IDs (queued or in flight) for a user
+func userTasksKey(ctx context.Context, userID string) string {
+	return guildKey(ctx, fmt.Sprintf("user:%s:tasks", userID))
+}
+
+// scenarioTasksKey is the set of live task IDs (queued or in flight) created by a scenario
+func scenarioTasksKey(ctx context.Context, scenario string) string {
+	return guildKey(ctx, fmt.Sprintf("scenario:%s:tasks", scenario))
+}
+
+// indexTask adds a task to its user and scenario index sets
+func indexTask(ctx context.Context, pipe redis.Pipeliner, task models.Task) {
+	if userID := task.UserID(); userID != "" {
+		pipe.SAdd(ctx, userTasksKey(ctx, userID), task.TaskID)
+	}
+	if task.Scenario != "" {
+		pipe.SAdd(ctx, scenarioTasksKey(ctx, task.Scenario), task.TaskID)
+	}
+}
+
+// unindexTask removes a task from its user and scenario index sets
+func unindexTask(ctx context.Context, pipe redis.Pipeliner, task models.Task) {
+	if userID := task.UserID(); userID != "" {
+		pipe.SRem(ctx, userTasksKey(ctx, userID), task.TaskID)
+	}
+	if task.Scenario != "" {
+		pipe.SRem(ctx, scenarioTasksKey(ctx, task.Scenario), task.TaskID)
+	}
+}
+
+// getIndexedTasks loads every task listed in an index set with a single MGET.
+// IDs whose blob no longer exists are removed from the index.
+func getIndexedTasks(ctx context.Context, indexKey string) ([]models.Task, error) {
+	taskIDs, err := RedisDB.SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(taskIDs) == 0 {
+		return []models.Task{}, nil
+	}
+
+	keys := make([]string, len(taskIDs))
+	for i, taskID := range taskIDs {
+		keys[i] = guildKey(ctx, "task:"+taskID)
+	}
+
+	values, err := RedisDB.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]models.Task, 0, len(taskIDs))
+	var stale []interface{}
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			stale = append(stale, taskIDs[i])
+			continue
+		}
+
+		var task models.Task
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			logger.Warn(logger.LogData{
+				"action":  "get_indexed_tasks",
+				"message": "Failed to decode indexed task",
+				"error":   err.Error(),
+				"task_id": taskIDs[i],
+			})
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	if len(stale) > 0 {
+		RedisDB.SRem(ctx, indexKey, stale...)
 	}
 
 	return tasks, nil
 }
 
 func FetchAllTasks(ctx context.Context) ([]models.Task, error) {
-	taskQueue := "taskQueue"
-
 	// Get all task IDs from the queue (no time restriction)
-	taskIDs, err := RedisDB.ZRange(ctx, taskQueue, 0, -1).Result()
+	taskIDs, err := RedisDB.ZRange(ctx, guildKey(ctx, taskQueueKey), 0, -1).Result()
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "fetch_all_tasks",
@@ -250,26 +1047,33 @@ func FetchAllTasks(ctx context.Context) ([]models.Task, error) {
 		return nil, err
 	}
 
-	tasks := make([]models.Task, len(taskIDs))
+	// Include tasks currently leased by a worker so they are not mistaken for missing
+	inFlightIDs, err := RedisDB.ZRange(ctx, guildKey(ctx, taskInFlightKey), 0, -1).Result()
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "fetch_all_tasks",
+			"message": "Failed to fetch all tasks",
+			"error":   err.Error(),
+		})
+		return nil, err
+	}
+	taskIDs = append(taskIDs, inFlightIDs...)
 
-	for i, taskID := range taskIDs {
-		task, err := getTaskByID(ctx, taskID)
-		if err != nil {
-			logger.Error(logger.LogData{
-				"action":  "fetch_all_tasks",
-				"message": "Failed to get task by ID",
-				"error":   err.Error(),
-			})
-			return nil, err
-		}
-		tasks[i] = task
+	tasks, err := loadTasks(ctx, taskIDs)
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "fetch_all_tasks",
+			"message": "Failed to load tasks",
+			"error":   err.Error(),
+		})
+		return nil, err
 	}
 
 	return tasks, nil
 }
 
 func getTaskByID(ctx context.Context, taskID string) (models.Task, error) {
-	data, err := RedisDB.Get(ctx, "task:"+taskID).Result()
+	data, err := RedisDB.Get(ctx, guildKey(ctx, "task:"+taskID)).Result()
 	if err != nil {
 		return models.Task{}, err
 	}
@@ -279,7 +1083,7 @@ func getTaskByID(ctx context.Context, taskID string) (models.Task, error) {
 }
 
 func SaveTaskToRedis(ctx context.Context, task models.Task) error {
-	key := "task:" + task.TaskID
+	key := guildKey(ctx, "task:"+task.TaskID)
 
 	// Marshal task to JSON before saving
 	taskJSON, err := json.Marshal(task)
@@ -292,24 +1096,20 @@ func SaveTaskToRedis(ctx context.Context, task models.Task) error {
 		return err
 	}
 
-	err = RedisDB.Set(ctx, key, taskJSON, 0).Err()
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "save_task_to_redis",
-			"message": "Failed to save task to redis",
-			"error":   err.Error(),
+	// Write the blob, the queue entry and the indexes together so they never disagree
+	_, err = RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, key, taskJSON, 0)
+		pipe.ZAdd(ctx, guildKey(ctx, taskQueueKey), redis.Z{
+			Score:  float64(task.ScheduledTime),
+			Member: task.TaskID,
 		})
-		return err
-	}
-
-	err = RedisDB.ZAdd(ctx, "taskQueue", redis.Z{
-		Score:  float64(task.ScheduledTime),
-		Member: task.TaskID,
-	}).Err()
+		indexTask(ctx, pipe, task)
+		return nil
+	})
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "save_task_to_redis",
-			"message": "Failed to add task to queue",
+			"message": "Failed to save task to redis",
 			"error":   err.Error(),
 		})
 		return err
@@ -325,22 +1125,33 @@ func SaveTaskToRedis(ctx context.Context, task models.Task) error {
 }
 
 func DeleteTaskFromRedis(ctx context.Context, taskID string) error {
-	err := RedisDB.Del(ctx, "task:"+taskID).Err()
-	if err != nil {
+	// Load the task so it can be removed from its indexes; the blob may already be gone
+	task, err := getTaskByID(ctx, taskID)
+	if err != nil && err != redis.Nil {
 		logger.Error(logger.LogData{
 			"action":  "delete_task_from_redis",
-			"message": "Failed to delete task from redis",
+			"message": "Failed to load task",
 			"error":   err.Error(),
+			"task_id": taskID,
 		})
 		return err
 	}
 
-	err = RedisDB.ZRem(ctx, "taskQueue", taskID).Err()
+	_, err = RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, guildKey(ctx, "task:"+taskID))
+		pipe.ZRem(ctx, guildKey(ctx, taskQueueKey), taskID)
+		pipe.ZRem(ctx, guildKey(ctx, taskInFlightKey), taskID)
+		if task.TaskID != "" {
+			unindexTask(ctx, pipe, task)
+		}
+		return nil
+	})
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "delete_task_from_redis",
-			"message": "Failed to remove task from queue",
+			"message": "Failed to delete task from redis",
 			"error":   err.Error(),
+			"task_id": taskID,
 		})
 		return err
 	}
@@ -349,7 +1160,7 @@ func DeleteTaskFromRedis(ctx context.Context, taskID string) error {
 }
 
 func UpdateUserAnalytics(ctx context.Context, userID, scenario string, messages, voiceJoins, invites int64, topChannelID string) error {
-	key := fmt.Sprintf("user:%s:analytics:%s", userID, scenario)
+	key := guildKey(ctx, fmt.Sprintf("user:%s:analytics:%s", userID, scenario))
 
 	// Update analytics fields
 	fields := map[string]interface{}{
@@ -388,7 +1199,7 @@ func UpdateUserAnalytics(ctx context.Context, userID, scenario string, messages,
 
 func GetTrackedUsers(ctx context.Context) ([]string, error) {
 
-	users, err := RedisDB.SMembers(ctx, "trackedUsers").Result()
+	users, err := RedisDB.SMembers(ctx, guildKey(ctx, trackedUsersKey)).Result()
 
 	if err != nil {
 		logger.Error(logger.LogData{
@@ -403,7 +1214,7 @@ func GetTrackedUsers(ctx context.Context) ([]string, error) {
 }
 
 func AddTrackedUser(ctx context.Context, userID string) error {
-	err := RedisDB.SAdd(ctx, "trackedUsers", userID).Err()
+	err := RedisDB.SAdd(ctx, guildKey(ctx, trackedUsersKey), userID).Err()
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "add tracked user to redis",
@@ -417,19 +1228,23 @@ func AddTrackedUser(ctx context.Context, userID string) error {
 }
 
 func RemoveTrackedUser(ctx context.Context, userID string) error {
-	// Remove from tracked users set
-	err := RedisDB.SRem(ctx, "trackedUsers", userID).Err()
+	// Untrack the user and clean up their data in one transaction
+	err := cleanupUserData(ctx, userID, true)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "remove tracked user from redis",
 			"message": "failed to remove tracked user from redis",
 			"error":   err.Error(),
+			"user_id": userID,
 		})
 		return err
 	}
 
-	// Clean up all user-related data
-	err = CleanupUserData(ctx, userID)
+	return nil
+}
+
+func CleanupUserData(ctx context.Context, userID string) error {
+	err := cleanupUserData(ctx, userID, false)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "cleanup_user_data",
@@ -443,29 +1258,34 @@ func RemoveTrackedUser(ctx context.Context, userID string) error {
 	return nil
 }
 
-func CleanupUserData(ctx context.Context, userID string) error {
-	// Clean up monitoring sessions
-	userSessionsKey := fmt.Sprintf("user:%s:monitoring_sessions", userID)
+// cleanupUserData deletes a user's monitoring sessions, analytics and channel activity in a
+// single transaction, optionally removing them from the tracked users set as well
+func cleanupUserData(ctx context.Context, userID string, untrack bool) error {
+	userSessionsKey := guildKey(ctx, fmt.Sprintf("user:%s:monitoring_sessions", userID))
 	sessionKeys, err := RedisDB.SMembers(ctx, userSessionsKey).Result()
-	if err == nil {
-		// Delete all monitoring sessions
-		for _, sessionKey := range sessionKeys {
-			RedisDB.Del(ctx, sessionKey)
-		}
-		// Remove the sessions set
-		RedisDB.Del(ctx, userSessionsKey)
+	if err != nil {
+		return fmt.Errorf("failed to get monitoring sessions: %w", err)
 	}
 
+	keys := append(sessionKeys, userSessionsKey)
+
 	// Clean up analytics and channel activity for all scenarios
-	scenarios := []string{"new_recruit", "recruitment_process"}
-	for _, scenario := range scenarios {
-		// Clean up analytics hash
-		analyticsKey := fmt.Sprintf("user:%s:analytics:%s", userID, scenario)
-		RedisDB.Del(ctx, analyticsKey)
+	for scenario := range models.ScenarioConfig {
+		keys = append(keys,
+			guildKey(ctx, fmt.Sprintf("user:%s:analytics:%s", userID, scenario)),
+			guildKey(ctx, fmt.Sprintf("user:%s:channels:%s", userID, scenario)),
+		)
+	}
 
-		// Clean up channel activity sorted set
-		channelsKey := fmt.Sprintf("user:%s:channels:%s", userID, scenario)
-		RedisDB.Del(ctx, channelsKey)
+	_, err = RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if untrack {
+			pipe.SRem(ctx, guildKey(ctx, trackedUsersKey), userID)
+		}
+		pipe.Del(ctx, keys...)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	logger.Debug(logger.LogData{
@@ -510,7 +1330,7 @@ func DecreaseAttributeCount(ctx context.Context, key string, attribute string, a
 }
 
 func IncreaseChannelCount(ctx context.Context, userID string, channelID string, scenario string) error {
-	channelsKey := fmt.Sprintf("user:%s:channels:%s", userID, scenario)
+	channelsKey := guildKey(ctx, fmt.Sprintf("user:%s:channels:%s", userID, scenario))
 	err := RedisDB.ZIncrBy(ctx, channelsKey, 1, channelID).Err()
 
 	if err != nil {
@@ -526,7 +1346,7 @@ func IncreaseChannelCount(ctx context.Context, userID string, channelID string,
 }
 
 func DecreaseChannelCount(ctx context.Context, userID string, channelID string, scenario string) error {
-	channelsKey := fmt.Sprintf("user:%s:channels:%s", userID, scenario)
+	channelsKey := guildKey(ctx, fmt.Sprintf("user:%s:channels:%s", userID, scenario))
 	err := RedisDB.ZIncrBy(ctx, channelsKey, -1, channelID).Err()
 
 	if err != nil {
@@ -543,7 +1363,7 @@ func DecreaseChannelCount(ctx context.Context, userID string, channelID string,
 
 func GetUserAnalytics(ctx context.Context, userID string) (models.UserAnalytics, error) {
 	// Get all monitoring sessions for this user to find active scenarios
-	userSessionsKey := fmt.Sprintf("user:%s:monitoring_sessions", userID)
+	userSessionsKey := guildKey(ctx, fmt.Sprintf("user:%s:monitoring_sessions", userID))
 	sessionKeys, err := RedisDB.SMembers(ctx, userSessionsKey).Result()
 	if err != nil {
 		return models.UserAnalytics{}, err
@@ -579,7 +1399,7 @@ func GetUserAnalytics(ctx context.Context, userID string) (models.UserAnalytics,
 
 		// Get analytics for each scenario in this session
 		for scenario := range session.Scenarios {
-			scenarioKey := fmt.Sprintf("user:%s:analytics:%s", userID, scenario)
+			scenarioKey := guildKey(ctx, fmt.Sprintf("user:%s:analytics:%s", userID, scenario))
 			fields, err := RedisDB.HGetAll(ctx, scenarioKey).Result()
 			if err != nil {
 				continue
@@ -626,7 +1446,7 @@ func GetUserAnalytics(ctx context.Context, userID string) (models.UserAnalytics,
 
 		// Get the top channel for the first active scenario
 		for scenario := range session.Scenarios {
-			channelsKey := fmt.Sprintf("user:%s:channels:%s", userID, scenario)
+			channelsKey := guildKey(ctx, fmt.Sprintf("user:%s:channels:%s", userID, scenario))
 			topChan, err := RedisDB.ZRevRangeWithScores(ctx, channelsKey, 0, 0).Result()
 			if err == nil && len(topChan) > 0 {
 				userAnalytics.TopChannelID = topChan[0].Member.(string)
@@ -646,13 +1466,43 @@ func GetUserAnalytics(ctx context.Context, userID string) (models.UserAnalytics,
 	return userAnalytics, nil
 }
 
-func InitializeScenarioAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario) error {
-	key := fmt.Sprintf("user:%s:analytics:%s", userID, scenario)
+// GetScenarioAnalytics returns the analytics counters and most active channel for one scenario
+func GetScenarioAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario) (models.UserAnalytics, error) {
+	analytics := models.UserAnalytics{UserID: userID}
+
+	analyticsKey := guildKey(ctx, fmt.Sprintf("user:%s:analytics:%s", userID, scenario))
+	fields, err := RedisDB.HGetAll(ctx, analyticsKey).Result()
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":   "get_scenario_analytics",
+			"message":  "failed to get scenario analytics",
+			"error":    err.Error(),
+			"user_id":  userID,
+			"scenario": string(scenario),
+		})
+		return analytics, err
+	}
+
+	analytics.Messages, _ = strconv.ParseInt(fields["messages"], 10, 64)
+	analytics.VoiceJoins, _ = strconv.ParseInt(fields["voice_joins"], 10, 64)
+	analytics.Invites, _ = strconv.ParseInt(fields["invites"], 10, 64)
+
+	// The top channel is the highest scored member of the scenario's channel activity set
+	channelsKey := guildKey(ctx, fmt.Sprintf("user:%s:channels:%s", userID, scenario))
+	topChan, err := RedisDB.ZRevRangeWithScores(ctx, channelsKey, 0, 0).Result()
+	if err == nil && len(topChan) > 0 {
+		analytics.TopChannelID = topChan[0].Member.(string)
+	}
+
+	return analytics, nil
+}
 
+// scenarioAnalyticsFields returns the zeroed analytics counters tracked by a scenario
+func scenarioAnalyticsFields(scenario models.MonitoringScenario) (map[string]interface{}, error) {
 	// Get the actions for this scenario from the config
 	actions, exists := models.ScenarioConfig[scenario]
 	if !exists {
-		return fmt.Errorf("unknown scenario: %s", scenario)
+		return nil, fmt.Errorf("unknown scenario: %s", scenario)
 	}
 
 	// Initialize analytics hash with fields based on scenario actions
@@ -677,7 +1527,18 @@ func InitializeScenarioAnalytics(ctx context.Context, userID string, scenario mo
 		}
 	}
 
-	err := RedisDB.HMSet(ctx, key, initialFields).Err()
+	return initialFields, nil
+}
+
+func InitializeScenarioAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario) error {
+	key := guildKey(ctx, fmt.Sprintf("user:%s:analytics:%s", userID, scenario))
+
+	initialFields, err := scenarioAnalyticsFields(scenario)
+	if err != nil {
+		return err
+	}
+
+	err = RedisDB.HMSet(ctx, key, initialFields).Err()
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":   "initialize_scenario_analytics",
@@ -701,7 +1562,8 @@ func InitializeScenarioAnalytics(ctx context.Context, userID string, scenario mo
 
 func SaveUserMonitoring(ctx context.Context, monitoring *models.UserMonitoring) error {
 	// Store monitoring session as JSON with unique key
-	sessionKey := fmt.Sprintf("user:%s:monitoring:%d", monitoring.UserID, monitoring.StartedAt)
+	sessionKey := guildKey(ctx, fmt.Sprintf("user:%s:monitoring:%d", monitoring.UserID, monitoring.StartedAt))
+	userSessionsKey := guildKey(ctx, fmt.Sprintf("user:%s:monitoring_sessions", monitoring.UserID))
 
 	data, err := json.Marshal(monitoring)
 	if err != nil {
@@ -713,31 +1575,10 @@ func SaveUserMonitoring(ctx context.Context, monitoring *models.UserMonitoring)
 		return err
 	}
 
-	err = RedisDB.Set(ctx, sessionKey, string(data), 0).Err()
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "save_user_monitoring",
-			"message": "failed to save monitoring session to redis",
-			"error":   err.Error(),
-		})
-		return err
-	}
-
-	// Add session to user's monitoring sessions set
-	userSessionsKey := fmt.Sprintf("user:%s:monitoring_sessions", monitoring.UserID)
-	err = RedisDB.SAdd(ctx, userSessionsKey, sessionKey).Err()
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "save_user_monitoring",
-			"message": "failed to add session to user sessions set",
-			"error":   err.Error(),
-		})
-		return err
-	}
-
-	// Initialize analytics for each scenario
+	// Build the analytics hashes up front so an unknown scenario fails before anything is written
+	analytics := make(map[string]map[string]interface{}, len(monitoring.Scenarios))
 	for scenario := range monitoring.Scenarios {
-		err = InitializeScenarioAnalytics(ctx, monitoring.UserID, scenario)
+		fields, err := scenarioAnalyticsFields(scenario)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":   "save_user_monitoring",
@@ -747,15 +1588,25 @@ func SaveUserMonitoring(ctx context.Context, monitoring *models.UserMonitoring)
 			})
 			return err
 		}
+		analytics[guildKey(ctx, fmt.Sprintf("user:%s:analytics:%s", monitoring.UserID, scenario))] = fields
 	}
 
-	// Add to tracked users set if not already present
-	err = RedisDB.SAdd(ctx, "trackedUsers", monitoring.UserID).Err()
+	// Write the session, its index entry, the analytics hashes and the tracked user together
+	_, err = RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, sessionKey, string(data), 0)
+		pipe.SAdd(ctx, userSessionsKey, sessionKey)
+		for key, fields := range analytics {
+			pipe.HSet(ctx, key, fields)
+		}
+		pipe.SAdd(ctx, guildKey(ctx, trackedUsersKey), monitoring.UserID)
+		return nil
+	})
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "save_user_monitoring",
-			"message": "failed to add user to tracked users set",
+			"message": "failed to save monitoring session to redis",
 			"error":   err.Error(),
+			"user_id": monitoring.UserID,
 		})
 		return err
 	}
@@ -765,7 +1616,7 @@ func SaveUserMonitoring(ctx context.Context, monitoring *models.UserMonitoring)
 
 func GetUserMonitoring(ctx context.Context, userID string) (*models.UserMonitoring, error) {
 	// Get all monitoring sessions for this user
-	userSessionsKey := fmt.Sprintf("user:%s:monitoring_sessions", userID)
+	userSessionsKey := guildKey(ctx, fmt.Sprintf("user:%s:monitoring_sessions", userID))
 	sessionKeys, err := RedisDB.SMembers(ctx, userSessionsKey).Result()
 	if err != nil {
 		logger.Error(logger.LogData{
@@ -843,9 +1694,9 @@ func SetUserPresence(ctx context.Context, key string, data string) error {
 	return nil
 }
 
+// GetTasksForUser returns the queued and in-flight tasks for a user using the per-user index
 func GetTasksForUser(ctx context.Context, userID string) ([]models.Task, error) {
-	// Get all tasks from the queue (including future tasks)
-	allTasks, err := FetchAllTasks(ctx)
+	userTasks, err := getIndexedTasks(ctx, userTasksKey(ctx, userID))
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "get_tasks_for_user",
@@ -856,14 +1707,6 @@ func GetTasksForUser(ctx context.Context, userID string) ([]models.Task, error)
 		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
 	}
 
-	// Filter tasks for the specific user
-	var userTasks []models.Task
-	for _, task := range allTasks {
-		if task.IsForUser(userID) {
-			userTasks = append(userTasks, task)
-		}
-	}
-
 	logger.Debug(logger.LogData{
 		"action":     "get_tasks_for_user",
 		"message":    "retrieved tasks for user",
@@ -873,3 +1716,19 @@ func GetTasksForUser(ctx context.Context, userID string) ([]models.Task, error)
 
 	return userTasks, nil
 }
+
+// GetTasksForScenario returns the queued and in-flight tasks created by a scenario using the per-scenario index
+func GetTasksForScenario(ctx context.Context, scenario string) ([]models.Task, error) {
+	scenarioTasks, err := getIndexedTasks(ctx, scenarioTasksKey(ctx, scenario))
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":   "get_tasks_for_scenario",
+			"message":  "failed to fetch tasks",
+			"error":    err.Error(),
+			"scenario": scenario,
+		})
+		return nil, fmt.Errorf("failed to fetch tasks: %w", err)
+	}
+
+	return scenarioTasks, nil
+}
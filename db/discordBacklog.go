@@ -0,0 +1,60 @@
+package db
+
+import (
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// discordBacklogKey holds Discord API requests saved at shutdown as JSON entries, oldest first
+const discordBacklogKey = "discordRequestBacklog"
+
+// SavePendingDiscordRequests appends requests to the Discord API backlog
+func SavePendingDiscordRequests(ctx context.Context, requests []models.PendingDiscordRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	entries := make([]interface{}, 0, len(requests))
+	for _, request := range requests {
+		data, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to marshal pending Discord request: %w", err)
+		}
+		entries = append(entries, data)
+	}
+
+	return RedisDB.RPush(ctx, discordBacklogKey, entries...).Err()
+}
+
+// TakePendingDiscordRequests returns the Discord API backlog and clears it in one transaction
+func TakePendingDiscordRequests(ctx context.Context) ([]models.PendingDiscordRequest, error) {
+	var entries *redis.StringSliceCmd
+	_, err := RedisDB.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		entries = pipe.LRange(ctx, discordBacklogKey, 0, -1)
+		pipe.Del(ctx, discordBacklogKey)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Discord request backlog: %w", err)
+	}
+
+	requests := make([]models.PendingDiscordRequest, 0, len(entries.Val()))
+	for _, entry := range entries.Val() {
+		var request models.PendingDiscordRequest
+		if err := json.Unmarshal([]byte(entry), &request); err != nil {
+			logger.Warn(logger.LogData{
+				"action":  "take_pending_discord_requests",
+				"message": "Skipping unreadable Discord request backlog entry",
+				"error":   err.Error(),
+			})
+			continue
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
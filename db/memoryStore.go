@@ -0,0 +1,785 @@
+package db
+
+import (
+	"astralHRBot/clock"
+	"astralHRBot/models"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MemoryStore implements Store in process memory. It mirrors the Redis semantics closely enough
+// for the task handlers, task processor and monitoring tracker to be tested without a Redis server.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	guilds map[string]*memoryGuild // guild ID -> the guild's namespace
+
+	discordBacklog []models.PendingDiscordRequest
+}
+
+// memoryGuild holds one guild's data, as its key namespace does in Redis
+type memoryGuild struct {
+	users map[string]models.User
+
+	tasks      map[string]models.Task
+	queue      map[string]int64        // task ID -> scheduled unix time
+	inFlight   map[string]int64        // task ID -> lease expiry unix time
+	deadLetter map[string]int64        // task ID -> unix time it failed
+	taskAudit  []models.TaskAuditEntry // newest first
+
+	sessions map[string][]models.UserMonitoring // user ID -> monitoring sessions
+	tracked  map[string]struct{}
+
+	analytics map[string]map[string]int64 // analytics key -> field -> count
+	channels  map[string]map[string]int64 // channels key -> channel ID -> count
+
+	config        map[string]string
+	configHistory []models.ConfigChange // newest first
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		guilds: make(map[string]*memoryGuild),
+	}
+}
+
+// guild returns the namespace of the guild in ctx, creating it on first use; the caller holds m.mu
+func (m *MemoryStore) guild(ctx context.Context) *memoryGuild {
+	guildID := GuildFromContext(ctx)
+	g, exists := m.guilds[guildID]
+	if !exists {
+		g = &memoryGuild{
+			users:      make(map[string]models.User),
+			tasks:      make(map[string]models.Task),
+			queue:      make(map[string]int64),
+			inFlight:   make(map[string]int64),
+			deadLetter: make(map[string]int64),
+			sessions:   make(map[string][]models.UserMonitoring),
+			tracked:    make(map[string]struct{}),
+			analytics:  make(map[string]map[string]int64),
+			channels:   make(map[string]map[string]int64),
+			config:     make(map[string]string),
+		}
+		m.guilds[guildID] = g
+	}
+	return g
+}
+
+func analyticsKey(userID string, scenario models.MonitoringScenario) string {
+	return fmt.Sprintf("%s:%s", userID, scenario)
+}
+
+// Users
+
+func (m *MemoryStore) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	user, exists := g.users[userID]
+	if !exists {
+		return nil, fmt.Errorf("no data found for key User:%s", userID)
+	}
+	return &user, nil
+}
+
+func (m *MemoryStore) SaveUser(ctx context.Context, user *models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	g.users[user.DiscordID] = *user
+	return nil
+}
+
+// UpdateUserFields sets fields on the user by struct field name; a nil value clears the field
+func (m *MemoryStore) UpdateUserFields(ctx context.Context, userID string, fields map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	user := g.users[userID]
+	user.DiscordID = userID
+	v := reflect.ValueOf(&user).Elem()
+
+	for name, value := range fields {
+		field := v.FieldByName(name)
+		if !field.IsValid() {
+			return fmt.Errorf("unknown user field %s", name)
+		}
+		if value == nil {
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+		val := reflect.ValueOf(value)
+		if !val.Type().AssignableTo(field.Type()) {
+			return fmt.Errorf("cannot assign %T to user field %s", value, name)
+		}
+		field.Set(val)
+	}
+
+	g.users[userID] = user
+	return nil
+}
+
+// Tasks
+
+func (m *MemoryStore) SaveTask(ctx context.Context, task models.Task) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	g.tasks[task.TaskID] = task
+	g.queue[task.TaskID] = task.ScheduledTime
+	return nil
+}
+
+func (m *MemoryStore) GetTask(ctx context.Context, taskID string) (models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	task, exists := g.tasks[taskID]
+	if !exists {
+		return models.Task{}, redis.Nil
+	}
+	return task, nil
+}
+
+func (m *MemoryStore) DeleteTask(ctx context.Context, taskID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	delete(g.tasks, taskID)
+	delete(g.queue, taskID)
+	delete(g.inFlight, taskID)
+	return nil
+}
+
+func (m *MemoryStore) GetTasksForUser(ctx context.Context, userID string) ([]models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	var tasks []models.Task
+	for _, task := range g.liveTasks() {
+		if task.IsForUser(userID) {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *MemoryStore) FetchAllTasks(ctx context.Context) ([]models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	return g.liveTasks(), nil
+}
+
+// liveTasks returns queued and in-flight tasks ordered by scheduled time; the caller holds the store's mutex
+func (g *memoryGuild) liveTasks() []models.Task {
+	tasks := make([]models.Task, 0, len(g.queue)+len(g.inFlight))
+	for taskID := range g.queue {
+		tasks = append(tasks, g.tasks[taskID])
+	}
+	for taskID := range g.inFlight {
+		tasks = append(tasks, g.tasks[taskID])
+	}
+	sort.Slice(tasks, func(a, b int) bool {
+		return tasks[a].ScheduledTime < tasks[b].ScheduledTime
+	})
+	return tasks
+}
+
+// Task processing
+
+func (m *MemoryStore) ClaimDueTasks(ctx context.Context, lease time.Duration, limit int) ([]models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	now := clock.Now()
+
+	var due []models.Task
+	for taskID, scheduled := range g.queue {
+		if scheduled <= now.Unix() {
+			due = append(due, g.tasks[taskID])
+		}
+	}
+	sort.Slice(due, func(a, b int) bool {
+		return due[a].ScheduledTime < due[b].ScheduledTime
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].Status = models.TaskStatusRunning
+		g.tasks[due[i].TaskID] = due[i]
+		delete(g.queue, due[i].TaskID)
+		g.inFlight[due[i].TaskID] = now.Add(lease).Unix()
+	}
+
+	return due, nil
+}
+
+func (m *MemoryStore) RequeueExpiredTasks(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	now := clock.Now().Unix()
+
+	var requeued []string
+	for taskID, expiry := range g.inFlight {
+		if expiry > now {
+			continue
+		}
+		delete(g.inFlight, taskID)
+		g.queue[taskID] = now
+		task := g.tasks[taskID]
+		task.Status = models.TaskStatusPending
+		g.tasks[taskID] = task
+		requeued = append(requeued, taskID)
+	}
+
+	return requeued, nil
+}
+
+// AckTask marks a leased task as done. Done tasks are kept so tests can inspect their result.
+func (m *MemoryStore) AckTask(ctx context.Context, taskID string, result models.TaskResult) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	if _, leased := g.inFlight[taskID]; !leased {
+		return fmt.Errorf("task %s is no longer leased", taskID)
+	}
+
+	if task, exists := g.tasks[taskID]; exists {
+		task.Status = models.TaskStatusDone
+		task.Result = result.Summary
+		g.tasks[taskID] = task
+	}
+	delete(g.queue, taskID)
+	delete(g.inFlight, taskID)
+	return nil
+}
+
+func (m *MemoryStore) RetryTask(ctx context.Context, task models.Task, runAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	if _, leased := g.inFlight[task.TaskID]; !leased {
+		return fmt.Errorf("task %s is no longer leased", task.TaskID)
+	}
+
+	task.Status = models.TaskStatusPending
+	task.ScheduledTime = runAt.Unix()
+	g.tasks[task.TaskID] = task
+	delete(g.inFlight, task.TaskID)
+	g.queue[task.TaskID] = task.ScheduledTime
+	return nil
+}
+
+func (m *MemoryStore) DeadLetterTask(ctx context.Context, task models.Task) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	task.Status = models.TaskStatusFailed
+	g.tasks[task.TaskID] = task
+	delete(g.inFlight, task.TaskID)
+	delete(g.queue, task.TaskID)
+	g.deadLetter[task.TaskID] = clock.Now().Unix()
+	return nil
+}
+
+func (m *MemoryStore) TaskQueueStats(ctx context.Context) (models.TaskQueueStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	return models.TaskQueueStats{
+		Queued:     int64(len(g.queue)),
+		InFlight:   int64(len(g.inFlight)),
+		DeadLetter: int64(len(g.deadLetter)),
+	}, nil
+}
+
+// Task management
+
+func (m *MemoryStore) GetTasksForScenario(ctx context.Context, scenario string) ([]models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	var tasks []models.Task
+	for _, task := range g.liveTasks() {
+		if task.Scenario == scenario {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
+
+func (m *MemoryStore) RescheduleTask(ctx context.Context, taskID string, runAt time.Time, actor string) (models.Task, error) {
+	return m.updateQueuedTask(ctx, taskID, models.TaskAuditReschedule, actor, func(task *models.Task) bool {
+		task.ScheduledTime = runAt.Unix()
+		return true
+	})
+}
+
+func (m *MemoryStore) RunTaskNow(ctx context.Context, taskID string, actor string) (models.Task, error) {
+	return m.updateQueuedTask(ctx, taskID, models.TaskAuditRunNow, actor, func(task *models.Task) bool {
+		task.ScheduledTime = clock.Now().Unix()
+		return true
+	})
+}
+
+func (m *MemoryStore) CancelTask(ctx context.Context, taskID string, actor string) (models.Task, error) {
+	return m.updateQueuedTask(ctx, taskID, models.TaskAuditCancel, actor, func(task *models.Task) bool {
+		task.ScheduledTime = 0
+		return false
+	})
+}
+
+// updateQueuedTask applies change to a task still waiting in the queue and records it in the
+// audit log, deleting the task if change returns false
+func (m *MemoryStore) updateQueuedTask(ctx context.Context, taskID string, action models.TaskAuditAction, actor string, change func(task *models.Task) bool) (models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	if _, queued := g.queue[taskID]; !queued {
+		if _, running := g.inFlight[taskID]; running {
+			return models.Task{}, fmt.Errorf("task %s is currently running", taskID)
+		}
+		return models.Task{}, fmt.Errorf("task %s is not waiting in the queue", taskID)
+	}
+
+	task := g.tasks[taskID]
+	entry := models.TaskAuditEntry{
+		TaskID:       taskID,
+		TaskType:     task.FunctionName,
+		Action:       action,
+		Actor:        actor,
+		PreviousTime: task.ScheduledTime,
+		Timestamp:    clock.Now().Unix(),
+	}
+
+	keep := change(&task)
+	task.Status = models.TaskStatusPending
+	entry.NewTime = task.ScheduledTime
+
+	if keep {
+		g.tasks[taskID] = task
+		g.queue[taskID] = task.ScheduledTime
+	} else {
+		delete(g.tasks, taskID)
+		delete(g.queue, taskID)
+	}
+	g.taskAudit = append([]models.TaskAuditEntry{entry}, g.taskAudit...)
+	if len(g.taskAudit) > taskAuditLimit {
+		g.taskAudit = g.taskAudit[:taskAuditLimit]
+	}
+	return task, nil
+}
+
+func (m *MemoryStore) GetTaskAuditLog(ctx context.Context, limit int64) ([]models.TaskAuditEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	entries := g.taskAudit[:min(int(limit), len(g.taskAudit))]
+	return append([]models.TaskAuditEntry(nil), entries...), nil
+}
+
+// GetDeadLetterTasks returns the dead-lettered tasks, oldest first
+func (m *MemoryStore) GetDeadLetterTasks(ctx context.Context) ([]models.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	tasks := make([]models.Task, 0, len(g.deadLetter))
+	for taskID := range g.deadLetter {
+		tasks = append(tasks, g.tasks[taskID])
+	}
+	sort.Slice(tasks, func(a, b int) bool {
+		return g.deadLetter[tasks[a].TaskID] < g.deadLetter[tasks[b].TaskID]
+	})
+	return tasks, nil
+}
+
+func (m *MemoryStore) RequeueDeadLetterTask(ctx context.Context, taskID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	if _, exists := g.deadLetter[taskID]; !exists {
+		return fmt.Errorf("task %s is not in the dead-letter queue", taskID)
+	}
+
+	task := g.tasks[taskID]
+	task.Status = models.TaskStatusPending
+	task.Retries = 0
+	task.LastError = ""
+	task.ScheduledTime = clock.Now().Unix()
+	g.tasks[taskID] = task
+	delete(g.deadLetter, taskID)
+	g.queue[taskID] = task.ScheduledTime
+	return nil
+}
+
+func (m *MemoryStore) PurgeDeadLetterTask(ctx context.Context, taskID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	if _, exists := g.deadLetter[taskID]; !exists {
+		return fmt.Errorf("task %s is not in the dead-letter queue", taskID)
+	}
+	delete(g.deadLetter, taskID)
+	delete(g.tasks, taskID)
+	return nil
+}
+
+// DeadLetterTaskIDs returns the IDs of dead-lettered tasks in every guild so tests can assert on them
+func (m *MemoryStore) DeadLetterTaskIDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := []string{}
+	for _, g := range m.guilds {
+		for taskID := range g.deadLetter {
+			ids = append(ids, taskID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// CompletedTasks returns the tasks acknowledged as done in every guild, oldest scheduled first, so tests can inspect their result
+func (m *MemoryStore) CompletedTasks() []models.Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var done []models.Task
+	for _, g := range m.guilds {
+		for _, task := range g.tasks {
+			if task.Status == models.TaskStatusDone {
+				done = append(done, task)
+			}
+		}
+	}
+	sort.Slice(done, func(a, b int) bool {
+		return done[a].ScheduledTime < done[b].ScheduledTime
+	})
+	return done
+}
+
+// Monitoring sessions
+
+func (m *MemoryStore) SaveUserMonitoring(ctx context.Context, monitoring *models.UserMonitoring) error {
+	// Build the analytics counters first so an unknown scenario fails before anything is written
+	analytics := make(map[models.MonitoringScenario]map[string]interface{}, len(monitoring.Scenarios))
+	for scenario := range monitoring.Scenarios {
+		fields, err := scenarioAnalyticsFields(scenario)
+		if err != nil {
+			return err
+		}
+		analytics[scenario] = fields
+	}
+
+	session, err := cloneMonitoring(monitoring)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	// Sessions are keyed by start time, so saving the same session again replaces it
+	sessions := g.sessions[monitoring.UserID]
+	replaced := false
+	for i := range sessions {
+		if sessions[i].StartedAt == session.StartedAt {
+			sessions[i] = session
+			replaced = true
+		}
+	}
+	if !replaced {
+		sessions = append(sessions, session)
+	}
+	g.sessions[monitoring.UserID] = sessions
+
+	for scenario, fields := range analytics {
+		key := analyticsKey(monitoring.UserID, scenario)
+		if g.analytics[key] == nil {
+			g.analytics[key] = make(map[string]int64)
+		}
+		for field := range fields {
+			g.analytics[key][field] = 0
+		}
+	}
+
+	g.tracked[monitoring.UserID] = struct{}{}
+	return nil
+}
+
+// GetUserMonitoring returns the most recent unexpired session, or nil if there is none
+func (m *MemoryStore) GetUserMonitoring(ctx context.Context, userID string) (*models.UserMonitoring, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	var latest *models.UserMonitoring
+	var live []models.UserMonitoring
+	for _, session := range g.sessions[userID] {
+		if session.IsExpired() {
+			continue
+		}
+		live = append(live, session)
+		if latest == nil || session.StartedAt > latest.StartedAt {
+			latest = &session
+		}
+	}
+	g.sessions[userID] = live
+
+	if latest == nil {
+		return nil, nil
+	}
+
+	clone, err := cloneMonitoring(latest)
+	if err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+func (m *MemoryStore) GetTrackedUsers(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	users := make([]string, 0, len(g.tracked))
+	for userID := range g.tracked {
+		users = append(users, userID)
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+func (m *MemoryStore) RemoveTrackedUser(ctx context.Context, userID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	delete(g.tracked, userID)
+	delete(g.sessions, userID)
+	for scenario := range models.ScenarioConfig {
+		delete(g.analytics, analyticsKey(userID, scenario))
+		delete(g.channels, analyticsKey(userID, scenario))
+	}
+	return nil
+}
+
+// Analytics
+
+// GetUserAnalytics sums the counters of every scenario in the user's live sessions
+func (m *MemoryStore) GetUserAnalytics(ctx context.Context, userID string) (models.UserAnalytics, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	analytics := models.UserAnalytics{UserID: userID}
+	for _, session := range g.sessions[userID] {
+		if session.IsExpired() {
+			continue
+		}
+		for scenario := range session.Scenarios {
+			scenarioAnalytics := g.scenarioAnalytics(userID, scenario)
+			analytics.Messages += scenarioAnalytics.Messages
+			analytics.VoiceJoins += scenarioAnalytics.VoiceJoins
+			analytics.Invites += scenarioAnalytics.Invites
+			if analytics.TopChannelID == "" {
+				analytics.TopChannelID = scenarioAnalytics.TopChannelID
+			}
+		}
+	}
+	return analytics, nil
+}
+
+func (m *MemoryStore) GetScenarioAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario) (models.UserAnalytics, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	return g.scenarioAnalytics(userID, scenario), nil
+}
+
+// scenarioAnalytics reads one scenario's counters; the caller holds the store's mutex
+func (g *memoryGuild) scenarioAnalytics(userID string, scenario models.MonitoringScenario) models.UserAnalytics {
+	key := analyticsKey(userID, scenario)
+	fields := g.analytics[key]
+
+	analytics := models.UserAnalytics{
+		UserID:     userID,
+		Messages:   fields["messages"],
+		VoiceJoins: fields["voice_joins"],
+		Invites:    fields["invites"],
+	}
+
+	var top int64
+	for channelID, count := range g.channels[key] {
+		if analytics.TopChannelID == "" || count > top || (count == top && channelID < analytics.TopChannelID) {
+			analytics.TopChannelID = channelID
+			top = count
+		}
+	}
+
+	return analytics
+}
+
+func (m *MemoryStore) UpdateUserAnalytics(ctx context.Context, userID, scenario string, messages, voiceJoins, invites int64, topChannelID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	key := analyticsKey(userID, models.MonitoringScenario(scenario))
+	if g.analytics[key] == nil {
+		g.analytics[key] = make(map[string]int64)
+	}
+	g.analytics[key]["messages"] = messages
+	g.analytics[key]["voice_joins"] = voiceJoins
+	g.analytics[key]["invites"] = invites
+	return nil
+}
+
+func (m *MemoryStore) IncrementAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario, field string, amount int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	key := analyticsKey(userID, scenario)
+	if g.analytics[key] == nil {
+		g.analytics[key] = make(map[string]int64)
+	}
+	g.analytics[key][field] += int64(amount)
+	return nil
+}
+
+func (m *MemoryStore) IncrementChannelCount(ctx context.Context, userID string, scenario models.MonitoringScenario, channelID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	key := analyticsKey(userID, scenario)
+	if g.channels[key] == nil {
+		g.channels[key] = make(map[string]int64)
+	}
+	g.channels[key][channelID]++
+	return nil
+}
+
+// cloneMonitoring copies a session through JSON, as Redis would, so callers never share its scenario map
+func cloneMonitoring(monitoring *models.UserMonitoring) (models.UserMonitoring, error) {
+	data, err := json.Marshal(monitoring)
+	if err != nil {
+		return models.UserMonitoring{}, err
+	}
+
+	var clone models.UserMonitoring
+	err = json.Unmarshal(data, &clone)
+	return clone, err
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func (m *MemoryStore) SavePendingDiscordRequests(ctx context.Context, requests []models.PendingDiscordRequest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.discordBacklog = append(m.discordBacklog, requests...)
+	return nil
+}
+
+func (m *MemoryStore) TakePendingDiscordRequests(ctx context.Context) ([]models.PendingDiscordRequest, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	requests := m.discordBacklog
+	m.discordBacklog = nil
+	return requests, nil
+}
+
+// Runtime configuration
+
+func (m *MemoryStore) GetConfigValues(ctx context.Context) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	values := make(map[string]string, len(g.config))
+	for key, value := range g.config {
+		values[key] = value
+	}
+	return values, nil
+}
+
+func (m *MemoryStore) SetConfigValue(ctx context.Context, change models.ConfigChange) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	g.config[change.Key] = change.NewValue
+	g.configHistory = append([]models.ConfigChange{change}, g.configHistory...)
+	if len(g.configHistory) > configHistoryLimit {
+		g.configHistory = g.configHistory[:configHistoryLimit]
+	}
+	return nil
+}
+
+func (m *MemoryStore) GetConfigHistory(ctx context.Context, limit int64) ([]models.ConfigChange, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	n := min(int(limit), len(g.configHistory))
+	return append([]models.ConfigChange{}, g.configHistory[:n]...), nil
+}
+
+func (m *MemoryStore) SeedConfigValues(ctx context.Context, values map[string]string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.guild(ctx)
+
+	var seeded []string
+	for key, value := range values {
+		if _, exists := g.config[key]; exists {
+			continue
+		}
+		g.config[key] = value
+		seeded = append(seeded, key)
+	}
+	return seeded, nil
+}
+
+// Health
+
+func (m *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
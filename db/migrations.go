@@ -0,0 +1,190 @@
+package db
+
+import (
+	"astralHRBot/guilds"
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// migrationsKey is the set of migration names that have already been applied
+const migrationsKey = "migrations"
+
+// migration is a one-off data change that runs once per Redis instance
+type migration struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// migrations run in order; append new ones to the end and never rename existing ones
+var migrations = []migration{
+	{"backfill_task_indexes", backfillTaskIndexes},
+	{"namespace_keys_by_guild", namespaceKeysByGuild},
+}
+
+// RunMigrations applies any migrations that have not run yet.
+// Task handlers must be registered first because some migrations decode task params.
+func RunMigrations(ctx context.Context) error {
+	if err := models.ValidateTaskRegistry(); err != nil {
+		return fmt.Errorf("cannot run migrations before the task handlers are registered: %w", err)
+	}
+
+	for _, m := range migrations {
+		applied, err := RedisDB.SIsMember(ctx, migrationsKey, m.name).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", m.name, err)
+		}
+		if applied {
+			continue
+		}
+
+		logger.Info(logger.LogData{
+			"action":    "run_migrations",
+			"message":   "Applying migration",
+			"migration": m.name,
+		})
+
+		if err := m.run(ctx); err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.name, err)
+		}
+
+		if err := RedisDB.SAdd(ctx, migrationsKey, m.name).Err(); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+// backfillTaskIndexes adds every queued and in-flight task to the per-user and per-scenario index sets
+func backfillTaskIndexes(ctx context.Context) error {
+	tasks, err := FetchAllTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	pipe := RedisDB.TxPipeline()
+	for _, task := range tasks {
+		indexTask(ctx, pipe, task)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	logger.Info(logger.LogData{
+		"action":  "backfill_task_indexes",
+		"message": "Backfilled task indexes",
+		"tasks":   len(tasks),
+	})
+
+	return nil
+}
+
+// guildKeyPrefixes and guildKeyNames are the key families the bot keeps per guild. Only keys in
+// these families are moved by namespaceKeysByGuild, so anything else sharing the Redis instance,
+// and keys shared by every guild like the migrations set and the Discord request backlog, stay
+// where they are.
+var (
+	guildKeyPrefixes = []string{
+		"User:",     // user records
+		"task:",     // task blobs
+		"user:",     // analytics, channel counts, monitoring sessions and task indexes
+		"scenario:", // scenario task indexes
+	}
+	guildKeyNames = []string{
+		taskQueueKey,
+		taskInFlightKey,
+		taskDeadLetterKey,
+		taskQuarantineKey,
+		taskAuditKey,
+		trackedUsersKey,
+		configKey,
+		configHistoryKey,
+	}
+)
+
+// isGuildKey reports whether an unnamespaced key belongs to one of the bot's per-guild key families
+func isGuildKey(key string) bool {
+	for _, prefix := range guildKeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	for _, name := range guildKeyNames {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaceKeysByGuild moves the keys written before the bot served several guilds into the home
+// guild's namespace
+func namespaceKeysByGuild(ctx context.Context) error {
+	var keys []string
+	iter := RedisDB.Scan(ctx, 0, "*", consistencyScanCount).Iterator()
+	for iter.Next(ctx) {
+		if key := iter.Val(); isGuildKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	home := guilds.Home()
+	if home == "" {
+		return errors.New("GUILD_ID must be set to move existing data into its guild's namespace")
+	}
+	prefix := guildPrefix(home)
+
+	for _, key := range keys {
+		moved, err := RedisDB.RenameNX(ctx, key, prefix+key).Result()
+		if err != nil {
+			return fmt.Errorf("failed to move %s: %w", key, err)
+		}
+		if !moved {
+			logger.Warn(logger.LogData{
+				"action":  "namespace_keys_by_guild",
+				"message": "Key already exists in the guild's namespace, leaving the old one in place",
+				"key":     key,
+			})
+			continue
+		}
+
+		// Monitoring session sets hold the keys of their sessions, which have just moved too
+		if strings.HasSuffix(key, ":monitoring_sessions") {
+			sessionKeys, err := RedisDB.SMembers(ctx, prefix+key).Result()
+			if err != nil {
+				return err
+			}
+			pipe := RedisDB.TxPipeline()
+			for _, sessionKey := range sessionKeys {
+				if strings.HasPrefix(sessionKey, "guild:") {
+					continue
+				}
+				pipe.SRem(ctx, prefix+key, sessionKey)
+				pipe.SAdd(ctx, prefix+key, prefix+sessionKey)
+			}
+			if _, err := pipe.Exec(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	logger.Info(logger.LogData{
+		"action":   "namespace_keys_by_guild",
+		"message":  "Moved existing keys into the home guild's namespace",
+		"guild_id": home,
+		"keys":     len(keys),
+	})
+
+	// Instances that never ran backfill_task_indexes had no indexes to move
+	return backfillTaskIndexes(WithGuild(ctx, home))
+}
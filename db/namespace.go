@@ -0,0 +1,36 @@
+package db
+
+import (
+	"astralHRBot/guilds"
+	"context"
+)
+
+// guildContextKey is the context key WithGuild stores the guild ID under
+type guildContextKey struct{}
+
+// WithGuild returns a context whose reads and writes go to a guild's namespace
+func WithGuild(ctx context.Context, guildID string) context.Context {
+	return context.WithValue(ctx, guildContextKey{}, guildID)
+}
+
+// GuildFromContext returns the guild set with WithGuild, or the home guild if none was set
+func GuildFromContext(ctx context.Context) string {
+	if guildID, ok := ctx.Value(guildContextKey{}).(string); ok && guildID != "" {
+		return guildID
+	}
+	return guilds.Home()
+}
+
+// guildPrefix is the start of every key in a guild's namespace
+func guildPrefix(guildID string) string {
+	return "guild:" + guildID + ":"
+}
+
+// guildKey namespaces a key to the guild in ctx
+func guildKey(ctx context.Context, name string) string {
+	guildID := GuildFromContext(ctx)
+	if guildID == "" {
+		return name
+	}
+	return guildPrefix(guildID) + name
+}
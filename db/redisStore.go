@@ -0,0 +1,163 @@
+package db
+
+import (
+	"astralHRBot/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// RedisStore implements Store on top of the Redis client set up by InitRedis
+type RedisStore struct{}
+
+func (RedisStore) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	return GetUserFromRedis(ctx, userID)
+}
+
+func (RedisStore) SaveUser(ctx context.Context, user *models.User) error {
+	return SaveUserToRedis(ctx, user)
+}
+
+func (RedisStore) UpdateUserFields(ctx context.Context, userID string, fields map[string]interface{}) error {
+	return UpdateHashFields(ctx, guildKey(ctx, "User:"+userID), fields)
+}
+
+func (RedisStore) SaveTask(ctx context.Context, task models.Task) error {
+	return SaveTaskToRedis(ctx, task)
+}
+
+func (RedisStore) GetTask(ctx context.Context, taskID string) (models.Task, error) {
+	return GetTask(ctx, taskID)
+}
+
+func (RedisStore) DeleteTask(ctx context.Context, taskID string) error {
+	return DeleteTaskFromRedis(ctx, taskID)
+}
+
+func (RedisStore) GetTasksForUser(ctx context.Context, userID string) ([]models.Task, error) {
+	return GetTasksForUser(ctx, userID)
+}
+
+func (RedisStore) FetchAllTasks(ctx context.Context) ([]models.Task, error) {
+	return FetchAllTasks(ctx)
+}
+
+func (RedisStore) ClaimDueTasks(ctx context.Context, lease time.Duration, limit int) ([]models.Task, error) {
+	return ClaimDueTasks(ctx, lease, limit)
+}
+
+func (RedisStore) RequeueExpiredTasks(ctx context.Context) ([]string, error) {
+	return RequeueExpiredTasks(ctx)
+}
+
+func (RedisStore) AckTask(ctx context.Context, taskID string, result models.TaskResult) error {
+	return AckTask(ctx, taskID, result)
+}
+
+func (RedisStore) RetryTask(ctx context.Context, task models.Task, runAt time.Time) error {
+	return RetryTask(ctx, task, runAt)
+}
+
+func (RedisStore) DeadLetterTask(ctx context.Context, task models.Task) error {
+	return DeadLetterTask(ctx, task)
+}
+
+func (RedisStore) TaskQueueStats(ctx context.Context) (models.TaskQueueStats, error) {
+	return GetTaskQueueStats(ctx)
+}
+
+func (RedisStore) GetTasksForScenario(ctx context.Context, scenario string) ([]models.Task, error) {
+	return GetTasksForScenario(ctx, scenario)
+}
+
+func (RedisStore) RescheduleTask(ctx context.Context, taskID string, runAt time.Time, actor string) (models.Task, error) {
+	return RescheduleTask(ctx, taskID, runAt, actor)
+}
+
+func (RedisStore) RunTaskNow(ctx context.Context, taskID string, actor string) (models.Task, error) {
+	return RunTaskNow(ctx, taskID, actor)
+}
+
+func (RedisStore) CancelTask(ctx context.Context, taskID string, actor string) (models.Task, error) {
+	return CancelTask(ctx, taskID, actor)
+}
+
+func (RedisStore) GetTaskAuditLog(ctx context.Context, limit int64) ([]models.TaskAuditEntry, error) {
+	return GetTaskAuditLog(ctx, limit)
+}
+
+func (RedisStore) GetDeadLetterTasks(ctx context.Context) ([]models.Task, error) {
+	return GetDeadLetterTasks(ctx)
+}
+
+func (RedisStore) RequeueDeadLetterTask(ctx context.Context, taskID string) error {
+	return RequeueDeadLetterTask(ctx, taskID)
+}
+
+func (RedisStore) PurgeDeadLetterTask(ctx context.Context, taskID string) error {
+	return PurgeDeadLetterTask(ctx, taskID)
+}
+
+func (RedisStore) SaveUserMonitoring(ctx context.Context, monitoring *models.UserMonitoring) error {
+	return SaveUserMonitoring(ctx, monitoring)
+}
+
+func (RedisStore) GetUserMonitoring(ctx context.Context, userID string) (*models.UserMonitoring, error) {
+	return GetUserMonitoring(ctx, userID)
+}
+
+func (RedisStore) GetTrackedUsers(ctx context.Context) ([]string, error) {
+	return GetTrackedUsers(ctx)
+}
+
+func (RedisStore) RemoveTrackedUser(ctx context.Context, userID string) error {
+	return RemoveTrackedUser(ctx, userID)
+}
+
+func (RedisStore) GetUserAnalytics(ctx context.Context, userID string) (models.UserAnalytics, error) {
+	return GetUserAnalytics(ctx, userID)
+}
+
+func (RedisStore) GetScenarioAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario) (models.UserAnalytics, error) {
+	return GetScenarioAnalytics(ctx, userID, scenario)
+}
+
+func (RedisStore) UpdateUserAnalytics(ctx context.Context, userID, scenario string, messages, voiceJoins, invites int64, topChannelID string) error {
+	return UpdateUserAnalytics(ctx, userID, scenario, messages, voiceJoins, invites, topChannelID)
+}
+
+func (RedisStore) IncrementAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario, field string, amount int) error {
+	return IncreaseAttributeCount(ctx, guildKey(ctx, fmt.Sprintf("user:%s:analytics:%s", userID, scenario)), field, amount)
+}
+
+func (RedisStore) IncrementChannelCount(ctx context.Context, userID string, scenario models.MonitoringScenario, channelID string) error {
+	return IncreaseChannelCount(ctx, userID, channelID, string(scenario))
+}
+
+func (RedisStore) SavePendingDiscordRequests(ctx context.Context, requests []models.PendingDiscordRequest) error {
+	return SavePendingDiscordRequests(ctx, requests)
+}
+
+func (RedisStore) TakePendingDiscordRequests(ctx context.Context) ([]models.PendingDiscordRequest, error) {
+	return TakePendingDiscordRequests(ctx)
+}
+
+func (RedisStore) GetConfigValues(ctx context.Context) (map[string]string, error) {
+	return GetConfigValues(ctx)
+}
+
+func (RedisStore) SetConfigValue(ctx context.Context, change models.ConfigChange) error {
+	return SetConfigValue(ctx, change)
+}
+
+func (RedisStore) GetConfigHistory(ctx context.Context, limit int64) ([]models.ConfigChange, error) {
+	return GetConfigHistory(ctx, limit)
+}
+
+func (RedisStore) SeedConfigValues(ctx context.Context, values map[string]string) ([]string, error) {
+	return SeedConfigValues(ctx, values)
+}
+
+func (RedisStore) Ping(ctx context.Context) error {
+	return RedisDB.Ping(ctx).Err()
+}
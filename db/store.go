@@ -0,0 +1,79 @@
+package db
+
+import (
+	"astralHRBot/models"
+	"context"
+	"time"
+)
+
+// Store is the persistence layer used by handlers, tasks, the monitoring tracker and the task processor.
+// RedisStore is used in production; MemoryStore keeps everything in process for tests.
+type Store interface {
+	// Users
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	SaveUser(ctx context.Context, user *models.User) error
+	UpdateUserFields(ctx context.Context, userID string, fields map[string]interface{}) error
+
+	// Tasks
+	SaveTask(ctx context.Context, task models.Task) error
+	GetTask(ctx context.Context, taskID string) (models.Task, error)
+	DeleteTask(ctx context.Context, taskID string) error
+	GetTasksForUser(ctx context.Context, userID string) ([]models.Task, error)
+	FetchAllTasks(ctx context.Context) ([]models.Task, error)
+
+	// Task processing
+	ClaimDueTasks(ctx context.Context, lease time.Duration, limit int) ([]models.Task, error)
+	RequeueExpiredTasks(ctx context.Context) ([]string, error)
+	AckTask(ctx context.Context, taskID string, result models.TaskResult) error
+	RetryTask(ctx context.Context, task models.Task, runAt time.Time) error
+	DeadLetterTask(ctx context.Context, task models.Task) error
+	TaskQueueStats(ctx context.Context) (models.TaskQueueStats, error)
+
+	// Task management
+	GetTasksForScenario(ctx context.Context, scenario string) ([]models.Task, error)
+	RescheduleTask(ctx context.Context, taskID string, runAt time.Time, actor string) (models.Task, error)
+	RunTaskNow(ctx context.Context, taskID string, actor string) (models.Task, error)
+	CancelTask(ctx context.Context, taskID string, actor string) (models.Task, error)
+	GetTaskAuditLog(ctx context.Context, limit int64) ([]models.TaskAuditEntry, error)
+	GetDeadLetterTasks(ctx context.Context) ([]models.Task, error)
+	RequeueDeadLetterTask(ctx context.Context, taskID string) error
+	PurgeDeadLetterTask(ctx context.Context, taskID string) error
+
+	// Monitoring sessions
+	SaveUserMonitoring(ctx context.Context, monitoring *models.UserMonitoring) error
+	GetUserMonitoring(ctx context.Context, userID string) (*models.UserMonitoring, error)
+	GetTrackedUsers(ctx context.Context) ([]string, error)
+	RemoveTrackedUser(ctx context.Context, userID string) error
+
+	// Analytics
+	GetUserAnalytics(ctx context.Context, userID string) (models.UserAnalytics, error)
+	GetScenarioAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario) (models.UserAnalytics, error)
+	UpdateUserAnalytics(ctx context.Context, userID, scenario string, messages, voiceJoins, invites int64, topChannelID string) error
+	IncrementAnalytics(ctx context.Context, userID string, scenario models.MonitoringScenario, field string, amount int) error
+	IncrementChannelCount(ctx context.Context, userID string, scenario models.MonitoringScenario, channelID string) error
+
+	// Discord API backlog
+	SavePendingDiscordRequests(ctx context.Context, requests []models.PendingDiscordRequest) error
+	TakePendingDiscordRequests(ctx context.Context) ([]models.PendingDiscordRequest, error)
+
+	// Runtime configuration
+	GetConfigValues(ctx context.Context) (map[string]string, error)
+	SetConfigValue(ctx context.Context, change models.ConfigChange) error
+	GetConfigHistory(ctx context.Context, limit int64) ([]models.ConfigChange, error)
+	SeedConfigValues(ctx context.Context, values map[string]string) ([]string, error)
+
+	// Health
+	Ping(ctx context.Context) error
+}
+
+var store Store = RedisStore{}
+
+// GetStore returns the active store
+func GetStore() Store {
+	return store
+}
+
+// SetStore replaces the active store, for example with a MemoryStore in tests
+func SetStore(s Store) {
+	store = s
+}
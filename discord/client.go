@@ -0,0 +1,128 @@
+package discord
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// DiscordClient is the subset of the Discord API the bot uses. Method signatures match
+// *discordgo.Session so the live session can be wrapped without translating any arguments.
+type DiscordClient interface {
+	// Members and roles
+	GuildMember(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error)
+	GuildMemberRoleAdd(guildID, userID, roleID string, options ...discordgo.RequestOption) error
+	GuildMemberRoleRemove(guildID, userID, roleID string, options ...discordgo.RequestOption) error
+	GuildRoles(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Role, error)
+	User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error)
+
+	// Messages
+	ChannelMessages(channelID string, limit int, beforeID, afterID, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error)
+	ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error)
+	UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+
+	// Channels and threads
+	Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	GuildChannels(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error)
+	ChannelEditComplex(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	ForumThreadStart(channelID, name string, archiveDuration int, content string, options ...discordgo.RequestOption) (*discordgo.Channel, error)
+	GuildThreadsActive(guildID string, options ...discordgo.RequestOption) (*discordgo.ThreadsList, error)
+	ThreadsArchived(channelID string, before *time.Time, limit int, options ...discordgo.RequestOption) (*discordgo.ThreadsList, error)
+
+	// Audit log
+	GuildAuditLog(guildID, userID, beforeID string, actionType, limit int, options ...discordgo.RequestOption) (*discordgo.GuildAuditLog, error)
+
+	// Gateway state
+	BotUserID() string
+	StateGuildIDs() []string
+	StateGuild(guildID string) (*discordgo.Guild, error)
+
+	// Rate limits
+	RateLimitWait(bucketID string) time.Duration
+}
+
+var (
+	client   DiscordClient
+	clientMu sync.RWMutex
+)
+
+// GetClient returns the Discord client used by handlers, tasks and monitoring
+func GetClient() DiscordClient {
+	clientMu.RLock()
+	defer clientMu.RUnlock()
+	return client
+}
+
+// SetClient replaces the Discord client, e.g. with a FakeGuild in tests
+func SetClient(c DiscordClient) {
+	clientMu.Lock()
+	client = c
+	clientMu.Unlock()
+}
+
+// sessionClient adapts a live *discordgo.Session to DiscordClient
+type sessionClient struct {
+	*discordgo.Session
+}
+
+// FromSession wraps a discordgo session. Gateway callbacks receive the raw session and use this
+// to hand it on to code written against DiscordClient.
+func FromSession(s *discordgo.Session) DiscordClient {
+	if s == nil {
+		return nil
+	}
+	return sessionClient{s}
+}
+
+func (c sessionClient) BotUserID() string {
+	if c.State == nil || c.State.User == nil {
+		return ""
+	}
+	return c.State.User.ID
+}
+
+func (c sessionClient) StateGuildIDs() []string {
+	if c.State == nil {
+		return nil
+	}
+
+	c.State.RLock()
+	defer c.State.RUnlock()
+
+	ids := make([]string, 0, len(c.State.Guilds))
+	for _, guild := range c.State.Guilds {
+		ids = append(ids, guild.ID)
+	}
+	return ids
+}
+
+func (c sessionClient) StateGuild(guildID string) (*discordgo.Guild, error) {
+	if c.State == nil {
+		return nil, fmt.Errorf("Discord state is not available")
+	}
+	return c.State.Guild(guildID)
+}
+
+// bucketBusyWait is reported for a bucket that has a request in flight, since its remaining
+// capacity is not known until that request's response headers arrive
+const bucketBusyWait = 50 * time.Millisecond
+
+// RateLimitWait reads discordgo's bucket for bucketID and returns how long until a request on it
+// would be sent without sleeping
+func (c sessionClient) RateLimitWait(bucketID string) time.Duration {
+	if c.Ratelimiter == nil {
+		return 0
+	}
+
+	bucket := c.Ratelimiter.GetBucket(bucketID)
+	// discordgo holds the bucket lock for the whole request, so a locked bucket is in use
+	if !bucket.TryLock() {
+		return bucketBusyWait
+	}
+	defer bucket.Unlock()
+
+	return c.Ratelimiter.GetWaitTime(bucket, 1)
+}
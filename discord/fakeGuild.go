@@ -0,0 +1,833 @@
+package discord
+
+import (
+	"astralHRBot/clock"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// discordEpochMs is the Discord epoch (2015-01-01) in unix milliseconds, used to mint snowflakes
+const discordEpochMs = 1420070400000
+
+// Call is one request made against a FakeGuild. Only the fields relevant to the method are set.
+type Call struct {
+	Method    string
+	GuildID   string
+	ChannelID string
+	UserID    string
+	RoleID    string
+	Name      string
+	Content   string
+	Embed     *discordgo.MessageEmbed
+	Edit      *discordgo.ChannelEdit
+}
+
+// FakeGuild implements DiscordClient for a single in-memory guild. It applies requests to its
+// members, channels, threads and messages the way Discord would and records every call so tests
+// can assert on what the bot did.
+type FakeGuild struct {
+	mu sync.Mutex
+
+	guildID string
+	bot     *discordgo.User
+
+	members  map[string]*discordgo.Member
+	roles    map[string]*discordgo.Role
+	channels map[string]*discordgo.Channel // text channels, forums, threads and DMs by ID
+	messages map[string][]*discordgo.Message
+	dms      map[string]string // user ID -> DM channel ID
+	auditLog []*discordgo.AuditLogEntry
+
+	calls    []Call
+	failures map[string]error
+
+	sequence int64
+}
+
+// NewFakeGuild creates an empty guild whose bot user has the given ID
+func NewFakeGuild(guildID, botUserID string) *FakeGuild {
+	return &FakeGuild{
+		guildID:  guildID,
+		bot:      &discordgo.User{ID: botUserID, Username: "bot", Bot: true},
+		members:  make(map[string]*discordgo.Member),
+		roles:    make(map[string]*discordgo.Role),
+		channels: make(map[string]*discordgo.Channel),
+		messages: make(map[string][]*discordgo.Message),
+		dms:      make(map[string]string),
+		failures: make(map[string]error),
+	}
+}
+
+// Fail makes every call to method return err until it is cleared with a nil error
+func (f *FakeGuild) Fail(method string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.failures, method)
+		return
+	}
+	f.failures[method] = err
+}
+
+// Guild setup
+
+// AddMember adds a member with the given roles and returns a copy of it
+func (f *FakeGuild) AddMember(userID, username string, roleIDs ...string) *discordgo.Member {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	member := &discordgo.Member{
+		GuildID:  f.guildID,
+		JoinedAt: clock.Now(),
+		User:     &discordgo.User{ID: userID, Username: username, GlobalName: username},
+		Roles:    append([]string{}, roleIDs...),
+	}
+	f.members[userID] = member
+	return cloneMember(member)
+}
+
+// RemoveMember removes a member from the guild, as when they leave the server, and returns them
+func (f *FakeGuild) RemoveMember(userID string) (*discordgo.Member, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	member, exists := f.members[userID]
+	if !exists {
+		return nil, false
+	}
+	delete(f.members, userID)
+	return cloneMember(member), true
+}
+
+// ChangeRoles applies a role change made by someone other than the bot, such as an admin or an
+// auth service, and logs it in the audit log under actorID. It returns the member before and after
+// the change so the caller can build the matching gateway event. The change is not recorded as a call.
+func (f *FakeGuild) ChangeRoles(actorID, userID string, add, remove []string) (before, after *discordgo.Member, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	member, err := f.member(f.guildID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	before = cloneMember(member)
+
+	var roles []string
+	for _, roleID := range member.Roles {
+		if !containsString(remove, roleID) {
+			roles = append(roles, roleID)
+		}
+	}
+	for _, roleID := range add {
+		if !containsString(roles, roleID) {
+			roles = append(roles, roleID)
+		}
+	}
+	member.Roles = roles
+
+	f.addAuditLogEntry(actorID, userID, discordgo.AuditLogActionMemberRoleUpdate)
+	return before, cloneMember(member), nil
+}
+
+// AddRole adds a role at the given position in the role list; higher positions rank higher
+func (f *FakeGuild) AddRole(roleID, name string, position int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.roles[roleID] = &discordgo.Role{ID: roleID, Name: name, Position: position}
+}
+
+// AddTextChannel adds a text channel
+func (f *FakeGuild) AddTextChannel(channelID, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.channels[channelID] = &discordgo.Channel{
+		ID:      channelID,
+		GuildID: f.guildID,
+		Name:    name,
+		Type:    discordgo.ChannelTypeGuildText,
+	}
+}
+
+// AddForumChannel adds a forum channel offering the given tags
+func (f *FakeGuild) AddForumChannel(channelID, name string, tags ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	available := make([]discordgo.ForumTag, 0, len(tags))
+	for _, tag := range tags {
+		available = append(available, discordgo.ForumTag{ID: f.nextID(), Name: tag})
+	}
+
+	f.channels[channelID] = &discordgo.Channel{
+		ID:            channelID,
+		GuildID:       f.guildID,
+		Name:          name,
+		Type:          discordgo.ChannelTypeGuildForum,
+		AvailableTags: available,
+	}
+}
+
+// PostMessage posts a message as a guild member, e.g. to give analytics something to count
+func (f *FakeGuild) PostMessage(channelID, userID, content string) (*discordgo.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	author := &discordgo.User{ID: userID}
+	if member, exists := f.members[userID]; exists {
+		author = member.User
+	}
+	return f.postMessage(channelID, author, content, nil)
+}
+
+// AddAuditLogEntry records an action in the audit log as if userID had performed it on targetID
+func (f *FakeGuild) AddAuditLogEntry(userID, targetID string, action discordgo.AuditLogAction) *discordgo.AuditLogEntry {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.addAuditLogEntry(userID, targetID, action)
+}
+
+// Assertions
+
+// Calls returns every call made so far, oldest first
+func (f *FakeGuild) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call{}, f.calls...)
+}
+
+// CallsTo returns the calls made to a single method, oldest first
+func (f *FakeGuild) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var calls []Call
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// ResetCalls forgets the calls recorded so far without touching guild state
+func (f *FakeGuild) ResetCalls() {
+	f.mu.Lock()
+	f.calls = nil
+	f.mu.Unlock()
+}
+
+// Messages returns the messages in a channel or thread, oldest first
+func (f *FakeGuild) Messages(channelID string) []*discordgo.Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	messages := make([]*discordgo.Message, 0, len(f.messages[channelID]))
+	for _, message := range f.messages[channelID] {
+		messages = append(messages, cloneMessage(message))
+	}
+	return messages
+}
+
+// DirectMessages returns the direct messages the bot has sent to a user, oldest first
+func (f *FakeGuild) DirectMessages(userID string) []*discordgo.Message {
+	f.mu.Lock()
+	channelID, exists := f.dms[userID]
+	f.mu.Unlock()
+
+	if !exists {
+		return nil
+	}
+	return f.Messages(channelID)
+}
+
+// Threads returns the threads started in a forum channel, oldest first
+func (f *FakeGuild) Threads(parentID string) []*discordgo.Channel {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var threads []*discordgo.Channel
+	for _, channel := range f.channels {
+		if channel.IsThread() && channel.ParentID == parentID {
+			threads = append(threads, cloneChannel(channel))
+		}
+	}
+	sort.Slice(threads, func(a, b int) bool {
+		return snowflakeLess(threads[a].ID, threads[b].ID)
+	})
+	return threads
+}
+
+// MemberRoles returns a member's current roles, or nil if they are not in the guild
+func (f *FakeGuild) MemberRoles(userID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	member, exists := f.members[userID]
+	if !exists {
+		return nil
+	}
+	return append([]string{}, member.Roles...)
+}
+
+// TagNames resolves the tags applied to a thread to their names
+func (f *FakeGuild) TagNames(threadID string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	thread, exists := f.channels[threadID]
+	if !exists {
+		return nil
+	}
+	parent, exists := f.channels[thread.ParentID]
+	if !exists {
+		return nil
+	}
+
+	var names []string
+	for _, tagID := range thread.AppliedTags {
+		for _, tag := range parent.AvailableTags {
+			if tag.ID == tagID {
+				names = append(names, tag.Name)
+			}
+		}
+	}
+	return names
+}
+
+// DiscordClient: members and roles
+
+func (f *FakeGuild) GuildMember(guildID, userID string, options ...discordgo.RequestOption) (*discordgo.Member, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "GuildMember", GuildID: guildID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	member, err := f.member(guildID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return cloneMember(member), nil
+}
+
+func (f *FakeGuild) GuildMemberRoleAdd(guildID, userID, roleID string, options ...discordgo.RequestOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "GuildMemberRoleAdd", GuildID: guildID, UserID: userID, RoleID: roleID}); err != nil {
+		return err
+	}
+	member, err := f.member(guildID, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range member.Roles {
+		if existing == roleID {
+			return nil
+		}
+	}
+	member.Roles = append(member.Roles, roleID)
+	f.addAuditLogEntry(f.bot.ID, userID, discordgo.AuditLogActionMemberRoleUpdate)
+	return nil
+}
+
+func (f *FakeGuild) GuildMemberRoleRemove(guildID, userID, roleID string, options ...discordgo.RequestOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "GuildMemberRoleRemove", GuildID: guildID, UserID: userID, RoleID: roleID}); err != nil {
+		return err
+	}
+	member, err := f.member(guildID, userID)
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range member.Roles {
+		if existing == roleID {
+			member.Roles = append(member.Roles[:i], member.Roles[i+1:]...)
+			f.addAuditLogEntry(f.bot.ID, userID, discordgo.AuditLogActionMemberRoleUpdate)
+			break
+		}
+	}
+	return nil
+}
+
+func (f *FakeGuild) GuildRoles(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Role, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "GuildRoles", GuildID: guildID}); err != nil {
+		return nil, err
+	}
+	if guildID != f.guildID {
+		return nil, fmt.Errorf("unknown guild %s", guildID)
+	}
+	return f.sortedRoles(), nil
+}
+
+func (f *FakeGuild) User(userID string, options ...discordgo.RequestOption) (*discordgo.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "User", UserID: userID}); err != nil {
+		return nil, err
+	}
+	if userID == "@me" || userID == f.bot.ID {
+		user := *f.bot
+		return &user, nil
+	}
+	if member, exists := f.members[userID]; exists {
+		user := *member.User
+		return &user, nil
+	}
+	return nil, fmt.Errorf("unknown user %s", userID)
+}
+
+// DiscordClient: messages
+
+func (f *FakeGuild) ChannelMessages(channelID string, limit int, beforeID, afterID, aroundID string, options ...discordgo.RequestOption) ([]*discordgo.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "ChannelMessages", ChannelID: channelID}); err != nil {
+		return nil, err
+	}
+	if _, exists := f.channels[channelID]; !exists {
+		return nil, fmt.Errorf("unknown channel %s", channelID)
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	// Discord returns the newest messages first
+	var page []*discordgo.Message
+	stored := f.messages[channelID]
+	for i := len(stored) - 1; i >= 0 && len(page) < limit; i-- {
+		message := stored[i]
+		if beforeID != "" && !snowflakeLess(message.ID, beforeID) {
+			continue
+		}
+		if afterID != "" && !snowflakeLess(afterID, message.ID) {
+			continue
+		}
+		page = append(page, cloneMessage(message))
+	}
+	return page, nil
+}
+
+func (f *FakeGuild) ChannelMessageSend(channelID string, content string, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "ChannelMessageSend", ChannelID: channelID, Content: content}); err != nil {
+		return nil, err
+	}
+	return f.postMessage(channelID, f.bot, content, nil)
+}
+
+func (f *FakeGuild) ChannelMessageSendEmbed(channelID string, embed *discordgo.MessageEmbed, options ...discordgo.RequestOption) (*discordgo.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "ChannelMessageSendEmbed", ChannelID: channelID, Embed: embed}); err != nil {
+		return nil, err
+	}
+	return f.postMessage(channelID, f.bot, "", embed)
+}
+
+func (f *FakeGuild) UserChannelCreate(recipientID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "UserChannelCreate", UserID: recipientID}); err != nil {
+		return nil, err
+	}
+
+	member, exists := f.members[recipientID]
+	if !exists {
+		return nil, fmt.Errorf("unknown user %s", recipientID)
+	}
+
+	channelID, exists := f.dms[recipientID]
+	if !exists {
+		channelID = f.nextID()
+		f.dms[recipientID] = channelID
+		f.channels[channelID] = &discordgo.Channel{
+			ID:         channelID,
+			Type:       discordgo.ChannelTypeDM,
+			Recipients: []*discordgo.User{member.User},
+		}
+	}
+	return cloneChannel(f.channels[channelID]), nil
+}
+
+// DiscordClient: channels and threads
+
+func (f *FakeGuild) Channel(channelID string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "Channel", ChannelID: channelID}); err != nil {
+		return nil, err
+	}
+	channel, exists := f.channels[channelID]
+	if !exists {
+		return nil, fmt.Errorf("unknown channel %s", channelID)
+	}
+	return cloneChannel(channel), nil
+}
+
+func (f *FakeGuild) GuildChannels(guildID string, options ...discordgo.RequestOption) ([]*discordgo.Channel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "GuildChannels", GuildID: guildID}); err != nil {
+		return nil, err
+	}
+	if guildID != f.guildID {
+		return nil, fmt.Errorf("unknown guild %s", guildID)
+	}
+	return f.guildChannels(), nil
+}
+
+func (f *FakeGuild) ChannelEditComplex(channelID string, data *discordgo.ChannelEdit, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	edit := *data
+	if err := f.record(Call{Method: "ChannelEditComplex", ChannelID: channelID, Edit: &edit}); err != nil {
+		return nil, err
+	}
+	channel, exists := f.channels[channelID]
+	if !exists {
+		return nil, fmt.Errorf("unknown channel %s", channelID)
+	}
+
+	if data.Name != "" {
+		channel.Name = data.Name
+	}
+	if data.AppliedTags != nil {
+		channel.AppliedTags = append([]string{}, (*data.AppliedTags)...)
+	}
+	if channel.ThreadMetadata != nil {
+		if data.AutoArchiveDuration != 0 {
+			channel.ThreadMetadata.AutoArchiveDuration = data.AutoArchiveDuration
+		}
+		if data.Locked != nil {
+			channel.ThreadMetadata.Locked = *data.Locked
+		}
+		if data.Archived != nil && *data.Archived != channel.ThreadMetadata.Archived {
+			channel.ThreadMetadata.Archived = *data.Archived
+			channel.ThreadMetadata.ArchiveTimestamp = clock.Now()
+		}
+	}
+	return cloneChannel(channel), nil
+}
+
+func (f *FakeGuild) ForumThreadStart(channelID, name string, archiveDuration int, content string, options ...discordgo.RequestOption) (*discordgo.Channel, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "ForumThreadStart", ChannelID: channelID, Name: name, Content: content}); err != nil {
+		return nil, err
+	}
+	forum, exists := f.channels[channelID]
+	if !exists || forum.Type != discordgo.ChannelTypeGuildForum {
+		return nil, fmt.Errorf("unknown forum channel %s", channelID)
+	}
+
+	thread := &discordgo.Channel{
+		ID:       f.nextID(),
+		GuildID:  f.guildID,
+		ParentID: channelID,
+		Name:     name,
+		Type:     discordgo.ChannelTypeGuildPublicThread,
+		ThreadMetadata: &discordgo.ThreadMetadata{
+			AutoArchiveDuration: archiveDuration,
+			ArchiveTimestamp:    clock.Now(),
+		},
+	}
+	f.channels[thread.ID] = thread
+
+	if _, err := f.postMessage(thread.ID, f.bot, content, nil); err != nil {
+		return nil, err
+	}
+	return cloneChannel(thread), nil
+}
+
+func (f *FakeGuild) GuildThreadsActive(guildID string, options ...discordgo.RequestOption) (*discordgo.ThreadsList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "GuildThreadsActive", GuildID: guildID}); err != nil {
+		return nil, err
+	}
+	if guildID != f.guildID {
+		return nil, fmt.Errorf("unknown guild %s", guildID)
+	}
+
+	list := &discordgo.ThreadsList{}
+	for _, channel := range f.channels {
+		if channel.IsThread() && !channel.ThreadMetadata.Archived {
+			list.Threads = append(list.Threads, cloneChannel(channel))
+		}
+	}
+	sort.Slice(list.Threads, func(a, b int) bool {
+		return snowflakeLess(list.Threads[a].ID, list.Threads[b].ID)
+	})
+	return list, nil
+}
+
+func (f *FakeGuild) ThreadsArchived(channelID string, before *time.Time, limit int, options ...discordgo.RequestOption) (*discordgo.ThreadsList, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "ThreadsArchived", ChannelID: channelID}); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	var archived []*discordgo.Channel
+	for _, channel := range f.channels {
+		if !channel.IsThread() || channel.ParentID != channelID || !channel.ThreadMetadata.Archived {
+			continue
+		}
+		if before != nil && !channel.ThreadMetadata.ArchiveTimestamp.Before(*before) {
+			continue
+		}
+		archived = append(archived, channel)
+	}
+
+	// Discord returns the most recently archived threads first
+	sort.Slice(archived, func(a, b int) bool {
+		return archived[a].ThreadMetadata.ArchiveTimestamp.After(archived[b].ThreadMetadata.ArchiveTimestamp)
+	})
+
+	list := &discordgo.ThreadsList{HasMore: len(archived) > limit}
+	for i := 0; i < len(archived) && i < limit; i++ {
+		list.Threads = append(list.Threads, cloneChannel(archived[i]))
+	}
+	return list, nil
+}
+
+// DiscordClient: audit log
+
+func (f *FakeGuild) GuildAuditLog(guildID, userID, beforeID string, actionType, limit int, options ...discordgo.RequestOption) (*discordgo.GuildAuditLog, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record(Call{Method: "GuildAuditLog", GuildID: guildID, UserID: userID}); err != nil {
+		return nil, err
+	}
+	if guildID != f.guildID {
+		return nil, fmt.Errorf("unknown guild %s", guildID)
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	// Discord returns the newest entries first
+	log := &discordgo.GuildAuditLog{}
+	for i := len(f.auditLog) - 1; i >= 0 && len(log.AuditLogEntries) < limit; i-- {
+		entry := f.auditLog[i]
+		if userID != "" && entry.UserID != userID {
+			continue
+		}
+		if actionType != 0 && int(*entry.ActionType) != actionType {
+			continue
+		}
+		if beforeID != "" && !snowflakeLess(entry.ID, beforeID) {
+			continue
+		}
+		copied := *entry
+		log.AuditLogEntries = append(log.AuditLogEntries, &copied)
+	}
+	return log, nil
+}
+
+// DiscordClient: gateway state
+
+func (f *FakeGuild) BotUserID() string {
+	return f.bot.ID
+}
+
+func (f *FakeGuild) StateGuildIDs() []string {
+	return []string{f.guildID}
+}
+
+func (f *FakeGuild) StateGuild(guildID string) (*discordgo.Guild, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if guildID != f.guildID {
+		return nil, fmt.Errorf("unknown guild %s", guildID)
+	}
+
+	guild := &discordgo.Guild{ID: f.guildID, Channels: f.guildChannels(), Roles: f.sortedRoles()}
+	for _, member := range f.members {
+		guild.Members = append(guild.Members, cloneMember(member))
+	}
+	guild.MemberCount = len(guild.Members)
+	return guild, nil
+}
+
+// RateLimitWait always reports capacity; the fake guild does not rate limit
+func (f *FakeGuild) RateLimitWait(bucketID string) time.Duration {
+	return 0
+}
+
+// Internals; the caller holds f.mu
+
+// record appends a call and returns the failure configured for its method, if any
+func (f *FakeGuild) record(call Call) error {
+	f.calls = append(f.calls, call)
+	return f.failures[call.Method]
+}
+
+func (f *FakeGuild) member(guildID, userID string) (*discordgo.Member, error) {
+	if guildID != f.guildID {
+		return nil, fmt.Errorf("unknown guild %s", guildID)
+	}
+	member, exists := f.members[userID]
+	if !exists {
+		return nil, fmt.Errorf("unknown member %s", userID)
+	}
+	return member, nil
+}
+
+// guildChannels returns the guild's channels, leaving out threads and DMs, ordered by ID
+func (f *FakeGuild) guildChannels() []*discordgo.Channel {
+	var channels []*discordgo.Channel
+	for _, channel := range f.channels {
+		if channel.GuildID == f.guildID && !channel.IsThread() {
+			channels = append(channels, cloneChannel(channel))
+		}
+	}
+	sort.Slice(channels, func(a, b int) bool {
+		return channels[a].ID < channels[b].ID
+	})
+	return channels
+}
+
+// sortedRoles returns the guild's roles, lowest first
+func (f *FakeGuild) sortedRoles() []*discordgo.Role {
+	roles := make([]*discordgo.Role, 0, len(f.roles))
+	for _, role := range f.roles {
+		copied := *role
+		roles = append(roles, &copied)
+	}
+	sort.Slice(roles, func(a, b int) bool {
+		return roles[a].Position < roles[b].Position
+	})
+	return roles
+}
+
+func (f *FakeGuild) postMessage(channelID string, author *discordgo.User, content string, embed *discordgo.MessageEmbed) (*discordgo.Message, error) {
+	channel, exists := f.channels[channelID]
+	if !exists {
+		return nil, fmt.Errorf("unknown channel %s", channelID)
+	}
+
+	// Posting in an archived thread reopens it, as it does on Discord
+	if channel.IsThread() && channel.ThreadMetadata.Archived && !channel.ThreadMetadata.Locked {
+		channel.ThreadMetadata.Archived = false
+		channel.ThreadMetadata.ArchiveTimestamp = clock.Now()
+	}
+
+	message := &discordgo.Message{
+		ID:        f.nextID(),
+		ChannelID: channelID,
+		GuildID:   channel.GuildID,
+		Content:   content,
+		Timestamp: clock.Now(),
+		Author:    author,
+	}
+	if embed != nil {
+		message.Embeds = []*discordgo.MessageEmbed{embed}
+	}
+	f.messages[channelID] = append(f.messages[channelID], message)
+	return cloneMessage(message), nil
+}
+
+func (f *FakeGuild) addAuditLogEntry(userID, targetID string, action discordgo.AuditLogAction) *discordgo.AuditLogEntry {
+	entry := &discordgo.AuditLogEntry{
+		ID:         f.nextID(),
+		UserID:     userID,
+		TargetID:   targetID,
+		ActionType: &action,
+	}
+	f.auditLog = append(f.auditLog, entry)
+	copied := *entry
+	return &copied
+}
+
+// nextID mints a snowflake for the current time so IDs sort in creation order
+func (f *FakeGuild) nextID() string {
+	f.sequence++
+	ms := clock.Now().UnixMilli() - discordEpochMs
+	if ms < 0 {
+		ms = 0
+	}
+	return strconv.FormatInt(ms<<22|f.sequence&0x3FFFFF, 10)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func snowflakeLess(a, b string) bool {
+	x, errA := strconv.ParseInt(a, 10, 64)
+	y, errB := strconv.ParseInt(b, 10, 64)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b) < 0
+	}
+	return x < y
+}
+
+func cloneMember(member *discordgo.Member) *discordgo.Member {
+	copied := *member
+	user := *member.User
+	copied.User = &user
+	copied.Roles = append([]string{}, member.Roles...)
+	return &copied
+}
+
+func cloneChannel(channel *discordgo.Channel) *discordgo.Channel {
+	copied := *channel
+	if channel.ThreadMetadata != nil {
+		metadata := *channel.ThreadMetadata
+		copied.ThreadMetadata = &metadata
+	}
+	copied.AppliedTags = append([]string{}, channel.AppliedTags...)
+	copied.AvailableTags = append([]discordgo.ForumTag{}, channel.AvailableTags...)
+	return &copied
+}
+
+func cloneMessage(message *discordgo.Message) *discordgo.Message {
+	copied := *message
+	copied.Embeds = append([]*discordgo.MessageEmbed{}, message.Embeds...)
+	return &copied
+}
+
+var _ DiscordClient = (*FakeGuild)(nil)
+var _ DiscordClient = sessionClient{}
@@ -0,0 +1,45 @@
+// Package guilds lists the Discord servers the bot serves. Each guild has its own settings and its
+// own namespace in Redis, so one bot can run the same automation for several corporations.
+package guilds
+
+import (
+	"os"
+	"strings"
+)
+
+// IDs returns the guilds the bot serves, read from the comma-separated GUILD_IDS or, for a single
+// guild, GUILD_ID
+func IDs() []string {
+	value := os.Getenv("GUILD_IDS")
+	if value == "" {
+		value = os.Getenv("GUILD_ID")
+	}
+
+	var ids []string
+	for _, id := range strings.Split(value, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Home returns the first guild listed. Data stored before the bot served several guilds belongs to
+// it, and settings shared by every guild, such as the log level, are stored with it.
+func Home() string {
+	ids := IDs()
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[0]
+}
+
+// Serves reports whether guildID is one of the guilds the bot serves
+func Serves(guildID string) bool {
+	for _, id := range IDs() {
+		if id == guildID {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/handlers/middleware"
 	"astralHRBot/logger"
 	"astralHRBot/workers/eventWorker"
@@ -14,6 +15,14 @@ var messageCreateMiddleware = []MessageCreateMiddleware{
 }
 
 func MessageHandlers(s *discordgo.Session, m *discordgo.MessageCreate) {
+	HandleMessageCreate(discord.FromSession(s), m)
+}
+
+// HandleMessageCreate queues a new message for the message middleware against the given client
+func HandleMessageCreate(s discord.DiscordClient, m *discordgo.MessageCreate) {
+	if ignoreEvent(m.GuildID, "message_create") {
+		return
+	}
 	logger.Debug(logger.LogData{
 		"action":     "message_handler",
 		"message":    "Received message",
@@ -22,6 +31,7 @@ func MessageHandlers(s *discordgo.Session, m *discordgo.MessageCreate) {
 	})
 
 	eventWorker.Submit(
+		m.GuildID,
 		m.Author.ID,
 		func(e eventWorker.Event) {
 			p, t := e.Payload, e.TraceID
@@ -35,7 +45,7 @@ func MessageHandlers(s *discordgo.Session, m *discordgo.MessageCreate) {
 				return
 			}
 
-			s, ok1 := p[0].(*discordgo.Session)
+			s, ok1 := p[0].(discord.DiscordClient)
 			m, ok2 := p[1].(*discordgo.MessageCreate)
 
 			if !ok1 || !ok2 {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/handlers/middleware"
 	"astralHRBot/logger"
 	"astralHRBot/workers/eventWorker"
@@ -9,15 +10,24 @@ import (
 )
 
 func ManageGuildChanges(s *discordgo.Session, event any) {
+	HandleGuildChanges(discord.FromSession(s), event)
+}
+
+// HandleGuildChanges dispatches voice state and invite events against the given client
+func HandleGuildChanges(s discord.DiscordClient, event any) {
 	switch evt := event.(type) {
 	case *discordgo.VoiceStateUpdate:
-		handleVoiceStateUpdate(s, evt)
+		if !ignoreEvent(evt.GuildID, "guild_change") {
+			handleVoiceStateUpdate(s, evt)
+		}
 	case *discordgo.InviteCreate:
-		handleInviteCreate(s, evt)
+		if !ignoreEvent(evt.GuildID, "guild_change") {
+			handleInviteCreate(s, evt)
+		}
 	}
 }
 
-func handleVoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpdate) {
+func handleVoiceStateUpdate(s discord.DiscordClient, v *discordgo.VoiceStateUpdate) {
 	logger.Debug(logger.LogData{
 		"action":     "voice_state_update",
 		"message":    "Received voice state update",
@@ -25,12 +35,12 @@ func handleVoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpdate)
 		"channel_id": v.ChannelID,
 	})
 
-	eventWorker.Submit(v.UserID, func(e eventWorker.Event) {
+	eventWorker.Submit(v.GuildID, v.UserID, func(e eventWorker.Event) {
 		middleware.MonitorVoiceStateUpdate(s, v, e)
 	}, s, v)
 }
 
-func handleInviteCreate(s *discordgo.Session, i *discordgo.InviteCreate) {
+func handleInviteCreate(s discord.DiscordClient, i *discordgo.InviteCreate) {
 	if i.Inviter == nil {
 		return
 	}
@@ -42,7 +52,7 @@ func handleInviteCreate(s *discordgo.Session, i *discordgo.InviteCreate) {
 		"channel_id": i.ChannelID,
 	})
 
-	eventWorker.Submit(i.Inviter.ID, func(e eventWorker.Event) {
+	eventWorker.Submit(i.GuildID, i.Inviter.ID, func(e eventWorker.Event) {
 		middleware.MonitorInviteCreate(s, i, e)
 	}, s, i)
 }
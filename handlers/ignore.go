@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"astralHRBot/automation"
+	"astralHRBot/guilds"
+	"astralHRBot/logger"
+)
+
+// ignoreEvent reports whether an event from a guild should not be handled, because the bot does
+// not serve the guild or the guild's automation is paused
+func ignoreEvent(guildID, event string) bool {
+	if !guilds.Serves(guildID) {
+		logger.Debug(logger.LogData{
+			"action":   "event_ignored",
+			"message":  "Ignoring gateway event from a guild the bot does not serve",
+			"event":    event,
+			"guild_id": guildID,
+		})
+		return true
+	}
+	if !automation.Paused(guildID) {
+		return false
+	}
+	logger.Debug(logger.LogData{
+		"action":   "event_ignored",
+		"message":  "Ignoring gateway event while automation is paused",
+		"event":    event,
+		"guild_id": guildID,
+		"reason":   automation.Reason(guildID),
+	})
+	return true
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/handlers/middleware"
 	"astralHRBot/helper"
 	"astralHRBot/logger"
+	"astralHRBot/metrics"
+	"astralHRBot/templates"
 	"astralHRBot/workers/eventWorker"
 	"astralHRBot/workers/monitoring"
-	"fmt"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -20,11 +22,20 @@ var guildMemberRemoveMiddleware = []GuildMemberRemoveMiddleware{
 }
 
 func MemberLeaversAndJoiners(s *discordgo.Session, d any) {
+	HandleMemberLeaversAndJoiners(discord.FromSession(s), d)
+}
+
+// HandleMemberLeaversAndJoiners queues member join and leave events against the given client
+func HandleMemberLeaversAndJoiners(s discord.DiscordClient, d any) {
 	switch t := d.(type) {
 	case *discordgo.GuildMemberAdd:
-		eventWorker.Submit(t.User.ID, memberJoiningServerHandlers, s, t)
+		if !ignoreEvent(t.GuildID, "member_join_or_leave") {
+			eventWorker.Submit(t.GuildID, t.User.ID, memberJoiningServerHandlers, s, t)
+		}
 	case *discordgo.GuildMemberRemove:
-		eventWorker.Submit(t.User.ID, memberLeavingSererHandlers, s, t)
+		if !ignoreEvent(t.GuildID, "member_join_or_leave") {
+			eventWorker.Submit(t.GuildID, t.User.ID, memberLeavingSererHandlers, s, t)
+		}
 	}
 }
 
@@ -40,7 +51,7 @@ func memberJoiningServerHandlers(e eventWorker.Event) {
 		return
 	}
 
-	s, ok1 := p[0].(*discordgo.Session)
+	s, ok1 := p[0].(discord.DiscordClient)
 	m, ok2 := p[1].(*discordgo.GuildMemberAdd)
 
 	if !ok1 || !ok2 {
@@ -57,6 +68,7 @@ func memberJoiningServerHandlers(e eventWorker.Event) {
 			return
 		}
 	}
+	metrics.RecruitmentTransition(metrics.StageJoined)
 }
 
 func memberLeavingSererHandlers(e eventWorker.Event) {
@@ -71,7 +83,7 @@ func memberLeavingSererHandlers(e eventWorker.Event) {
 		return
 	}
 
-	s, ok1 := p[0].(*discordgo.Session)
+	s, ok1 := p[0].(discord.DiscordClient)
 	m, ok2 := p[1].(*discordgo.GuildMemberRemove)
 
 	if !ok1 || !ok2 {
@@ -87,12 +99,13 @@ func memberLeavingSererHandlers(e eventWorker.Event) {
 			return
 		}
 	}
+	metrics.RecruitmentTransition(metrics.StageLeft)
 
 	//close a recruitment thread if its open and assign the "Left Server" tag
 	rtm := helper.NewRecruitmentThreadManager(s, e, m.User.ID)
-	rtm.SendMessageAndClose(fmt.Sprintf("%s left the server.", m.User.GlobalName), "Left Server")
+	rtm.SendMessageAndClose(templates.Render(templates.ThreadLeftServer, templates.Data{GuildID: e.GuildID, UserID: m.User.ID, DisplayName: m.User.GlobalName}), helper.TagLeftServer)
 
 	//clear any monitoring or events for the user
-	monitoring.RemoveAllScenarios(m.User.ID)
+	monitoring.RemoveAllScenarios(e.GuildID, m.User.ID)
 
 }
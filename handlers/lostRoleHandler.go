@@ -2,20 +2,22 @@ package handlers
 
 import (
 	"astralHRBot/channels"
+	"astralHRBot/discord"
 	"astralHRBot/helper"
 	"astralHRBot/logger"
+	"astralHRBot/metrics"
 	"astralHRBot/models"
 	"astralHRBot/roles"
+	"astralHRBot/templates"
 	"astralHRBot/users"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
 	"astralHRBot/workers/monitoring"
-	"fmt"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func HandleRoleLost(s *discordgo.Session, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) {
+func HandleRoleLost(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) {
 	if memberLeavesCorporation(s, m, r, e) {
 		return
 	}
@@ -27,52 +29,37 @@ func HandleRoleLost(s *discordgo.Session, m *discordgo.GuildMemberUpdate, r []st
 	}
 }
 
-func memberLeavesCorporation(s *discordgo.Session, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) bool {
-	if roles.HasRole(r, roles.GetMemberRoleID()) {
-		for _, roleID := range roles.ContentNotificationRoles {
-			discordAPIWorker.NewRequest(e, func() error {
-				logger.Debug(logger.LogData{
-					"trace_id":  e.TraceID,
-					"action":    "role_removed",
-					"member_id": m.User.ID,
-					"role_id":   roleID,
-				})
-
-				err := s.GuildMemberRoleRemove(m.GuildID, m.User.ID, roleID)
-				return err
-			})
-		}
-
-		monitoring.RemoveAllScenarios(m.User.ID)
-
-		discordAPIWorker.NewRequest(e, func() error {
+func memberLeavesCorporation(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) bool {
+	if roles.HasRole(r, roles.GetMemberRoleID(m.GuildID)) {
+		for _, roleID := range roles.ContentNotificationRoleIDs(m.GuildID) {
 			logger.Debug(logger.LogData{
 				"trace_id":  e.TraceID,
 				"action":    "role_removed",
 				"member_id": m.User.ID,
-				"role":      "absentee",
-			})
-			err := s.GuildMemberRoleRemove(m.GuildID, m.User.ID, roles.GetAbsenteeRoleID())
-			return err
-		})
-
-		discordAPIWorker.NewRequest(e, func() error {
-			logger.Debug(logger.LogData{
-				"trace_id":  e.TraceID,
-				"action":    "role_added",
-				"member_id": m.User.ID,
-				"role":      "guest",
+				"role_id":   roleID,
 			})
-			err := s.GuildMemberRoleAdd(m.GuildID, m.User.ID, roles.GetGuestRoleID())
-			return err
-		})
+			discordAPIWorker.RemoveRole(e, m.GuildID, m.User.ID, roleID)
+		}
 
-		discordAPIWorker.NewRequest(e, func() error {
+		monitoring.RemoveAllScenarios(m.GuildID, m.User.ID)
 
-			_, err := s.ChannelMessageSend(channels.GetHRChannel(), fmt.Sprintf("%s, has left the corporation and their discord access has been removed.", m.User.GlobalName))
-			return err
+		logger.Debug(logger.LogData{
+			"trace_id":  e.TraceID,
+			"action":    "role_removed",
+			"member_id": m.User.ID,
+			"role":      "absentee",
+		})
+		discordAPIWorker.RemoveRole(e, m.GuildID, m.User.ID, roles.GetAbsenteeRoleID(m.GuildID))
 
+		logger.Debug(logger.LogData{
+			"trace_id":  e.TraceID,
+			"action":    "role_added",
+			"member_id": m.User.ID,
+			"role":      "guest",
 		})
+		discordAPIWorker.AddRole(e, m.GuildID, m.User.ID, roles.GetGuestRoleID(m.GuildID))
+
+		discordAPIWorker.SendMessage(e, channels.GetHRChannel(m.GuildID), templates.Render(templates.CorporationLeft, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.User.GlobalName}))
 
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
@@ -87,18 +74,15 @@ func memberLeavesCorporation(s *discordgo.Session, m *discordgo.GuildMemberUpdat
 	return false
 }
 
-func memberLosesBlueRole(s *discordgo.Session, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) bool {
-	if roles.HasRole(r, roles.GetBlueRoleID()) {
-		discordAPIWorker.NewRequest(e, func() error {
-			logger.Debug(logger.LogData{
-				"trace_id":  e.TraceID,
-				"action":    "role_added",
-				"member_id": m.User.ID,
-				"role":      "guest",
-			})
-			err := s.GuildMemberRoleAdd(m.GuildID, m.User.ID, roles.GetGuestRoleID())
-			return err
+func memberLosesBlueRole(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) bool {
+	if roles.HasRole(r, roles.GetBlueRoleID(m.GuildID)) {
+		logger.Debug(logger.LogData{
+			"trace_id":  e.TraceID,
+			"action":    "role_added",
+			"member_id": m.User.ID,
+			"role":      "guest",
 		})
+		discordAPIWorker.AddRole(e, m.GuildID, m.User.ID, roles.GetGuestRoleID(m.GuildID))
 
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
@@ -113,9 +97,9 @@ func memberLosesBlueRole(s *discordgo.Session, m *discordgo.GuildMemberUpdate, r
 	return false
 }
 
-func memberLosesRecruitRole(s *discordgo.Session, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) bool {
+func memberLosesRecruitRole(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, r []string, e eventWorker.Event) bool {
 	// Check if this role change was initiated by the bot
-	if helper.WasRoleChangeInitiatedByBot(s, m.User.ID) {
+	if helper.WasRoleChangeInitiatedByBot(s, m.GuildID, m.User.ID) {
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
 			"action":    "skip_bot_initiated_removal",
@@ -124,9 +108,10 @@ func memberLosesRecruitRole(s *discordgo.Session, m *discordgo.GuildMemberUpdate
 		})
 		return false
 	}
-	if roles.HasRole(r, roles.GetRecruitRoleID()) && !roles.HasRole(m.Roles, roles.GetMemberRoleID()) {
+	if roles.HasRole(r, roles.GetRecruitRoleID(m.GuildID)) && !roles.HasRole(m.Roles, roles.GetMemberRoleID(m.GuildID)) {
+		metrics.RecruitmentTransition(metrics.StageRecruitRoleLost)
 
-		err := users.RemoveRecruitmentDate(m.User.ID)
+		err := users.RemoveRecruitmentDate(m.GuildID, m.User.ID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id":  e.TraceID,
@@ -144,9 +129,9 @@ func memberLosesRecruitRole(s *discordgo.Session, m *discordgo.GuildMemberUpdate
 		})
 
 		rtm := helper.NewRecruitmentThreadManager(s, e, m.User.ID)
-		rtm.SendMessage(fmt.Sprintf("%s has left the recruitment channel.", m.User.GlobalName))
+		rtm.SendMessage(templates.Render(templates.ThreadLeftRecruitment, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.User.GlobalName}))
 
-		monitoring.RemoveScenario(m.User.ID, models.MonitoringScenarioRecruitmentProcess)
+		monitoring.RemoveScenario(m.GuildID, m.User.ID, models.MonitoringScenarioRecruitmentProcess)
 
 		return true
 	}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/logger"
 	"astralHRBot/roles"
 	"astralHRBot/workers/eventWorker"
@@ -11,7 +12,15 @@ import (
 var guildMemberUpdateMiddleware = []GuildMemberUpdateMiddleware{}
 
 func GuildMemberUpdateHandlers(s *discordgo.Session, m *discordgo.GuildMemberUpdate) {
-	eventWorker.Submit(m.User.ID, handleRoleChanges, s, m)
+	HandleGuildMemberUpdate(discord.FromSession(s), m)
+}
+
+// HandleGuildMemberUpdate queues a member update for role change handling against the given client
+func HandleGuildMemberUpdate(s discord.DiscordClient, m *discordgo.GuildMemberUpdate) {
+	if ignoreEvent(m.GuildID, "member_update") {
+		return
+	}
+	eventWorker.Submit(m.GuildID, m.User.ID, handleRoleChanges, s, m)
 }
 
 func handleRoleChanges(e eventWorker.Event) {
@@ -26,7 +35,7 @@ func handleRoleChanges(e eventWorker.Event) {
 		return
 	}
 
-	s, ok1 := p[0].(*discordgo.Session)
+	s, ok1 := p[0].(discord.DiscordClient)
 	m, ok2 := p[1].(*discordgo.GuildMemberUpdate)
 
 	if !ok1 || !ok2 {
@@ -2,30 +2,27 @@ package middleware
 
 import (
 	"astralHRBot/channels"
+	"astralHRBot/discord"
 	"astralHRBot/helper"
 	"astralHRBot/logger"
+	"astralHRBot/templates"
 	"astralHRBot/users"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
 	"astralHRBot/workers/monitoring"
-	"fmt"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func IgnoreBotMessages(discord *discordgo.Session, message *discordgo.MessageCreate, e eventWorker.Event) bool {
-	return discord.State.User.ID == message.Author.ID
+func IgnoreBotMessages(s discord.DiscordClient, message *discordgo.MessageCreate, e eventWorker.Event) bool {
+	return s.BotUserID() == message.Author.ID
 }
 
-func SendMessageOnMemberJoin(s *discordgo.Session, m *discordgo.GuildMemberAdd, e eventWorker.Event) bool {
-	channelID := channels.GetLandingChannel()
-	userName := helper.GetDisplayName(m.User)
-	message := fmt.Sprintf("%s Joined The Server.", userName)
+func SendMessageOnMemberJoin(s discord.DiscordClient, m *discordgo.GuildMemberAdd, e eventWorker.Event) bool {
+	channelID := channels.GetLandingChannel(e.GuildID)
+	message := templates.Render(templates.ServerJoined, templates.Data{GuildID: e.GuildID, UserID: m.User.ID, DisplayName: helper.GetDisplayName(m.User)})
 
-	discordAPIWorker.NewRequest(e, func() error {
-		_, err := s.ChannelMessageSend(channelID, message)
-		return err
-	})
+	discordAPIWorker.SendMessage(e, channelID, message)
 
 	logger.Debug(logger.LogData{
 		"trace_id":   e.TraceID,
@@ -38,15 +35,11 @@ func SendMessageOnMemberJoin(s *discordgo.Session, m *discordgo.GuildMemberAdd,
 	return true
 }
 
-func SendMessageOnMemberLeave(s *discordgo.Session, m *discordgo.GuildMemberRemove, e eventWorker.Event) bool {
-	channelID := channels.GetLeaversChannel()
-	userName := helper.GetDisplayName(m.User)
-	message := fmt.Sprintf("%s Left The Server.", userName)
+func SendMessageOnMemberLeave(s discord.DiscordClient, m *discordgo.GuildMemberRemove, e eventWorker.Event) bool {
+	channelID := channels.GetLeaversChannel(e.GuildID)
+	message := templates.Render(templates.ServerLeft, templates.Data{GuildID: e.GuildID, UserID: m.User.ID, DisplayName: helper.GetDisplayName(m.User)})
 
-	discordAPIWorker.NewRequest(e, func() error {
-		_, err := s.ChannelMessageSend(channelID, message)
-		return err
-	})
+	discordAPIWorker.SendMessage(e, channelID, message)
 
 	logger.Debug(logger.LogData{
 		"trace_id":   e.TraceID,
@@ -59,11 +52,13 @@ func SendMessageOnMemberLeave(s *discordgo.Session, m *discordgo.GuildMemberRemo
 	return true
 }
 
-// CreateOrUpdateUserMiddleware sends an event to handle user creation/updates in Redis when a member joins
-func CreateOrUpdateUserMiddleware(s *discordgo.Session, m *discordgo.GuildMemberAdd, e eventWorker.Event) bool {
-	// Send the user creation event to the event worker
-
-	eventWorker.Submit(m.User.ID, users.CreateOrUpdateUser, m.User)
+// CreateOrUpdateUserMiddleware creates or updates the user in Redis when a member joins
+func CreateOrUpdateUserMiddleware(s discord.DiscordClient, m *discordgo.GuildMemberAdd, e eventWorker.Event) bool {
+	// Middleware already runs on the member's shard, so the update is made inline; submitting it
+	// would wait on this shard's own buffer
+	userEvent := e
+	userEvent.Payload = []any{m.User}
+	users.CreateOrUpdateUser(userEvent)
 
 	logger.Debug(logger.LogData{
 		"trace_id":   e.TraceID,
@@ -76,7 +71,7 @@ func CreateOrUpdateUserMiddleware(s *discordgo.Session, m *discordgo.GuildMember
 	return true
 }
 
-func MonitorMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate, e eventWorker.Event) bool {
+func MonitorMessageCreate(s discord.DiscordClient, m *discordgo.MessageCreate, e eventWorker.Event) bool {
 	logger.Debug(logger.LogData{
 		"action":   "monitor_message_create",
 		"message":  "Received message create",
@@ -88,7 +83,7 @@ func MonitorMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate, e ev
 	return true
 }
 
-func MonitorMessageUpdate(s *discordgo.Session, m *discordgo.MessageUpdate, e eventWorker.Event) bool {
+func MonitorMessageUpdate(s discord.DiscordClient, m *discordgo.MessageUpdate, e eventWorker.Event) bool {
 	logger.Debug(logger.LogData{
 		"action":   "monitor_message_update",
 		"message":  "Received message update",
@@ -100,7 +95,7 @@ func MonitorMessageUpdate(s *discordgo.Session, m *discordgo.MessageUpdate, e ev
 	return true
 }
 
-func MonitorMessageDelete(s *discordgo.Session, m *discordgo.MessageDelete, e eventWorker.Event) bool {
+func MonitorMessageDelete(s discord.DiscordClient, m *discordgo.MessageDelete, e eventWorker.Event) bool {
 	logger.Debug(logger.LogData{
 		"action":   "monitor_message_delete",
 		"message":  "Received message delete",
@@ -112,7 +107,7 @@ func MonitorMessageDelete(s *discordgo.Session, m *discordgo.MessageDelete, e ev
 	return true
 }
 
-func MonitorVoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpdate, e eventWorker.Event) bool {
+func MonitorVoiceStateUpdate(s discord.DiscordClient, v *discordgo.VoiceStateUpdate, e eventWorker.Event) bool {
 	logger.Debug(logger.LogData{
 		"action":   "monitor_voice_state_update",
 		"message":  "Received voice state update",
@@ -124,7 +119,7 @@ func MonitorVoiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpdate
 	return true
 }
 
-func MonitorInviteCreate(s *discordgo.Session, i *discordgo.InviteCreate, e eventWorker.Event) bool {
+func MonitorInviteCreate(s discord.DiscordClient, i *discordgo.InviteCreate, e eventWorker.Event) bool {
 	logger.Debug(logger.LogData{
 		"action":   "monitor_invite_create",
 		"message":  "Received invite create",
@@ -136,7 +131,7 @@ func MonitorInviteCreate(s *discordgo.Session, i *discordgo.InviteCreate, e even
 	return true
 }
 
-func MonitorMessageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd, e eventWorker.Event) bool {
+func MonitorMessageReactionAdd(s discord.DiscordClient, r *discordgo.MessageReactionAdd, e eventWorker.Event) bool {
 	logger.Debug(logger.LogData{
 		"action":   "monitor_message_reaction_add",
 		"message":  "Received message reaction add",
@@ -148,7 +143,7 @@ func MonitorMessageReactionAdd(s *discordgo.Session, r *discordgo.MessageReactio
 	return true
 }
 
-func MonitorMessageReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove, e eventWorker.Event) bool {
+func MonitorMessageReactionRemove(s discord.DiscordClient, r *discordgo.MessageReactionRemove, e eventWorker.Event) bool {
 	logger.Debug(logger.LogData{
 		"action":   "monitor_message_reaction_remove",
 		"message":  "Received message reaction remove",
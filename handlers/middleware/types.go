@@ -1,19 +1,20 @@
 package middleware
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/workers/eventWorker"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // MessageCreateMiddleware handles message creation events
-type MessageCreateMiddleware func(s *discordgo.Session, m *discordgo.MessageCreate, e eventWorker.Event) bool
+type MessageCreateMiddleware func(s discord.DiscordClient, m *discordgo.MessageCreate, e eventWorker.Event) bool
 
 // GuildMemberAddMiddleware handles member join events
-type GuildMemberAddMiddleware func(s *discordgo.Session, a *discordgo.GuildMemberAdd, e eventWorker.Event) bool
+type GuildMemberAddMiddleware func(s discord.DiscordClient, a *discordgo.GuildMemberAdd, e eventWorker.Event) bool
 
 // GuildMemberUpdateMiddleware handles member update events
-type GuildMemberUpdateMiddleware func(s *discordgo.Session, a *discordgo.GuildMemberUpdate, e eventWorker.Event) bool
+type GuildMemberUpdateMiddleware func(s discord.DiscordClient, a *discordgo.GuildMemberUpdate, e eventWorker.Event) bool
 
 // GuildMemberRemoveMiddleware handles member leave events
-type GuildMemberRemoveMiddleware func(s *discordgo.Session, r *discordgo.GuildMemberRemove, e eventWorker.Event) bool
+type GuildMemberRemoveMiddleware func(s discord.DiscordClient, r *discordgo.GuildMemberRemove, e eventWorker.Event) bool
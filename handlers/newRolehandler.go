@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"astralHRBot/channels"
+	"astralHRBot/clock"
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/globals"
 	"astralHRBot/helper"
 	"astralHRBot/logger"
+	"astralHRBot/metrics"
 	"astralHRBot/models"
 	"astralHRBot/roles"
+	"astralHRBot/templates"
 	"astralHRBot/users"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
@@ -19,7 +23,7 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
-func HandleRoleGained(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) {
+func HandleRoleGained(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) {
 	if welcomeNewRecruit(s, m, a, e) {
 		return
 	}
@@ -34,42 +38,35 @@ func HandleRoleGained(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a []
 	}
 }
 
-func welcomeNewRecruit(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
-	if roles.HasRole(a, roles.GetRecruitRoleID()) && !roles.HasRole(m.Roles, roles.GetServerClownRoleID()) {
+func welcomeNewRecruit(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
+	if roles.HasRole(a, roles.GetRecruitRoleID(m.GuildID)) && !roles.HasRole(m.Roles, roles.GetServerClownRoleID(m.GuildID)) {
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
 			"action":    "process_start",
 			"process":   "welcome_new_recruit",
 			"member_id": m.User.ID,
 		})
+		metrics.RecruitmentTransition(metrics.StageRecruit)
 
-		channelID := channels.GetRecruitmentChannel()
-		message := fmt.Sprintf(globals.RecruitmentWelcomeMessage, m.User.ID)
+		channelID := channels.GetRecruitmentChannel(m.GuildID)
+		message := templates.Render(templates.RecruitmentWelcome, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.Member.DisplayName()})
 
-		discordAPIWorker.NewRequest(e, func() error {
+		logger.Debug(logger.LogData{
+			"trace_id":  e.TraceID,
+			"action":    "welcome_message_sent",
+			"member_id": m.User.ID,
+			"channel":   channelID,
+		})
+		discordAPIWorker.SendMessage(e, channelID, message)
+
+		if roles.HasRole(m.Roles, roles.GetNewcomerRoleID(m.GuildID)) {
 			logger.Debug(logger.LogData{
 				"trace_id":  e.TraceID,
-				"action":    "welcome_message_sent",
+				"action":    "role_removed",
 				"member_id": m.User.ID,
-				"channel":   channelID,
-			})
-
-			_, err := s.ChannelMessageSend(channelID, message)
-			return err
-		})
-
-		if roles.HasRole(m.Roles, roles.GetNewcomerRoleID()) {
-			discordAPIWorker.NewRequest(e, func() error {
-				logger.Debug(logger.LogData{
-					"trace_id":  e.TraceID,
-					"action":    "role_removed",
-					"member_id": m.User.ID,
-					"role":      "newcomer",
-				})
-
-				err := s.GuildMemberRoleRemove(m.GuildID, m.User.ID, roles.GetNewcomerRoleID())
-				return err
+				"role":      "newcomer",
 			})
+			discordAPIWorker.RemoveRole(e, m.GuildID, m.User.ID, roles.GetNewcomerRoleID(m.GuildID))
 		}
 
 		rtm := helper.NewRecruitmentThreadManager(s, e, m.User.ID)
@@ -78,12 +75,12 @@ func welcomeNewRecruit(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a [
 			rtm.CreateThread(m.User.GlobalName, m.User.ID)
 		} else {
 			rtm.ReopenThread()
-			rtm.SendMessage(fmt.Sprintf("%s Rejoined Recruitment", m.User.GlobalName))
+			rtm.SendMessage(templates.Render(templates.ThreadRejoined, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.User.GlobalName}))
 			rtm.RemoveTags("")
 		}
 
 		// Update recruitment date in Redis
-		err := users.UpdateRecruitmentDate(m.User.ID)
+		err := users.UpdateRecruitmentDate(m.GuildID, m.User.ID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id":  e.TraceID,
@@ -95,9 +92,10 @@ func welcomeNewRecruit(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a [
 		}
 
 		params := &models.RecruitmentCleanupParams{UserID: m.User.ID}
-		scheduledTime := time.Now().Add(time.Duration(globals.GetRecruitmentCleanupDelay()) * 24 * time.Hour).Unix()
+		scheduledTime := clock.Now().Add(time.Duration(globals.GetRecruitmentCleanupDelay(m.GuildID)) * 24 * time.Hour).Unix()
 
 		newTask, err := models.NewTaskWithScenario(
+			m.GuildID,
 			models.TaskRecruitmentCleanup,
 			params,
 			scheduledTime,
@@ -112,7 +110,7 @@ func welcomeNewRecruit(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a [
 			return true
 		}
 
-		err = db.SaveTaskToRedis(context.Background(), *newTask)
+		err = db.GetStore().SaveTask(db.WithGuild(context.Background(), m.GuildID), *newTask)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": e.TraceID,
@@ -123,8 +121,8 @@ func welcomeNewRecruit(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a [
 		}
 
 		// Also schedule midpoint reminder for recruitment process if it's in the future
-		startTime := time.Now()
-		if err := monitoring.CreateRecruitmentReminderAtMidpoint(context.Background(), m.User.ID, startTime, models.MonitoringScenarioRecruitmentProcess); err != nil {
+		startTime := clock.Now()
+		if err := monitoring.CreateRecruitmentReminderAtMidpoint(db.WithGuild(context.Background(), m.GuildID), m.User.ID, startTime, models.MonitoringScenarioRecruitmentProcess); err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": e.TraceID,
 				"action":   "create_recruitment_reminder",
@@ -134,7 +132,7 @@ func welcomeNewRecruit(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a [
 			})
 		}
 
-		monitoring.AddScenario(m.User.ID, models.MonitoringScenarioRecruitmentProcess)
+		monitoring.AddScenario(m.GuildID, m.User.ID, models.MonitoringScenarioRecruitmentProcess)
 
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
@@ -147,38 +145,32 @@ func welcomeNewRecruit(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a [
 	return false
 }
 
-func recruitAuthenticated(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
-	if roles.HasRole(m.Roles, roles.GetRecruitRoleID()) && roles.HasRole(a, roles.GetAuthenticatedGuestRoleID()) {
+func recruitAuthenticated(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
+	if roles.HasRole(m.Roles, roles.GetRecruitRoleID(m.GuildID)) && roles.HasRole(a, roles.GetAuthenticatedGuestRoleID(m.GuildID)) {
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
 			"action":    "process_start",
 			"process":   "recruit_authenticated",
 			"member_id": m.User.ID,
 		})
+		metrics.RecruitmentTransition(metrics.StageAuthenticated)
 
-		discordAPIWorker.NewRequest(e, func() error {
-			logger.Debug(logger.LogData{
-				"trace_id":  e.TraceID,
-				"action":    "recruitment_message_sent",
-				"member_id": m.User.ID,
-				"channel":   channels.GetRecruitmentHub(),
-			})
-
-			_, err := s.ChannelMessageSend(channels.GetRecruitmentHub(), fmt.Sprintf("%s has completed the authentication steps.", m.Member.DisplayName()))
-			if err != nil {
-				return err
-			}
-			return nil
+		logger.Debug(logger.LogData{
+			"trace_id":  e.TraceID,
+			"action":    "recruitment_message_sent",
+			"member_id": m.User.ID,
+			"channel":   channels.GetRecruitmentHub(m.GuildID),
 		})
+		discordAPIWorker.SendMessage(e, channels.GetRecruitmentHub(m.GuildID), templates.Render(templates.AuthenticationHub, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.Member.DisplayName()}))
 
 		helper.SendDirectMessage(s, m.User.ID,
-			"The authentication steps for Astral Acquisitions Inc have been completed. Please reach out to a recruiter in the recruitment channel.", e)
+			templates.Render(templates.AuthenticationDirect, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.Member.DisplayName()}), e)
 
 		rtm := helper.NewRecruitmentThreadManager(s, e, m.User.ID)
 		if rtm.HasThread() {
 			updatedThreadTitle := fmt.Sprintf("%s - %s", m.Member.DisplayName(), m.User.ID)
 			rtm.UpdateThreadTitle(updatedThreadTitle)
-			rtm.SendMessage(fmt.Sprintf("%s Authentication Steps Complete.", m.Member.DisplayName()))
+			rtm.SendMessage(templates.Render(templates.ThreadAuthenticated, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.Member.DisplayName()}))
 		} else {
 			logger.Info(logger.LogData{
 				"trace_id":  e.TraceID,
@@ -198,68 +190,61 @@ func recruitAuthenticated(s *discordgo.Session, m *discordgo.GuildMemberUpdate,
 	return false
 }
 
-func newMemberOnboarding(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
-	if (roles.HasRole(m.Roles, roles.GetRecruitRoleID()) || roles.HasRole(m.Roles, roles.GetAuthenticatedGuestRoleID())) && roles.HasRole(a, roles.GetAuthenticatedMemberRoleID()) {
+func newMemberOnboarding(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
+	if (roles.HasRole(m.Roles, roles.GetRecruitRoleID(m.GuildID)) || roles.HasRole(m.Roles, roles.GetAuthenticatedGuestRoleID(m.GuildID))) && roles.HasRole(a, roles.GetAuthenticatedMemberRoleID(m.GuildID)) {
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
 			"action":    "process_start",
 			"process":   "new_member_onboarding",
 			"member_id": m.User.ID,
 		})
+		metrics.RecruitmentTransition(metrics.StageMember)
 
 		rolesToRemove := []string{
-			roles.GetNewcomerRoleID(), roles.GetRecruitRoleID(), roles.GetGuestRoleID(),
+			roles.GetNewcomerRoleID(m.GuildID), roles.GetRecruitRoleID(m.GuildID), roles.GetGuestRoleID(m.GuildID),
 		}
 
 		for _, roleID := range rolesToRemove {
-			discordAPIWorker.NewRequest(e, func() error {
-				logger.Debug(logger.LogData{
-					"trace_id":  e.TraceID,
-					"action":    "role_removed",
-					"member_id": m.User.ID,
-					"role_id":   roleID,
-				})
-				err := s.GuildMemberRoleRemove(m.GuildID, m.User.ID, roleID)
-				return err
-			})
-		}
-
-		for _, roleID := range roles.ContentNotificationRoles {
-			discordAPIWorker.NewRequest(e, func() error {
-				logger.Debug(logger.LogData{
-					"trace_id":  e.TraceID,
-					"action":    "role_added",
-					"member_id": m.User.ID,
-					"role_id":   roleID,
-				})
-				err := s.GuildMemberRoleAdd(m.GuildID, m.User.ID, roleID)
-				return err
+			logger.Debug(logger.LogData{
+				"trace_id":  e.TraceID,
+				"action":    "role_removed",
+				"member_id": m.User.ID,
+				"role_id":   roleID,
 			})
+			discordAPIWorker.RemoveRole(e, m.GuildID, m.User.ID, roleID)
 		}
 
-		message := fmt.Sprintf(globals.MemberJoinWelcomeMessage, m.Member.DisplayName(), m.User.ID)
-
-		channelID := channels.GetGeneralChannel()
-		discordAPIWorker.NewRequest(e, func() error {
+		for _, roleID := range roles.ContentNotificationRoleIDs(m.GuildID) {
 			logger.Debug(logger.LogData{
 				"trace_id":  e.TraceID,
-				"action":    "welcome_message_sent",
+				"action":    "role_added",
 				"member_id": m.User.ID,
-				"channel":   channelID,
+				"role_id":   roleID,
 			})
-			_, err := s.ChannelMessageSend(channelID, message)
-			return err
+			discordAPIWorker.AddRole(e, m.GuildID, m.User.ID, roleID)
+		}
+
+		message := templates.Render(templates.MemberWelcome, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.Member.DisplayName()})
+
+		channelID := channels.GetGeneralChannel(m.GuildID)
+		logger.Debug(logger.LogData{
+			"trace_id":  e.TraceID,
+			"action":    "welcome_message_sent",
+			"member_id": m.User.ID,
+			"channel":   channelID,
 		})
+		discordAPIWorker.SendMessage(e, channelID, message)
 
 		rtm := helper.NewRecruitmentThreadManager(s, e, m.User.ID)
 
 		if rtm.HasThread() {
-			rtm.SendMessage("Character Joined Corporation.")
+			rtm.SendMessage(templates.Render(templates.ThreadJoinedCorp, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.Member.DisplayName()}))
 
 			params := &models.UserCheckinParams{UserID: m.User.ID}
-			scheduledTime := time.Now().Add(time.Duration(globals.GetNewRecruitTrackingDays()) * 24 * time.Hour).Unix()
+			scheduledTime := clock.Now().Add(time.Duration(globals.GetNewRecruitTrackingDays(m.GuildID)) * 24 * time.Hour).Unix()
 
 			newTask, err := models.NewTaskWithScenario(
+				m.GuildID,
 				models.TaskUserCheckin,
 				params,
 				scheduledTime,
@@ -273,9 +258,9 @@ func newMemberOnboarding(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a
 				})
 			} else {
 				// Remove recruitment process scenario if it exists
-				monitoring.RemoveScenario(m.User.ID, models.MonitoringScenarioRecruitmentProcess)
+				monitoring.RemoveScenario(m.GuildID, m.User.ID, models.MonitoringScenarioRecruitmentProcess)
 
-				err = db.SaveTaskToRedis(context.Background(), *newTask)
+				err = db.GetStore().SaveTask(db.WithGuild(context.Background(), m.GuildID), *newTask)
 				if err != nil {
 					logger.Error(logger.LogData{
 						"trace_id": e.TraceID,
@@ -285,10 +270,10 @@ func newMemberOnboarding(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a
 				}
 			}
 
-			monitoring.AddUserTracking(m.User.ID, models.MonitoringScenarioNewRecruit, time.Duration(int(globals.GetNewRecruitTrackingDays()))*24*time.Hour)
+			monitoring.AddUserTracking(m.GuildID, m.User.ID, models.MonitoringScenarioNewRecruit, time.Duration(int(globals.GetNewRecruitTrackingDays(m.GuildID)))*24*time.Hour)
 
-			rtm.SendMessage(fmt.Sprintf("User checkin scheduled for %d days time.", int(globals.GetNewRecruitTrackingDays())))
-			rtm.CloseThread("Accepted")
+			rtm.SendMessage(templates.Render(templates.ThreadCheckinBooked, templates.Data{GuildID: m.GuildID, UserID: m.User.ID, DisplayName: m.Member.DisplayName()}))
+			rtm.CloseThread(helper.TagAccepted)
 		}
 
 		logger.Debug(logger.LogData{
@@ -302,25 +287,21 @@ func newMemberOnboarding(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a
 	return false
 }
 
-func memberRecievesGuestRole(s *discordgo.Session, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
-	if roles.HasRole(a, roles.GetGuestRoleID()) {
+func memberRecievesGuestRole(s discord.DiscordClient, m *discordgo.GuildMemberUpdate, a []string, e eventWorker.Event) bool {
+	if roles.HasRole(a, roles.GetGuestRoleID(m.GuildID)) {
 		logger.Debug(logger.LogData{
 			"trace_id":  e.TraceID,
 			"action":    "process_start",
 			"process":   "member_recieves_guest_role",
 			"member_id": m.User.ID,
 		})
-		discordAPIWorker.NewRequest(e, func() error {
-			logger.Debug(logger.LogData{
-				"trace_id":  e.TraceID,
-				"action":    "role_removed",
-				"member_id": m.User.ID,
-				"role":      "newcomer",
-			})
-
-			err := s.GuildMemberRoleRemove(m.GuildID, m.User.ID, roles.GetNewcomerRoleID())
-			return err
+		logger.Debug(logger.LogData{
+			"trace_id":  e.TraceID,
+			"action":    "role_removed",
+			"member_id": m.User.ID,
+			"role":      "newcomer",
 		})
+		discordAPIWorker.RemoveRole(e, m.GuildID, m.User.ID, roles.GetNewcomerRoleID(m.GuildID))
 		return true
 	}
 	return false
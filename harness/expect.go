@@ -0,0 +1,237 @@
+package harness
+
+import (
+	"astralHRBot/models"
+	"astralHRBot/validation"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Assertions return an error describing what was found instead when they do not hold
+
+// ExpectMessage checks that the bot posted a message containing text in a channel or thread
+func (h *Harness) ExpectMessage(channelID, text string) error {
+	if _, found := h.findBotMessage(h.Guild.Messages(channelID), text); !found {
+		return fmt.Errorf("no message containing %q in channel %s", text, channelID)
+	}
+	return nil
+}
+
+// ExpectNoMessage checks that the bot did not post a message containing text in a channel or thread
+func (h *Harness) ExpectNoMessage(channelID, text string) error {
+	if message, found := h.findBotMessage(h.Guild.Messages(channelID), text); found {
+		return fmt.Errorf("unexpected message in channel %s: %q", channelID, message.Content)
+	}
+	return nil
+}
+
+// ExpectDirectMessage checks that the bot sent the user a direct message containing text
+func (h *Harness) ExpectDirectMessage(userID, text string) error {
+	if _, found := h.findBotMessage(h.Guild.DirectMessages(userID), text); !found {
+		return fmt.Errorf("no direct message to %s containing %q", userID, text)
+	}
+	return nil
+}
+
+// findBotMessage matches text against message content and embed titles
+func (h *Harness) findBotMessage(messages []*discordgo.Message, text string) (*discordgo.Message, bool) {
+	for _, message := range messages {
+		if message.Author == nil || message.Author.ID != BotUserID {
+			continue
+		}
+		if strings.Contains(message.Content, text) {
+			return message, true
+		}
+		for _, embed := range message.Embeds {
+			if strings.Contains(embed.Title, text) || strings.Contains(embed.Description, text) {
+				return message, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// RecruitmentThread returns the user's thread in the recruitment forum
+func (h *Harness) RecruitmentThread(userID string) (*discordgo.Channel, error) {
+	for _, thread := range h.Guild.Threads(RecruitmentForumID) {
+		if strings.Contains(thread.Name, userID) {
+			return thread, nil
+		}
+	}
+	return nil, fmt.Errorf("no recruitment thread for %s", userID)
+}
+
+// ExpectThreadMessage checks that the user's recruitment thread has a bot message containing text
+func (h *Harness) ExpectThreadMessage(userID, text string) error {
+	thread, err := h.RecruitmentThread(userID)
+	if err != nil {
+		return err
+	}
+	return h.ExpectMessage(thread.ID, text)
+}
+
+// ExpectThreadClosed checks that the user's recruitment thread is archived with the given tag, if any
+func (h *Harness) ExpectThreadClosed(userID, tag string) error {
+	thread, err := h.RecruitmentThread(userID)
+	if err != nil {
+		return err
+	}
+	if !thread.ThreadMetadata.Archived {
+		return fmt.Errorf("recruitment thread %q is still open", thread.Name)
+	}
+	if tag == "" {
+		return nil
+	}
+	tags := h.Guild.TagNames(thread.ID)
+	for _, applied := range tags {
+		if applied == tag {
+			return nil
+		}
+	}
+	return fmt.Errorf("recruitment thread %q has tags %v, want %q", thread.Name, tags, tag)
+}
+
+// ExpectRole checks that the member currently has a role
+func (h *Harness) ExpectRole(userID, roleID string) error {
+	for _, role := range h.Guild.MemberRoles(userID) {
+		if role == roleID {
+			return nil
+		}
+	}
+	return fmt.Errorf("user %s does not have role %s (has %v)", userID, roleID, h.Guild.MemberRoles(userID))
+}
+
+// ExpectNoRole checks that the member does not have a role
+func (h *Harness) ExpectNoRole(userID, roleID string) error {
+	for _, role := range h.Guild.MemberRoles(userID) {
+		if role == roleID {
+			return fmt.Errorf("user %s still has role %s", userID, roleID)
+		}
+	}
+	return nil
+}
+
+// ExpectTask checks that a task of the given type is scheduled for the user and returns it
+func (h *Harness) ExpectTask(userID string, taskType models.TaskType) (models.Task, error) {
+	userTasks, err := h.Store.GetTasksForUser(context.Background(), userID)
+	if err != nil {
+		return models.Task{}, err
+	}
+	for _, task := range userTasks {
+		if task.FunctionName == taskType {
+			return task, nil
+		}
+	}
+	return models.Task{}, fmt.Errorf("no %s task scheduled for %s (scheduled: %s)", taskType, userID, describeTasks(userTasks))
+}
+
+// ExpectNoTask checks that no task of the given type is scheduled for the user
+func (h *Harness) ExpectNoTask(userID string, taskType models.TaskType) error {
+	userTasks, err := h.Store.GetTasksForUser(context.Background(), userID)
+	if err != nil {
+		return err
+	}
+	for _, task := range userTasks {
+		if task.FunctionName == taskType {
+			return fmt.Errorf("unexpected %s task %s scheduled for %s", taskType, task.TaskID, userID)
+		}
+	}
+	return nil
+}
+
+// ExpectTaskCompleted checks that a task of the given type ran to completion for the user
+func (h *Harness) ExpectTaskCompleted(userID string, taskType models.TaskType) error {
+	for _, task := range h.Store.CompletedTasks() {
+		if task.FunctionName == taskType && task.IsForUser(userID) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no completed %s task for %s", taskType, userID)
+}
+
+// ExpectScenario checks that the user is being monitored under a scenario
+func (h *Harness) ExpectScenario(userID string, scenario models.MonitoringScenario) error {
+	monitoringData, err := h.Store.GetUserMonitoring(context.Background(), userID)
+	if err != nil {
+		return err
+	}
+	if monitoringData == nil || !monitoringData.HasScenario(scenario) {
+		return fmt.Errorf("user %s is not monitored under %s", userID, scenario)
+	}
+	return nil
+}
+
+// ExpectNotTracked checks that the user has no monitoring session
+func (h *Harness) ExpectNotTracked(userID string) error {
+	monitoringData, err := h.Store.GetUserMonitoring(context.Background(), userID)
+	if err != nil {
+		return err
+	}
+	if monitoringData != nil {
+		return fmt.Errorf("user %s is still monitored under %v", userID, monitoringData.GetScenarios())
+	}
+	return nil
+}
+
+// ExpectMessageCount checks the user's message count for a scenario
+func (h *Harness) ExpectMessageCount(userID string, scenario models.MonitoringScenario, want int64) error {
+	analytics, err := h.Store.GetScenarioAnalytics(context.Background(), userID, scenario)
+	if err != nil {
+		return err
+	}
+	if analytics.Messages != want {
+		return fmt.Errorf("user %s has %d %s messages, want %d", userID, analytics.Messages, scenario, want)
+	}
+	return nil
+}
+
+// ExpectUser checks that the user has a record in the store
+func (h *Harness) ExpectUser(userID string) error {
+	_, err := h.Store.GetUser(context.Background(), userID)
+	return err
+}
+
+// ExpectConfigValid checks that the role and channel settings match the fake guild
+func (h *Harness) ExpectConfigValid() error {
+	report := validation.Check(h.Guild, GuildID)
+	if !report.OK() {
+		return fmt.Errorf("expected no configuration problems, found %s", describeProblems(report.Problems))
+	}
+	return nil
+}
+
+// ExpectConfigProblem checks that validation reports a problem with a setting
+func (h *Harness) ExpectConfigProblem(key string) error {
+	report := validation.Check(h.Guild, GuildID)
+	for _, problem := range report.Problems {
+		if problem.Key == key {
+			return nil
+		}
+	}
+	return fmt.Errorf("expected a configuration problem with %s, found %s", key, describeProblems(report.Problems))
+}
+
+func describeProblems(problems []validation.Problem) string {
+	if len(problems) == 0 {
+		return "none"
+	}
+	descriptions := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", problem.Key, problem.Message))
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+func describeTasks(tasks []models.Task) string {
+	if len(tasks) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		names = append(names, string(task.FunctionName))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,297 @@
+// Package harness boots the bot's workers against a fake guild and an in-memory store so
+// gateway events can be replayed through the full pipeline and the results inspected.
+package harness
+
+import (
+	"astralHRBot/bot"
+	"astralHRBot/bot/identity"
+	"astralHRBot/clock"
+	"astralHRBot/config"
+	"astralHRBot/db"
+	"astralHRBot/discord"
+	"astralHRBot/handlers"
+	"astralHRBot/logger"
+	"astralHRBot/roles"
+	"astralHRBot/tasks"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"astralHRBot/workers/eventWorker"
+	"astralHRBot/workers/monitoring"
+	"astralHRBot/workers/taskworker"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// IDs of the fake guild. Start exports them through the environment variables the bot reads.
+const (
+	GuildID   = "guild"
+	BotUserID = "bot"
+	// AdminUserID is the actor recorded in the audit log for role changes made by the harness
+	AdminUserID = "admin"
+	// BotRoleID is the bot's own role, placed above every role it manages
+	BotRoleID = "bot-role"
+
+	GeneralChannelID       = "general"
+	LandingChannelID       = "landing"
+	LeaversChannelID       = "leavers"
+	RecruitmentChannelID   = "recruitment"
+	RecruitmentForumID     = "recruitment-forum"
+	RecruitmentHubID       = "recruitment-hub"
+	HRChannelID            = "hr"
+	GuidesChannelID        = "guides"
+	LogisticsChannelID     = "logistics"
+	ContentOptOutChannelID = "content-opt-out"
+
+	MemberRoleID              = "member-role"
+	RecruitRoleID             = "recruit-role"
+	GuestRoleID               = "guest-role"
+	AbsenteeRoleID            = "absentee-role"
+	ServerClownRoleID         = "server-clown-role"
+	BlueRoleID                = "blue-role"
+	NewcomerRoleID            = "newcomer-role"
+	AuthenticatedGuestRoleID  = "authenticated-guest-role"
+	AuthenticatedMemberRoleID = "authenticated-member-role"
+)
+
+// ContentRoleIDs are the content notification roles handed out to new members
+var ContentRoleIDs = []string{"mining-role", "industry-role", "pve-role", "pvp-role", "fw-role"}
+
+// RecruitmentForumTags are the tags offered by the fake recruitment forum
+var RecruitmentForumTags = []string{"Accepted", "Left Server", "Newbie role removed"}
+
+// RoleIDs are the fake guild's roles, lowest first, with the bot's own role at the top
+var RoleIDs = append(append([]string{
+	MemberRoleID, RecruitRoleID, GuestRoleID, AbsenteeRoleID, ServerClownRoleID, BlueRoleID,
+	NewcomerRoleID, AuthenticatedGuestRoleID, AuthenticatedMemberRoleID,
+}, ContentRoleIDs...), BotRoleID)
+
+// RolePosition returns where a role starts out in the fake guild's role list
+func RolePosition(roleID string) int {
+	for i, id := range RoleIDs {
+		if id == roleID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+var environment = map[string]string{
+	"GUILD_ID":                   GuildID,
+	"GENERAL_CHANNEL_ID":         GeneralChannelID,
+	"LANDING_CHANNEL_ID":         LandingChannelID,
+	"LEAVERS_CHANNEL_ID":         LeaversChannelID,
+	"RECRUITMENT_CHANNEL_ID":     RecruitmentChannelID,
+	"RECRUITMENT_FORUM_ID":       RecruitmentForumID,
+	"RECRUITMENT_HUB_ID":         RecruitmentHubID,
+	"HR_CHANNEL_ID":              HRChannelID,
+	"GUIDES_CHANNEL_ID":          GuidesChannelID,
+	"LOGISTICS_CHANNEL_ID":       LogisticsChannelID,
+	"CONTENT_OPT_OUT_CHANNEL_ID": ContentOptOutChannelID,
+	roles.MemberRole:             MemberRoleID,
+	roles.RecruitRole:            RecruitRoleID,
+	roles.GuestRole:              GuestRoleID,
+	roles.AbsenteeRole:           AbsenteeRoleID,
+	roles.ServerClown:            ServerClownRoleID,
+	roles.BlueRole:               BlueRoleID,
+	roles.NewcomerRole:           NewcomerRoleID,
+	roles.AuthenticatedGuest:     AuthenticatedGuestRoleID,
+	roles.AuthenticatedMember:    AuthenticatedMemberRoleID,
+	roles.MiningRole:             ContentRoleIDs[0],
+	roles.IndustryRole:           ContentRoleIDs[1],
+	roles.PveRole:                ContentRoleIDs[2],
+	roles.PvpRole:                ContentRoleIDs[3],
+	roles.FwRole:                 ContentRoleIDs[4],
+}
+
+// Config controls how the harness is booted
+type Config struct {
+	// Start is the initial time of the harness clock; defaults to the current time
+	Start time.Time
+	// SettleTimeout bounds how long Settle and Eventually wait; defaults to 10 seconds
+	SettleTimeout time.Duration
+	// Verbose enables debug logging
+	Verbose bool
+}
+
+// Harness owns the fake guild, the in-memory store and the fake clock the bot runs against
+type Harness struct {
+	Guild *discord.FakeGuild
+	Store *db.MemoryStore
+	Clock *clock.Fake
+
+	settleTimeout time.Duration
+}
+
+var started atomic.Bool
+
+// Start boots the event worker pool, the Discord API worker, the monitoring tracker and the task
+// handlers against a fake guild. The workers are process-wide singletons, so Start may only be
+// called once per process and scenarios sharing a harness should use distinct user IDs.
+//
+// The task processor's polling loop is not started; Advance moves the clock and then runs the
+// due tasks through the same claim and run path, so task outcomes are deterministic.
+func Start(cfg Config) (*Harness, error) {
+	if !started.CompareAndSwap(false, true) {
+		return nil, errors.New("harness already started")
+	}
+
+	if cfg.Start.IsZero() {
+		cfg.Start = time.Now()
+	}
+	if cfg.SettleTimeout <= 0 {
+		cfg.SettleTimeout = 10 * time.Second
+	}
+
+	for key, value := range environment {
+		if err := os.Setenv(key, value); err != nil {
+			return nil, fmt.Errorf("failed to set %s: %w", key, err)
+		}
+	}
+
+	logConfig := logger.ConfigFromEnv()
+	if !cfg.Verbose {
+		logConfig.Level = slog.LevelInfo
+	}
+	logger.StartLoggerWithConfig(logConfig)
+
+	h := &Harness{
+		Guild:         discord.NewFakeGuild(GuildID, BotUserID),
+		Store:         db.NewMemoryStore(),
+		Clock:         clock.NewFake(cfg.Start),
+		settleTimeout: cfg.SettleTimeout,
+	}
+	clock.Set(h.Clock)
+
+	for _, channelID := range []string{GeneralChannelID, LandingChannelID, LeaversChannelID, RecruitmentChannelID, RecruitmentHubID, HRChannelID, GuidesChannelID, LogisticsChannelID, ContentOptOutChannelID} {
+		h.Guild.AddTextChannel(channelID, channelID)
+	}
+	h.Guild.AddForumChannel(RecruitmentForumID, RecruitmentForumID, RecruitmentForumTags...)
+
+	for _, roleID := range RoleIDs {
+		h.Guild.AddRole(roleID, roleID, RolePosition(roleID))
+	}
+	h.Guild.AddMember(BotUserID, "bot", BotRoleID)
+
+	db.SetStore(h.Store)
+	if err := config.Load(context.Background()); err != nil {
+		return nil, err
+	}
+	discord.SetClient(h.Guild)
+	if err := identity.SetupBotIdentity(h.Guild); err != nil {
+		return nil, err
+	}
+
+	if err := tasks.RegisterHandlers(); err != nil {
+		return nil, err
+	}
+
+	// Task handlers wait for Discord to be ready through bot.ReadyChan
+	bot.ReadyChan = make(chan struct{})
+	close(bot.ReadyChan)
+
+	discordAPIWorker.NewWorker(h.Guild)
+	eventWorker.NewWorkerPool()
+	if err := monitoring.Start(); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Settle waits until every submitted event has been handled and every Discord request it queued has run
+func (h *Harness) Settle() error {
+	deadline := time.Now().Add(h.settleTimeout)
+	for {
+		if eventWorker.Pending() == 0 && discordAPIWorker.Pending() == 0 {
+			// Look twice so a request queued by the last event is not missed
+			time.Sleep(5 * time.Millisecond)
+			if eventWorker.Pending() == 0 && discordAPIWorker.Pending() == 0 {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("pipeline did not settle within %s: %d events and %d Discord requests pending",
+				h.settleTimeout, eventWorker.Pending(), discordAPIWorker.Pending())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// Eventually retries check until it passes or the settle timeout elapses. It is for state updated
+// outside the event and API workers, such as analytics written by the monitoring tracker.
+func (h *Harness) Eventually(check func() error) error {
+	deadline := time.Now().Add(h.settleTimeout)
+	for {
+		err := check()
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Gateway events
+
+// Join adds a member to the guild and replays the GuildMemberAdd event
+func (h *Harness) Join(userID, name string, roleIDs ...string) error {
+	member := h.Guild.AddMember(userID, name, roleIDs...)
+	handlers.HandleMemberLeaversAndJoiners(h.Guild, &discordgo.GuildMemberAdd{Member: member})
+	return h.Settle()
+}
+
+// Leave removes a member from the guild and replays the GuildMemberRemove event
+func (h *Harness) Leave(userID string) error {
+	member, exists := h.Guild.RemoveMember(userID)
+	if !exists {
+		return fmt.Errorf("user %s is not in the guild", userID)
+	}
+	handlers.HandleMemberLeaversAndJoiners(h.Guild, &discordgo.GuildMemberRemove{Member: member})
+	return h.Settle()
+}
+
+// GainRoles gives a member roles, as an admin or the auth service would, and replays the GuildMemberUpdate event
+func (h *Harness) GainRoles(userID string, roleIDs ...string) error {
+	return h.changeRoles(userID, roleIDs, nil)
+}
+
+// LoseRoles takes roles from a member and replays the GuildMemberUpdate event
+func (h *Harness) LoseRoles(userID string, roleIDs ...string) error {
+	return h.changeRoles(userID, nil, roleIDs)
+}
+
+func (h *Harness) changeRoles(userID string, add, remove []string) error {
+	before, after, err := h.Guild.ChangeRoles(AdminUserID, userID, add, remove)
+	if err != nil {
+		return err
+	}
+	handlers.HandleGuildMemberUpdate(h.Guild, &discordgo.GuildMemberUpdate{Member: after, BeforeUpdate: before})
+	return h.Settle()
+}
+
+// SendMessage posts a message as a member and replays the MessageCreate event
+func (h *Harness) SendMessage(userID, channelID, content string) error {
+	message, err := h.Guild.PostMessage(channelID, userID, content)
+	if err != nil {
+		return err
+	}
+	handlers.HandleMessageCreate(h.Guild, &discordgo.MessageCreate{Message: message})
+	return h.Settle()
+}
+
+// Advance moves the clock forward and runs every task that has become due
+func (h *Harness) Advance(d time.Duration) error {
+	h.Clock.Advance(d)
+	if err := taskworker.ProcessDueTasks(context.Background()); err != nil {
+		return err
+	}
+	return h.Settle()
+}
@@ -0,0 +1,30 @@
+package harness_test
+
+import (
+	"astralHRBot/harness"
+	"fmt"
+	"os"
+	"testing"
+)
+
+var h *harness.Harness
+
+func TestMain(m *testing.M) {
+	var err error
+	h, err = harness.Start(harness.Config{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start harness: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+func TestScenarios(t *testing.T) {
+	for _, scenario := range harness.Scenarios() {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if err := h.Run(scenario); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
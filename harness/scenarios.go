@@ -0,0 +1,254 @@
+package harness
+
+import (
+	"astralHRBot/models"
+	"astralHRBot/roles"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Step is one action or check in a scripted scenario
+type Step struct {
+	Name string
+	Run  func(h *Harness) error
+}
+
+// Scenario is a named sequence of steps replayed against the harness
+type Scenario struct {
+	Name  string
+	Steps []Step
+}
+
+// Run replays a scenario's steps in order and stops at the first one that fails
+func (h *Harness) Run(scenario Scenario) error {
+	for i, step := range scenario.Steps {
+		if err := step.Run(h); err != nil {
+			return fmt.Errorf("%s: step %d (%s): %w", scenario.Name, i+1, step.Name, err)
+		}
+	}
+	return nil
+}
+
+// Join is a step that adds a member to the guild
+func Join(userID, name string) Step {
+	return Step{
+		Name: "join " + name,
+		Run:  func(h *Harness) error { return h.Join(userID, name) },
+	}
+}
+
+// Leave is a step that removes a member from the guild
+func Leave(userID string) Step {
+	return Step{
+		Name: "leave",
+		Run:  func(h *Harness) error { return h.Leave(userID) },
+	}
+}
+
+// GainRoles is a step that gives a member roles
+func GainRoles(userID string, roleIDs ...string) Step {
+	return Step{
+		Name: fmt.Sprintf("gain %v", roleIDs),
+		Run:  func(h *Harness) error { return h.GainRoles(userID, roleIDs...) },
+	}
+}
+
+// LoseRoles is a step that takes roles from a member
+func LoseRoles(userID string, roleIDs ...string) Step {
+	return Step{
+		Name: fmt.Sprintf("lose %v", roleIDs),
+		Run:  func(h *Harness) error { return h.LoseRoles(userID, roleIDs...) },
+	}
+}
+
+// SendMessage is a step that posts a message as a member
+func SendMessage(userID, channelID, content string) Step {
+	return Step{
+		Name: "message in " + channelID,
+		Run:  func(h *Harness) error { return h.SendMessage(userID, channelID, content) },
+	}
+}
+
+// Advance is a step that moves the clock forward and runs the tasks that become due
+func Advance(d time.Duration) Step {
+	return Step{
+		Name: "advance " + d.String(),
+		Run:  func(h *Harness) error { return h.Advance(d) },
+	}
+}
+
+// MoveRole is a step that moves a role to another position in the guild's role list
+func MoveRole(roleID string, position int) Step {
+	return Step{
+		Name: fmt.Sprintf("move %s to position %d", roleID, position),
+		Run: func(h *Harness) error {
+			h.Guild.AddRole(roleID, roleID, position)
+			return nil
+		},
+	}
+}
+
+// Check is a step that runs assertions. Every check runs and all failures are reported together.
+func Check(name string, checks ...func(h *Harness) error) Step {
+	return Step{
+		Name: name,
+		Run: func(h *Harness) error {
+			var errs []error
+			for _, check := range checks {
+				if err := check(h); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+const day = 24 * time.Hour
+
+// Scenarios returns the built-in scenarios, each using its own user
+func Scenarios() []Scenario {
+	return []Scenario{
+		RecruitmentLifecycle("900000000000000001", "Alice"),
+		InactiveRecruit("900000000000000002", "Bob"),
+		ActiveRecruit("900000000000000003", "Carol"),
+		ConfigValidation(),
+	}
+}
+
+// RecruitmentLifecycle walks a user through join, recruit role, authentication, membership,
+// the first-week check-in and leaving the server
+func RecruitmentLifecycle(userID, name string) Scenario {
+	mention := fmt.Sprintf("<@%s>", userID)
+
+	return Scenario{
+		Name: "recruitment lifecycle",
+		Steps: []Step{
+			Join(userID, name),
+			Check("join is announced and the user is saved",
+				func(h *Harness) error { return h.ExpectMessage(LandingChannelID, name+" Joined The Server.") },
+				func(h *Harness) error { return h.ExpectUser(userID) },
+			),
+
+			GainRoles(userID, NewcomerRoleID, RecruitRoleID),
+			Check("recruit is welcomed and the recruitment process starts",
+				func(h *Harness) error { return h.ExpectMessage(RecruitmentChannelID, "Welcome "+mention) },
+				func(h *Harness) error { return h.ExpectNoRole(userID, NewcomerRoleID) },
+				func(h *Harness) error { return h.ExpectThreadMessage(userID, name+" Joined Recruitment") },
+				func(h *Harness) error { _, err := h.ExpectTask(userID, models.TaskRecruitmentCleanup); return err },
+				func(h *Harness) error { _, err := h.ExpectTask(userID, models.TaskRecruitmentReminder); return err },
+				func(h *Harness) error { return h.ExpectScenario(userID, models.MonitoringScenarioRecruitmentProcess) },
+			),
+
+			Advance(4 * day),
+			Check("midpoint reminder is sent",
+				func(h *Harness) error {
+					return h.ExpectMessage(RecruitmentChannelID, mention+" Are you still interested")
+				},
+				func(h *Harness) error { return h.ExpectTaskCompleted(userID, models.TaskRecruitmentReminder) },
+			),
+
+			GainRoles(userID, AuthenticatedGuestRoleID),
+			Check("authentication is acknowledged",
+				func(h *Harness) error {
+					return h.ExpectMessage(RecruitmentHubID, name+" has completed the authentication steps.")
+				},
+				func(h *Harness) error { return h.ExpectDirectMessage(userID, "authentication steps") },
+				func(h *Harness) error { return h.ExpectThreadMessage(userID, "Authentication Steps Complete.") },
+			),
+
+			GainRoles(userID, AuthenticatedMemberRoleID),
+			Check("new member is onboarded",
+				func(h *Harness) error { return h.ExpectMessage(GeneralChannelID, "Welcome to Astral, "+name) },
+				func(h *Harness) error { return h.ExpectNoRole(userID, RecruitRoleID) },
+				func(h *Harness) error { return h.ExpectRole(userID, ContentRoleIDs[0]) },
+				func(h *Harness) error { return h.ExpectThreadClosed(userID, "Accepted") },
+				func(h *Harness) error { return h.ExpectNoTask(userID, models.TaskRecruitmentCleanup) },
+				func(h *Harness) error { _, err := h.ExpectTask(userID, models.TaskUserCheckin); return err },
+				func(h *Harness) error { return h.ExpectScenario(userID, models.MonitoringScenarioNewRecruit) },
+			),
+
+			Advance(8 * day),
+			Check("first week check-in is posted",
+				func(h *Harness) error { return h.ExpectMessage(RecruitmentHubID, name+"'s First Week Analytics") },
+				func(h *Harness) error { return h.ExpectThreadMessage(userID, name+"'s First Week Analytics") },
+				func(h *Harness) error { return h.ExpectNoTask(userID, models.TaskUserCheckin) },
+				func(h *Harness) error { return h.ExpectNotTracked(userID) },
+			),
+
+			Leave(userID),
+			Check("leaving is announced and the thread is closed",
+				func(h *Harness) error { return h.ExpectMessage(LeaversChannelID, name+" Left The Server.") },
+				func(h *Harness) error { return h.ExpectThreadClosed(userID, "Left Server") },
+			),
+		},
+	}
+}
+
+// InactiveRecruit covers a recruit who never speaks and loses the recruit role at cleanup
+func InactiveRecruit(userID, name string) Scenario {
+	return Scenario{
+		Name: "inactive recruit",
+		Steps: []Step{
+			Join(userID, name),
+			GainRoles(userID, RecruitRoleID),
+			Advance(4 * day),
+			Advance(4 * day),
+			Check("recruit role is removed and the thread is flagged",
+				func(h *Harness) error { return h.ExpectNoRole(userID, RecruitRoleID) },
+				func(h *Harness) error { return h.ExpectThreadMessage(userID, "No activity in recruitment process") },
+				func(h *Harness) error { return h.ExpectThreadClosed(userID, "Newbie role removed") },
+				func(h *Harness) error { return h.ExpectNoTask(userID, models.TaskRecruitmentCleanup) },
+				func(h *Harness) error { return h.ExpectNotTracked(userID) },
+			),
+		},
+	}
+}
+
+// ActiveRecruit covers a recruit who talks in the recruitment channel and keeps the recruit role
+func ActiveRecruit(userID, name string) Scenario {
+	return Scenario{
+		Name: "active recruit",
+		Steps: []Step{
+			Join(userID, name),
+			GainRoles(userID, RecruitRoleID),
+			SendMessage(userID, RecruitmentChannelID, "Hi, I'd like to join"),
+			Check("message is counted",
+				func(h *Harness) error {
+					return h.Eventually(func() error {
+						return h.ExpectMessageCount(userID, models.MonitoringScenarioRecruitmentProcess, 1)
+					})
+				},
+			),
+			Advance(4 * day),
+			Advance(4 * day),
+			Check("recruit role is kept",
+				func(h *Harness) error { return h.ExpectRole(userID, RecruitRoleID) },
+				func(h *Harness) error { return h.ExpectThreadMessage(userID, "Automated check passed") },
+				func(h *Harness) error { return h.ExpectNoTask(userID, models.TaskRecruitmentCleanup) },
+			),
+		},
+	}
+}
+
+// ConfigValidation checks the harness guild passes validation and that a managed role the bot can
+// no longer reach is reported
+func ConfigValidation() Scenario {
+	return Scenario{
+		Name: "config validation",
+		Steps: []Step{
+			Check("configuration matches the guild",
+				func(h *Harness) error { return h.ExpectConfigValid() },
+			),
+			MoveRole(GuestRoleID, RolePosition(BotRoleID)+1),
+			Check("guest role above the bot is reported",
+				func(h *Harness) error { return h.ExpectConfigProblem(roles.GuestRole) },
+			),
+			MoveRole(GuestRoleID, RolePosition(GuestRoleID)),
+			Check("configuration matches the guild again",
+				func(h *Harness) error { return h.ExpectConfigValid() },
+			),
+		},
+	}
+}
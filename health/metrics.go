@@ -0,0 +1,93 @@
+package health
+
+import (
+	"astralHRBot/db"
+	"astralHRBot/logger"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"astralHRBot/workers/eventWorker"
+	"astralHRBot/workers/monitoring"
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// stateCollector reports queue depths and tracked users at scrape time, read from the same
+// snapshots as /debug/state
+type stateCollector struct {
+	eventQueued      *prometheus.Desc
+	eventActive      *prometheus.Desc
+	apiQueued        *prometheus.Desc
+	apiInFlight      *prometheus.Desc
+	apiOverflow      *prometheus.Desc
+	tasks            *prometheus.Desc
+	quarantinedTasks *prometheus.Desc
+	trackedUsers     *prometheus.Desc
+	logDropped       *prometheus.Desc
+}
+
+func newStateCollector() *stateCollector {
+	return &stateCollector{
+		eventQueued:      prometheus.NewDesc("astral_event_pool_queued", "Events waiting in the event worker pool.", nil, nil),
+		eventActive:      prometheus.NewDesc("astral_event_pool_active_shards", "Event worker shards with a running worker.", nil, nil),
+		apiQueued:        prometheus.NewDesc("astral_discord_api_queued", "Discord API requests waiting to run, by priority lane.", []string{"lane"}, nil),
+		apiInFlight:      prometheus.NewDesc("astral_discord_api_in_flight", "Discord API requests currently running.", nil, nil),
+		apiOverflow:      prometheus.NewDesc("astral_discord_api_overflow_total", "Discord API requests dropped, rejected, saved at shutdown or lost at shutdown.", []string{"result"}, nil),
+		tasks:            prometheus.NewDesc("astral_tasks", "Tasks in each task set.", []string{"state"}, nil),
+		quarantinedTasks: prometheus.NewDesc("astral_tasks_quarantined_total", "Tasks quarantined since startup because their data was missing or unreadable.", nil, nil),
+		trackedUsers:     prometheus.NewDesc("astral_tracked_users", "Users being monitored, by monitoring scenario.", []string{"scenario"}, nil),
+		logDropped:       prometheus.NewDesc("astral_log_dropped_total", "Log messages discarded because the logger's buffer was full.", nil, nil),
+	}
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.eventQueued
+	ch <- c.eventActive
+	ch <- c.apiQueued
+	ch <- c.apiInFlight
+	ch <- c.apiOverflow
+	ch <- c.tasks
+	ch <- c.quarantinedTasks
+	ch <- c.trackedUsers
+	ch <- c.logDropped
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	pool := eventWorker.Stats()
+	ch <- prometheus.MustNewConstMetric(c.eventQueued, prometheus.GaugeValue, float64(pool.Queued))
+	ch <- prometheus.MustNewConstMetric(c.eventActive, prometheus.GaugeValue, float64(pool.ActiveShards))
+
+	api := discordAPIWorker.Stats()
+	for lane, queued := range api.ByLane {
+		ch <- prometheus.MustNewConstMetric(c.apiQueued, prometheus.GaugeValue, float64(queued), lane)
+	}
+	ch <- prometheus.MustNewConstMetric(c.apiInFlight, prometheus.GaugeValue, float64(api.InFlight))
+	ch <- prometheus.MustNewConstMetric(c.apiOverflow, prometheus.CounterValue, float64(api.Dropped), "dropped")
+	ch <- prometheus.MustNewConstMetric(c.apiOverflow, prometheus.CounterValue, float64(api.Rejected), "rejected")
+	ch <- prometheus.MustNewConstMetric(c.apiOverflow, prometheus.CounterValue, float64(api.Persisted), "persisted")
+	ch <- prometheus.MustNewConstMetric(c.apiOverflow, prometheus.CounterValue, float64(api.Lost), "lost")
+
+	ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+	defer cancel()
+	if tasks, err := taskQueueStats(ctx); err == nil {
+		ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.GaugeValue, float64(tasks.Queued), "queued")
+		ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.GaugeValue, float64(tasks.InFlight), "in_flight")
+		ch <- prometheus.MustNewConstMetric(c.tasks, prometheus.GaugeValue, float64(tasks.DeadLetter), "dead_letter")
+	} else {
+		logger.Warn(logger.LogData{
+			"action":  "metrics_scrape",
+			"message": "Failed to count tasks",
+			"error":   err.Error(),
+		})
+	}
+	ch <- prometheus.MustNewConstMetric(c.quarantinedTasks, prometheus.CounterValue, float64(db.QuarantinedTaskCount()))
+
+	for scenario, count := range monitoring.ScenarioCounts() {
+		ch <- prometheus.MustNewConstMetric(c.trackedUsers, prometheus.GaugeValue, float64(count), string(scenario))
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.logDropped, prometheus.CounterValue, float64(logger.Dropped()))
+}
+
+func init() {
+	prometheus.MustRegister(newStateCollector())
+}
@@ -0,0 +1,201 @@
+// Package health serves the liveness, readiness, debug and Prometheus endpoints used by Docker,
+// the uptime monitor and metrics scraping.
+package health
+
+import (
+	"astralHRBot/bot"
+	"astralHRBot/db"
+	"astralHRBot/guilds"
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"astralHRBot/workers/eventWorker"
+	"astralHRBot/workers/monitoring"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// defaultAddr is the port published in docker-compose.yml
+	defaultAddr = ":8080"
+	// checkTimeout bounds each dependency check made by a request
+	checkTimeout = 2 * time.Second
+)
+
+var server *http.Server
+
+// checkResult is the body of /healthz and /readyz
+type checkResult struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// state is the body of /debug/state
+type state struct {
+	EventPool        eventWorker.PoolStats       `json:"event_pool"`
+	DiscordAPI       discordAPIWorker.QueueStats `json:"discord_api"`
+	Tasks            *models.TaskQueueStats      `json:"tasks,omitempty"`
+	TasksError       string                      `json:"tasks_error,omitempty"`
+	QuarantinedTasks int64                       `json:"quarantined_tasks"`
+	TrackedUsers     int                         `json:"tracked_users"`
+}
+
+// Handler returns the mux serving /healthz, /readyz, /debug/state and /metrics
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", handleHealth)
+	mux.HandleFunc("GET /readyz", handleReady)
+	mux.HandleFunc("GET /debug/state", handleState)
+	mux.Handle("GET /metrics", promhttp.Handler())
+	return mux
+}
+
+// Start listens on HTTP_ADDR, or :8080 if it is not set. The listener is opened before Start
+// returns so a port conflict fails startup.
+func Start() error {
+	addr, exists := os.LookupEnv("HTTP_ADDR")
+	if !exists {
+		addr = defaultAddr
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server = &http.Server{
+		Handler:           Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(logger.LogData{
+				"action":  "http_server",
+				"message": "HTTP server stopped unexpectedly",
+				"error":   err.Error(),
+			})
+		}
+	}()
+
+	logger.Info(logger.LogData{
+		"action":  "http_server",
+		"message": "HTTP server listening",
+		"addr":    listener.Addr().String(),
+	})
+	return nil
+}
+
+// Stop waits until ctx ends for in-flight requests to finish, then closes the server
+func Stop(ctx context.Context) error {
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+// handleHealth reports whether the gateway is connected and Redis answers a ping
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{}
+
+	if bot.Connected() {
+		checks["gateway"] = "ok"
+	} else {
+		checks["gateway"] = "disconnected"
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+	if err := db.GetStore().Ping(ctx); err != nil {
+		checks["redis"] = err.Error()
+	} else {
+		checks["redis"] = "ok"
+	}
+
+	writeChecks(w, checks)
+}
+
+// handleReady reports whether monitoring has loaded and the bot has finished connecting
+func handleReady(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{}
+
+	if monitoring.IsReady() {
+		checks["monitoring"] = "ok"
+	} else {
+		checks["monitoring"] = "starting"
+	}
+	if bot.IsReady() {
+		checks["discord"] = "ok"
+	} else {
+		checks["discord"] = "starting"
+	}
+
+	writeChecks(w, checks)
+}
+
+// handleState reports the queue depths of the event pool, the Discord API worker and the task queue
+func handleState(w http.ResponseWriter, r *http.Request) {
+	current := state{
+		EventPool:        eventWorker.Stats(),
+		DiscordAPI:       discordAPIWorker.Stats(),
+		QuarantinedTasks: db.QuarantinedTaskCount(),
+		TrackedUsers:     len(monitoring.GetTrackedUsers()),
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+	if tasks, err := taskQueueStats(ctx); err != nil {
+		current.TasksError = err.Error()
+	} else {
+		current.Tasks = &tasks
+	}
+
+	writeJSON(w, http.StatusOK, current)
+}
+
+// taskQueueStats adds up the task queues of every guild
+func taskQueueStats(ctx context.Context) (models.TaskQueueStats, error) {
+	var total models.TaskQueueStats
+	for _, guildID := range guilds.IDs() {
+		stats, err := db.GetStore().TaskQueueStats(db.WithGuild(ctx, guildID))
+		if err != nil {
+			return total, err
+		}
+		total.Queued += stats.Queued
+		total.InFlight += stats.InFlight
+		total.DeadLetter += stats.DeadLetter
+	}
+	return total, nil
+}
+
+// writeChecks responds 200 if every check is ok and 503 otherwise
+func writeChecks(w http.ResponseWriter, checks map[string]string) {
+	result := checkResult{Status: "ok", Checks: checks}
+	status := http.StatusOK
+	for _, check := range checks {
+		if check != "ok" {
+			result.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+			break
+		}
+	}
+	writeJSON(w, status, result)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logger.Warn(logger.LogData{
+			"action":  "http_server",
+			"message": "Failed to write response",
+			"error":   err.Error(),
+		})
+	}
+}
@@ -1,15 +1,14 @@
 package helper
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/logger"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
-
-	"github.com/bwmarrin/discordgo"
 )
 
 // SendDirectMessage sends a direct message to a user
-func SendDirectMessage(s *discordgo.Session, userID string, message string, event eventWorker.Event) {
+func SendDirectMessage(s discord.DiscordClient, userID string, message string, event eventWorker.Event) {
 	discordAPIWorker.NewRequest(event, func() error {
 		dmChannel, err := s.UserChannelCreate(userID)
 		if err != nil {
@@ -33,5 +32,5 @@ func SendDirectMessage(s *discordgo.Session, userID string, message string, even
 			return err
 		}
 		return nil
-	})
+	}, discordAPIWorker.WithRoute(discordAPIWorker.DirectMessageRoute(userID)))
 }
@@ -1,16 +1,14 @@
 package helper
 
 import (
-	"os"
+	"astralHRBot/discord"
 	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
 
-func FindForumThreadByTitle(s *discordgo.Session, channelID string, phrase string) (*discordgo.Channel, bool) {
-	guildID, _ := os.LookupEnv("GUILD_ID")
-
+func FindForumThreadByTitle(s discord.DiscordClient, guildID, channelID string, phrase string) (*discordgo.Channel, bool) {
 	activeThreadList, err := s.GuildThreadsActive(guildID)
 
 	if err != nil {
@@ -2,7 +2,9 @@ package helper
 
 import (
 	"astralHRBot/channels"
+	"astralHRBot/discord"
 	"astralHRBot/logger"
+	"astralHRBot/templates"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
 	"fmt"
@@ -11,19 +13,31 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// Tags applied to recruitment threads when they are closed
+const (
+	TagAccepted          = "Accepted"
+	TagLeftServer        = "Left Server"
+	TagNewbieRoleRemoved = "Newbie role removed"
+)
+
+// RecruitmentForumTags are the tags the recruitment forum has to offer
+var RecruitmentForumTags = []string{TagAccepted, TagLeftServer, TagNewbieRoleRemoved}
+
 // RecruitmentThreadManager provides methods to manage recruitment threads
 type RecruitmentThreadManager struct {
-	session     *discordgo.Session
+	session     discord.DiscordClient
 	event       eventWorker.Event
 	channelID   string
 	thread      *discordgo.Channel
 	found       bool
 	channelInfo *discordgo.Channel
+	// created resolves to the thread started by CreateThread; later requests are chained after it
+	created *discordAPIWorker.Future[*discordgo.Channel]
 }
 
 // NewRecruitmentThreadManager creates a new manager for a specific user
-func NewRecruitmentThreadManager(s *discordgo.Session, e eventWorker.Event, userID string) *RecruitmentThreadManager {
-	recruitmentChannelID := channels.GetRecruitmentForum()
+func NewRecruitmentThreadManager(s discord.DiscordClient, e eventWorker.Event, userID string) *RecruitmentThreadManager {
+	recruitmentChannelID := channels.GetRecruitmentForum(e.GuildID)
 
 	// Debug logging to help diagnose thread finding issues
 	logger.Debug(logger.LogData{
@@ -34,7 +48,7 @@ func NewRecruitmentThreadManager(s *discordgo.Session, e eventWorker.Event, user
 		"channel_id": recruitmentChannelID,
 	})
 
-	thread, found := FindForumThreadByTitle(s, recruitmentChannelID, userID)
+	thread, found := FindForumThreadByTitle(s, e.GuildID, recruitmentChannelID, userID)
 
 	if found {
 		logger.Debug(logger.LogData{
@@ -75,9 +89,41 @@ func NewRecruitmentThreadManager(s *discordgo.Session, e eventWorker.Event, user
 	}
 }
 
-// HasThread returns true if a recruitment thread exists for the user
+// HasThread returns true if a recruitment thread exists for the user or is being created
 func (rtm *RecruitmentThreadManager) HasThread() bool {
-	return rtm.found
+	return rtm.found || rtm.created != nil
+}
+
+// route keeps every request for this thread in order; before the thread exists, requests go
+// through the forum so the creation is ordered too
+func (rtm *RecruitmentThreadManager) route() discordAPIWorker.Route {
+	if rtm.found {
+		return discordAPIWorker.ThreadRoute(rtm.thread.ID)
+	}
+	return discordAPIWorker.ForumRoute(rtm.channelID)
+}
+
+// requestOptions orders a thread request on the thread's route and, while the thread is still being
+// created, chains it after the creation so it is skipped if that failed
+func (rtm *RecruitmentThreadManager) requestOptions(extra ...discordAPIWorker.RequestOption) []discordAPIWorker.RequestOption {
+	opts := append([]discordAPIWorker.RequestOption{discordAPIWorker.WithRoute(rtm.route())}, extra...)
+	if rtm.created != nil {
+		opts = append(opts, discordAPIWorker.After(rtm.created))
+	}
+	return opts
+}
+
+// threadID returns the ID of the found or created thread. It is called from queued requests, which
+// run after the creation has finished.
+func (rtm *RecruitmentThreadManager) threadID() (string, error) {
+	if rtm.found {
+		return rtm.thread.ID, nil
+	}
+	thread, err := rtm.created.Wait()
+	if err != nil {
+		return "", err
+	}
+	return thread.ID, nil
 }
 
 // GetThread returns the thread if it exists
@@ -87,7 +133,7 @@ func (rtm *RecruitmentThreadManager) GetThread() (*discordgo.Channel, bool) {
 
 // SendMessage sends a message to the recruitment thread
 func (rtm *RecruitmentThreadManager) SendMessage(message string) error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "send_message",
@@ -97,7 +143,12 @@ func (rtm *RecruitmentThreadManager) SendMessage(message string) error {
 	}
 
 	discordAPIWorker.NewRequest(rtm.event, func() error {
-		_, err := rtm.session.ChannelMessageSend(rtm.thread.ID, message)
+		threadID, err := rtm.threadID()
+		if err != nil {
+			return err
+		}
+
+		_, err = rtm.session.ChannelMessageSend(threadID, message)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": rtm.event.TraceID,
@@ -113,14 +164,14 @@ func (rtm *RecruitmentThreadManager) SendMessage(message string) error {
 			})
 		}
 		return err
-	})
+	}, rtm.requestOptions()...)
 
 	return nil
 }
 
 // SendMessageEmbed sends an embed message to the recruitment thread
 func (rtm *RecruitmentThreadManager) SendMessageEmbed(embed *discordgo.MessageEmbed) error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "send_message_embed",
@@ -130,7 +181,12 @@ func (rtm *RecruitmentThreadManager) SendMessageEmbed(embed *discordgo.MessageEm
 	}
 
 	discordAPIWorker.NewRequest(rtm.event, func() error {
-		_, err := rtm.session.ChannelMessageSendEmbed(rtm.thread.ID, embed)
+		threadID, err := rtm.threadID()
+		if err != nil {
+			return err
+		}
+
+		_, err = rtm.session.ChannelMessageSendEmbed(threadID, embed)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": rtm.event.TraceID,
@@ -146,14 +202,14 @@ func (rtm *RecruitmentThreadManager) SendMessageEmbed(embed *discordgo.MessageEm
 			})
 		}
 		return err
-	})
+	}, rtm.requestOptions()...)
 
 	return nil
 }
 
 // ApplyTag applies a tag to the recruitment thread
 func (rtm *RecruitmentThreadManager) ApplyTag(tagName string) error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "apply_tag",
@@ -190,7 +246,12 @@ func (rtm *RecruitmentThreadManager) ApplyTag(tagName string) error {
 	}
 
 	discordAPIWorker.NewRequest(rtm.event, func() error {
-		_, err := rtm.session.ChannelEditComplex(rtm.thread.ID, &discordgo.ChannelEdit{
+		threadID, err := rtm.threadID()
+		if err != nil {
+			return err
+		}
+
+		_, err = rtm.session.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
 			AppliedTags: &[]string{tagID},
 		})
 		if err != nil {
@@ -208,14 +269,14 @@ func (rtm *RecruitmentThreadManager) ApplyTag(tagName string) error {
 			})
 		}
 		return err
-	})
+	}, rtm.requestOptions(discordAPIWorker.WithRetry(discordAPIWorker.DefaultRetryPolicy))...)
 
 	return nil
 }
 
 // CloseThread closes/archives the recruitment thread and optionally applies a tag
 func (rtm *RecruitmentThreadManager) CloseThread(tagName string) error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "close_thread",
@@ -225,6 +286,11 @@ func (rtm *RecruitmentThreadManager) CloseThread(tagName string) error {
 	}
 
 	discordAPIWorker.NewRequest(rtm.event, func() error {
+		threadID, err := rtm.threadID()
+		if err != nil {
+			return err
+		}
+
 		// Find tag if specified
 		var tagsToApply *[]string
 		if tagName != "" && rtm.channelInfo != nil {
@@ -278,7 +344,7 @@ func (rtm *RecruitmentThreadManager) CloseThread(tagName string) error {
 
 		// Close the thread
 		isArchived := true
-		_, err := rtm.session.ChannelEditComplex(rtm.thread.ID, &discordgo.ChannelEdit{
+		_, err = rtm.session.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
 			Archived:    &isArchived,
 			AppliedTags: tagsToApply,
 		})
@@ -298,7 +364,7 @@ func (rtm *RecruitmentThreadManager) CloseThread(tagName string) error {
 			})
 		}
 		return err
-	})
+	}, rtm.requestOptions(discordAPIWorker.WithRetry(discordAPIWorker.DefaultRetryPolicy))...)
 
 	return nil
 }
@@ -306,7 +372,7 @@ func (rtm *RecruitmentThreadManager) CloseThread(tagName string) error {
 // RemoveTags removes tags from the recruitment thread
 // If tagName is empty, removes all tags. If tagName is specified, removes only that tag.
 func (rtm *RecruitmentThreadManager) RemoveTags(tagName string) error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "remove_tags",
@@ -316,6 +382,11 @@ func (rtm *RecruitmentThreadManager) RemoveTags(tagName string) error {
 	}
 
 	discordAPIWorker.NewRequest(rtm.event, func() error {
+		threadID, err := rtm.threadID()
+		if err != nil {
+			return err
+		}
+
 		var tagsToApply *[]string
 
 		if tagName == "" {
@@ -352,7 +423,7 @@ func (rtm *RecruitmentThreadManager) RemoveTags(tagName string) error {
 			}
 
 			// Get current thread info to see existing tags
-			threadInfo, err := rtm.session.Channel(rtm.thread.ID)
+			threadInfo, err := rtm.session.Channel(threadID)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"trace_id": rtm.event.TraceID,
@@ -373,7 +444,7 @@ func (rtm *RecruitmentThreadManager) RemoveTags(tagName string) error {
 			tagsToApply = &filteredTags
 		}
 
-		_, err := rtm.session.ChannelEditComplex(rtm.thread.ID, &discordgo.ChannelEdit{
+		_, err = rtm.session.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
 			AppliedTags: tagsToApply,
 		})
 		if err != nil {
@@ -399,14 +470,14 @@ func (rtm *RecruitmentThreadManager) RemoveTags(tagName string) error {
 			}
 		}
 		return err
-	})
+	}, rtm.requestOptions(discordAPIWorker.WithRetry(discordAPIWorker.DefaultRetryPolicy))...)
 
 	return nil
 }
 
 // UpdateThreadTitle updates the title of the recruitment thread
 func (rtm *RecruitmentThreadManager) UpdateThreadTitle(newTitle string) error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "update_thread_title",
@@ -416,15 +487,20 @@ func (rtm *RecruitmentThreadManager) UpdateThreadTitle(newTitle string) error {
 	}
 
 	discordAPIWorker.NewRequest(rtm.event, func() error {
+		threadID, err := rtm.threadID()
+		if err != nil {
+			return err
+		}
+
 		logger.Debug(logger.LogData{
 			"trace_id":  rtm.event.TraceID,
 			"action":    "update_thread_title",
 			"message":   "Updating recruitment thread title",
-			"thread_id": rtm.thread.ID,
+			"thread_id": threadID,
 			"new_title": newTitle,
 		})
 
-		_, err := rtm.session.ChannelEditComplex(rtm.thread.ID, &discordgo.ChannelEdit{
+		_, err = rtm.session.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
 			Name: newTitle,
 		})
 		if err != nil {
@@ -442,14 +518,14 @@ func (rtm *RecruitmentThreadManager) UpdateThreadTitle(newTitle string) error {
 			})
 		}
 		return err
-	})
+	}, rtm.requestOptions(discordAPIWorker.WithRetry(discordAPIWorker.DefaultRetryPolicy))...)
 
 	return nil
 }
 
 // CreateThread creates a new recruitment thread for a user
 func (rtm *RecruitmentThreadManager) CreateThread(userName, userID string) error {
-	if rtm.found {
+	if rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "create_thread",
@@ -458,7 +534,7 @@ func (rtm *RecruitmentThreadManager) CreateThread(userName, userID string) error
 		return nil
 	}
 
-	discordAPIWorker.NewRequest(rtm.event, func() error {
+	rtm.created = discordAPIWorker.NewRequestWithValue(rtm.event, func() (*discordgo.Channel, error) {
 		newThreadTitle := fmt.Sprintf("%s - %s", userName, userID)
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
@@ -468,7 +544,7 @@ func (rtm *RecruitmentThreadManager) CreateThread(userName, userID string) error
 			"title":    newThreadTitle,
 		})
 
-		_, err := rtm.session.ForumThreadStart(rtm.channelID, newThreadTitle, 10080, fmt.Sprintf("%s Joined Recruitment", userName))
+		thread, err := rtm.session.ForumThreadStart(rtm.channelID, newThreadTitle, 10080, templates.Render(templates.ThreadOpened, templates.Data{GuildID: rtm.event.GuildID, UserID: userID, DisplayName: userName}))
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": rtm.event.TraceID,
@@ -484,15 +560,15 @@ func (rtm *RecruitmentThreadManager) CreateThread(userName, userID string) error
 				"user_id":  userID,
 			})
 		}
-		return err
-	})
+		return thread, err
+	}, discordAPIWorker.WithRoute(rtm.route()))
 
 	return nil
 }
 
 // ReopenThread reopens an existing recruitment thread
 func (rtm *RecruitmentThreadManager) ReopenThread() error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "reopen_thread",
@@ -502,14 +578,19 @@ func (rtm *RecruitmentThreadManager) ReopenThread() error {
 	}
 
 	discordAPIWorker.NewRequest(rtm.event, func() error {
+		threadID, err := rtm.threadID()
+		if err != nil {
+			return err
+		}
+
 		logger.Debug(logger.LogData{
 			"trace_id":  rtm.event.TraceID,
 			"action":    "reopen_thread",
 			"message":   "Reopening recruitment thread",
-			"thread_id": rtm.thread.ID,
+			"thread_id": threadID,
 		})
 
-		_, err := rtm.session.ChannelEditComplex(rtm.thread.ID, &discordgo.ChannelEdit{
+		_, err = rtm.session.ChannelEditComplex(threadID, &discordgo.ChannelEdit{
 			AutoArchiveDuration: 0,
 		})
 		if err != nil {
@@ -527,14 +608,14 @@ func (rtm *RecruitmentThreadManager) ReopenThread() error {
 			})
 		}
 		return err
-	})
+	}, rtm.requestOptions(discordAPIWorker.WithRetry(discordAPIWorker.DefaultRetryPolicy))...)
 
 	return nil
 }
 
 // SendMessageAndClose sends a message and then closes the thread with an optional tag
 func (rtm *RecruitmentThreadManager) SendMessageAndClose(message, tagName string) error {
-	if !rtm.found {
+	if !rtm.HasThread() {
 		logger.Debug(logger.LogData{
 			"trace_id": rtm.event.TraceID,
 			"action":   "send_message_and_close",
@@ -547,7 +628,7 @@ func (rtm *RecruitmentThreadManager) SendMessageAndClose(message, tagName string
 		"trace_id":     rtm.event.TraceID,
 		"action":       "send_message_and_close",
 		"message":      "Starting send message and close operation",
-		"thread_id":    rtm.thread.ID,
+		"route":        rtm.route().Key,
 		"user_message": message,
 		"tag_name":     tagName,
 	})
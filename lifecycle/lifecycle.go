@@ -0,0 +1,167 @@
+// Package lifecycle starts the bot's subsystems in dependency order and stops them in reverse
+// within an overall deadline.
+package lifecycle
+
+import (
+	"astralHRBot/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Hook is one subsystem's start and stop functions. Either may be nil.
+type Hook struct {
+	Name string
+	// DependsOn names hooks that must start before this one; this hook is stopped before them
+	DependsOn []string
+	Start     func(ctx context.Context) error
+	Stop      func(ctx context.Context) error
+}
+
+// Manager runs registered hooks. It is not safe for concurrent use.
+type Manager struct {
+	hooks   []Hook
+	started []Hook
+}
+
+// New returns an empty manager
+func New() *Manager {
+	return &Manager{}
+}
+
+// Register adds a hook. Hooks without dependencies between them start in registration order.
+func (m *Manager) Register(hook Hook) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// Start runs every start hook in dependency order. If one fails, the hooks already started are
+// stopped before the error is returned.
+func (m *Manager) Start(ctx context.Context) error {
+	ordered, err := m.order()
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range ordered {
+		if hook.Start != nil {
+			began := time.Now()
+			if err := hook.Start(ctx); err != nil {
+				logger.Error(logger.LogData{
+					"action":  "lifecycle_start",
+					"message": "Subsystem failed to start",
+					"hook":    hook.Name,
+					"error":   err.Error(),
+				})
+				return errors.Join(fmt.Errorf("start %s: %w", hook.Name, err), m.Stop(ctx))
+			}
+			logger.Info(logger.LogData{
+				"action":   "lifecycle_start",
+				"message":  "Subsystem started",
+				"hook":     hook.Name,
+				"duration": time.Since(began).String(),
+			})
+		}
+		m.started = append(m.started, hook)
+	}
+	return nil
+}
+
+// Stop runs the stop hooks of every started hook in the reverse of their start order. Hooks
+// share ctx's deadline; once it has passed the remaining hooks still run so they can release
+// what they hold, but anything they wait on is cut short.
+func (m *Manager) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(m.started) - 1; i >= 0; i-- {
+		hook := m.started[i]
+		if hook.Stop == nil {
+			continue
+		}
+
+		began := time.Now()
+		if err := hook.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+			logger.Error(logger.LogData{
+				"action":  "lifecycle_stop",
+				"message": "Subsystem did not stop cleanly",
+				"hook":    hook.Name,
+				"error":   err.Error(),
+			})
+			continue
+		}
+		logger.Info(logger.LogData{
+			"action":   "lifecycle_stop",
+			"message":  "Subsystem stopped",
+			"hook":     hook.Name,
+			"duration": time.Since(began).String(),
+		})
+	}
+	m.started = nil
+	return errors.Join(errs...)
+}
+
+// order sorts the hooks so each comes after the hooks it depends on, keeping registration order
+// where there is no dependency
+func (m *Manager) order() ([]Hook, error) {
+	byName := make(map[string]Hook, len(m.hooks))
+	for _, hook := range m.hooks {
+		if _, exists := byName[hook.Name]; exists {
+			return nil, fmt.Errorf("hook %q registered twice", hook.Name)
+		}
+		byName[hook.Name] = hook
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(m.hooks))
+	ordered := make([]Hook, 0, len(m.hooks))
+
+	var visit func(hook Hook) error
+	visit = func(hook Hook) error {
+		switch state[hook.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("hook %q is part of a dependency cycle", hook.Name)
+		}
+		state[hook.Name] = visiting
+		for _, name := range hook.DependsOn {
+			dependency, exists := byName[name]
+			if !exists {
+				return fmt.Errorf("hook %q depends on unknown hook %q", hook.Name, name)
+			}
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+		state[hook.Name] = visited
+		ordered = append(ordered, hook)
+		return nil
+	}
+
+	for _, hook := range m.hooks {
+		if err := visit(hook); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// Wait runs a blocking stop function and returns ctx's error if the deadline passes first. The
+// function keeps running in the background in that case.
+func Wait(ctx context.Context, stop func()) error {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stop()
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config controls where log records go and which are kept
+type Config struct {
+	// Level is the threshold below which messages are discarded
+	Level slog.Level
+	// PackageLevels overrides Level for individual packages, keyed by short package name
+	PackageLevels map[string]slog.Level
+	// Format is "text" or "json"
+	Format string
+	// File is an optional path records are also written to
+	File string
+	// MaxFileSize is the size in bytes at which File is rotated; zero disables rotation
+	MaxFileSize int64
+	// MaxBackups is how many rotated files are kept alongside File
+	MaxBackups int
+	// BufferSize is how many records may wait for the worker before new ones are dropped
+	BufferSize int
+
+	problems []configProblem
+}
+
+type configProblem struct {
+	setting string
+	value   string
+}
+
+// DefaultConfig is used for any setting not given in the environment
+func DefaultConfig() Config {
+	return Config{
+		Level:       slog.LevelDebug,
+		Format:      "text",
+		MaxFileSize: 10 << 20,
+		MaxBackups:  5,
+		BufferSize:  1024,
+	}
+}
+
+// ConfigFromEnv reads LOG_LEVEL, LOG_PACKAGE_LEVELS, LOG_FORMAT, LOG_FILE, LOG_FILE_MAX_SIZE_MB,
+// LOG_FILE_MAX_BACKUPS and LOG_BUFFER_SIZE, keeping the default for any that is missing or
+// invalid. LOG_PACKAGE_LEVELS is a comma-separated list such as "db=warn,monitoring=debug".
+func ConfigFromEnv() Config {
+	c := DefaultConfig()
+
+	if value, exists := os.LookupEnv("LOG_LEVEL"); exists {
+		if l, err := ParseLevel(value); err == nil {
+			c.Level = l
+		} else {
+			c.invalid("LOG_LEVEL", value)
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_PACKAGE_LEVELS"); exists {
+		c.PackageLevels = make(map[string]slog.Level)
+		for _, pair := range strings.Split(value, ",") {
+			pkg, name, found := strings.Cut(strings.TrimSpace(pair), "=")
+			l, err := ParseLevel(name)
+			if !found || pkg == "" || err != nil {
+				c.invalid("LOG_PACKAGE_LEVELS", pair)
+				continue
+			}
+			c.PackageLevels[pkg] = l
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_FORMAT"); exists {
+		if value == "text" || value == "json" {
+			c.Format = value
+		} else {
+			c.invalid("LOG_FORMAT", value)
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_FILE"); exists {
+		c.File = value
+	}
+	if value, exists := os.LookupEnv("LOG_FILE_MAX_SIZE_MB"); exists {
+		if size, err := strconv.Atoi(value); err == nil && size >= 0 {
+			c.MaxFileSize = int64(size) << 20
+		} else {
+			c.invalid("LOG_FILE_MAX_SIZE_MB", value)
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_FILE_MAX_BACKUPS"); exists {
+		if backups, err := strconv.Atoi(value); err == nil && backups >= 0 {
+			c.MaxBackups = backups
+		} else {
+			c.invalid("LOG_FILE_MAX_BACKUPS", value)
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_BUFFER_SIZE"); exists {
+		if size, err := strconv.Atoi(value); err == nil && size > 0 {
+			c.BufferSize = size
+		} else {
+			c.invalid("LOG_BUFFER_SIZE", value)
+		}
+	}
+
+	return c
+}
+
+// invalid records a bad setting so it can be logged once the logger is running
+func (c *Config) invalid(setting, value string) {
+	c.problems = append(c.problems, configProblem{setting: setting, value: value})
+}
+
+var levelNames = map[slog.Level]string{
+	slog.LevelDebug:  "DEBUG",
+	LevelSystemDebug: "SYSTEM_DEBUG",
+	slog.LevelInfo:   "INFO",
+	LevelSystem:      "SYSTEM",
+	slog.LevelWarn:   "WARN",
+	slog.LevelError:  "ERROR",
+}
+
+// LevelName returns the name a level is written with
+func LevelName(l slog.Level) string {
+	if name, exists := levelNames[l]; exists {
+		return name
+	}
+	return l.String()
+}
+
+// ParseLevel reads a level name such as "debug" or "WARN"
+func ParseLevel(name string) (slog.Level, error) {
+	for l, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return l, nil
+		}
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
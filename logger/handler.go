@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// stdout is the console sink
+var stdout io.Writer = os.Stdout
+
+// newHandler builds a text or JSON handler. Filtering happens before records are queued, so the
+// handler accepts every level the logger emits.
+func newHandler(format string, w io.Writer) slog.Handler {
+	options := &slog.HandlerOptions{
+		AddSource:   true,
+		Level:       slog.LevelDebug,
+		ReplaceAttr: replaceAttr,
+	}
+	if format == "json" {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// replaceAttr writes the custom level names and shortens source paths to dir/file.go:line
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(LevelName(l))
+		}
+	case slog.SourceKey:
+		if source, ok := a.Value.Any().(*slog.Source); ok && source.File != "" {
+			file := filepath.Join(filepath.Base(filepath.Dir(source.File)), filepath.Base(source.File))
+			a.Value = slog.StringValue(fmt.Sprintf("%s:%d", file, source.Line))
+		}
+	}
+	return a
+}
@@ -1,124 +1,260 @@
 package logger
 
 import (
-	"astralHRBot/globals"
-	"encoding/json"
+	"context"
 	"fmt"
-	"path/filepath"
+	"log/slog"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
-type logLevel string
-
+// Levels beyond slog's own. SYSTEM messages are shown whenever INFO is; SYSTEM_DEBUG only when DEBUG is.
 const (
-	info        logLevel = "INFO"
-	warn        logLevel = "WARN"
-	error       logLevel = "ERROR"
-	debug       logLevel = "DEBUG"
-	system      logLevel = "SYSTEM"
-	systemDebug logLevel = "SYSTEM_DEBUG"
+	LevelSystemDebug = slog.LevelDebug + 1
+	LevelSystem      = slog.LevelInfo + 1
 )
 
 type LogData map[string]any
 
-type logMessage struct {
-	Level   logLevel
-	Data    LogData
-	Time    time.Time
-	TraceID string
-	Package string
+// entry is a record waiting for the worker. flushed is only set on the marker queued by Flush
+// and is closed once the worker reaches it.
+type entry struct {
+	record  slog.Record
+	flushed chan struct{}
 }
 
-var logChannel = make(chan logMessage, 200)
+var (
+	cfg        = DefaultConfig()
+	level      slog.LevelVar
+	logChannel chan entry
+	startOnce  sync.Once
+
+	sinksMu sync.RWMutex
+	sinks   []slog.Handler
+
+	// dropped counts messages discarded because the buffer was full; unreported is the part of
+	// it the worker has not yet logged a warning about
+	dropped    atomic.Int64
+	unreported atomic.Int64
+)
 
+// StartLogger starts the logger with settings from the environment
 func StartLogger() {
-	go logWorker()
+	StartLoggerWithConfig(ConfigFromEnv())
+}
+
+// StartLoggerWithConfig sets up the configured sinks and starts the worker that writes to them.
+// Only the first call has any effect.
+func StartLoggerWithConfig(c Config) {
+	startOnce.Do(func() {
+		cfg = c
+		level.Set(c.Level)
+		logChannel = make(chan entry, c.BufferSize)
+
+		sinks = append(sinks, newHandler(c.Format, stdout))
+		var fileErr error
+		if c.File != "" {
+			file, err := openRotatingFile(c.File, c.MaxFileSize, c.MaxBackups)
+			if err != nil {
+				fileErr = err
+			} else {
+				sinks = append(sinks, newHandler(c.Format, file))
+			}
+		}
+
+		go logWorker()
+
+		for _, problem := range c.problems {
+			Warn(LogData{
+				"action":  "logger_config",
+				"message": "Ignoring invalid setting, using the default",
+				"setting": problem.setting,
+				"value":   problem.value,
+			})
+		}
+		if fileErr != nil {
+			Error(LogData{
+				"action":  "logger_config",
+				"message": "Failed to open log file, logging to stdout only",
+				"file":    c.File,
+				"error":   fileErr.Error(),
+			})
+		}
+	})
+}
+
+// AddSink sends every record at or above the sink's own level to another handler as well
+func AddSink(handler slog.Handler) {
+	sinksMu.Lock()
+	sinks = append(sinks, handler)
+	sinksMu.Unlock()
+}
+
+// SetLevel changes the threshold below which messages are discarded. Per-package levels from
+// LOG_PACKAGE_LEVELS still take precedence.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// GetLevel returns the current threshold
+func GetLevel() slog.Level {
+	return level.Level()
+}
+
+// Dropped returns the number of messages discarded since startup because the buffer was full
+func Dropped() int64 {
+	return dropped.Load()
 }
 
 func logWorker() {
-	for logMsg := range logChannel {
-		// Check debug mode at processing time with proper synchronization
-		if (logMsg.Level == debug || logMsg.Level == systemDebug) && !globals.GetDebugMode() {
+	ctx := context.Background()
+	for e := range logChannel {
+		if e.flushed != nil {
+			close(e.flushed)
 			continue
 		}
 
-		jsonData, err := json.Marshal(logMsg.Data)
-		if err != nil {
-			jsonData = []byte(fmt.Sprintf(`{"error": "Failed to marshal log data: %v"}`, err))
+		if lost := unreported.Swap(0); lost > 0 {
+			write(ctx, newRecord(slog.LevelWarn, 0, "Dropped log messages because the buffer was full", "logger", "", LogData{
+				"action":  "log_dropped",
+				"dropped": lost,
+			}))
 		}
+		write(ctx, e.record)
+	}
+}
 
-		output := fmt.Sprintf("[%s] [%s] [Package: %s]: %s\n",
-			logMsg.Time.Format(time.RFC3339),
-			logMsg.Level,
-			logMsg.Package,
-			string(jsonData),
-		)
-		fmt.Println(output)
+func write(ctx context.Context, record slog.Record) {
+	sinksMu.RLock()
+	defer sinksMu.RUnlock()
+	for _, sink := range sinks {
+		if !sink.Enabled(ctx, record.Level) {
+			continue
+		}
+		if err := sink.Handle(ctx, record.Clone()); err != nil {
+			fmt.Fprintf(stdout, "failed to write log record: %v\n", err)
+		}
 	}
 }
 
-func getCallerPackage() string {
-	pc, _, _, ok := runtime.Caller(3)
-	if !ok {
-		return "unknown"
+// Flush waits until every message logged before the call has been written, or until ctx ends
+func Flush(ctx context.Context) error {
+	if logChannel == nil {
+		return nil
 	}
 
-	fn := runtime.FuncForPC(pc)
-	if fn == nil {
-		return "unknown"
+	marker := entry{flushed: make(chan struct{})}
+	select {
+	case logChannel <- marker:
+	case <-ctx.Done():
+		return ctx.Err()
 	}
 
-	fullName := fn.Name()
+	select {
+	case <-marker.flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
 
-	parts := strings.Split(fullName, ".")
-	if len(parts) < 2 {
+// callerPackage returns the short name of the package a function belongs to, e.g. "monitoring"
+// for "astralHRBot/workers/monitoring.(*tracker).run"
+func callerPackage(function string) string {
+	if function == "" {
 		return "unknown"
 	}
+	name := function[strings.LastIndex(function, "/")+1:]
+	if dot := strings.Index(name, "."); dot >= 0 {
+		name = name[:dot]
+	}
+	return name
+}
 
-	pkgPath := strings.Join(parts[:len(parts)-1], ".")
+// enabled checks a message against its package's level, or the global level if it has none
+func enabled(l slog.Level, pkg string) bool {
+	if threshold, exists := cfg.PackageLevels[pkg]; exists {
+		return l >= threshold
+	}
+	return l >= level.Level()
+}
 
-	pkgName := filepath.Base(pkgPath)
+// newRecord turns log data into a record. The message comes from data["message"], falling back
+// to data["action"]; the trace ID and package are added as their own attributes and the rest of
+// the data follows in key order.
+func newRecord(l slog.Level, pc uintptr, message, pkg, traceID string, data LogData) slog.Record {
+	record := slog.NewRecord(time.Now(), l, message, pc)
+	record.AddAttrs(slog.String("package", pkg))
+	if traceID != "" {
+		record.AddAttrs(slog.String("trace_id", traceID))
+	}
 
-	return pkgName
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		if key == "message" || key == "trace_id" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		record.AddAttrs(slog.Any(key, data[key]))
+	}
+	return record
 }
 
-func newLog(level logLevel, data LogData) {
-	traceID := "-"
-	if id, ok := data["trace_id"].(string); ok {
-		traceID = id
+func newLog(l slog.Level, data LogData) {
+	if logChannel == nil {
+		return
+	}
+
+	// Skip runtime.Callers, newLog and the exported level function
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	pkg := callerPackage(frame.Function)
+	if !enabled(l, pkg) {
+		return
+	}
+
+	message, _ := data["message"].(string)
+	if message == "" {
+		message, _ = data["action"].(string)
 	}
+	traceID, _ := data["trace_id"].(string)
 
-	logChannel <- logMessage{
-		Level:   level,
-		Data:    data,
-		Time:    time.Now(),
-		TraceID: traceID,
-		Package: getCallerPackage(),
+	select {
+	case logChannel <- entry{record: newRecord(l, pcs[0], message, pkg, traceID, data)}:
+	default:
+		dropped.Add(1)
+		unreported.Add(1)
 	}
 }
 
 func Info(data LogData) {
-	newLog(info, data)
+	newLog(slog.LevelInfo, data)
 }
 
 func Warn(data LogData) {
-	newLog(warn, data)
+	newLog(slog.LevelWarn, data)
 }
 
 func Error(data LogData) {
-	newLog(error, data)
+	newLog(slog.LevelError, data)
 }
 
 func Debug(data LogData) {
-	newLog(debug, data)
+	newLog(slog.LevelDebug, data)
 }
 
 func System(data LogData) {
-	newLog(system, data)
+	newLog(LevelSystem, data)
 }
 
 func SystemDebug(data LogData) {
-	newLog(systemDebug, data)
+	newLog(LevelSystemDebug, data)
 }
@@ -2,15 +2,30 @@ package main
 
 import (
 	"astralHRBot/bot"
+	"astralHRBot/config"
 	"astralHRBot/db"
+	"astralHRBot/discord"
+	"astralHRBot/guilds"
+	"astralHRBot/health"
+	"astralHRBot/lifecycle"
 	"astralHRBot/logger"
 	"astralHRBot/tasks"
+	"astralHRBot/validation"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
+	"astralHRBot/workers/logChannel"
 	"astralHRBot/workers/monitoring"
 	"astralHRBot/workers/taskworker"
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
+// defaultShutdownTimeout bounds the whole shutdown unless SHUTDOWN_TIMEOUT is set
+const defaultShutdownTimeout = 30 * time.Second
+
 func main() {
 	logger.StartLogger()
 	logger.System(logger.LogData{
@@ -18,21 +33,203 @@ func main() {
 		"message": "Starting AstralHRBot...",
 	})
 
-	db.InitRedis()
-	bot.Setup()
+	manager := lifecycle.New()
+	registerHooks(manager)
 
-	tasks.RegisterHandlers()
+	if err := manager.Start(context.Background()); err != nil {
+		logger.Error(logger.LogData{
+			"action":  "startup",
+			"message": "Failed to start",
+			"error":   err.Error(),
+		})
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		logger.Flush(flushCtx)
+		cancel()
+		os.Exit(1)
+	}
 
-	discordAPIWorker.NewWorker(bot.Discord)
-	eventWorker.NewWorkerPool()
-	taskworker.StartTaskProcessor()
-	monitoring.Start()
-	monitoring.WaitForReady()
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	<-sigChan
 
+	timeout := shutdownTimeout()
 	logger.Info(logger.LogData{
-		"action":  "startup",
-		"message": "All systems initialized, starting bot...",
+		"action":  "server_shutdown",
+		"message": "Astral HR Bot is shutting down...",
+		"timeout": timeout.String(),
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := manager.Stop(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+// registerHooks adds every subsystem to the manager. Each depends on what it uses while running,
+// so shutdown closes the gateway first, drains the workers that act on its events and flushes
+// the logger last.
+func registerHooks(manager *lifecycle.Manager) {
+	manager.Register(lifecycle.Hook{
+		Name: "logger",
+		// The logger is started before anything else so startup can be logged
+		Stop: func(ctx context.Context) error {
+			logger.Info(logger.LogData{
+				"action":  "server_shutdown",
+				"message": "Astral HR Bot has shut down gracefully.",
+			})
+			return logger.Flush(ctx)
+		},
+	})
+
+	// Migrations decode task params, so the task registry is filled before Redis is touched
+	manager.Register(lifecycle.Hook{
+		Name:      "task_registry",
+		DependsOn: []string{"logger"},
+		Start: func(ctx context.Context) error {
+			return tasks.RegisterHandlers()
+		},
+	})
+
+	manager.Register(lifecycle.Hook{
+		Name:      "redis",
+		DependsOn: []string{"logger", "task_registry"},
+		Start: func(ctx context.Context) error {
+			db.InitRedis()
+			return db.RunMigrations(ctx)
+		},
+		Stop: func(ctx context.Context) error {
+			return db.CloseRedis()
+		},
+	})
+
+	// Roles, channels and the recruitment delays are read from the config store, so it is loaded
+	// before anything acts on events and before monitoring recreates tasks from those delays
+	manager.Register(lifecycle.Hook{
+		Name:      "config",
+		DependsOn: []string{"redis"},
+		Start:     config.Load,
+	})
+
+	manager.Register(lifecycle.Hook{
+		Name:      "discord_session",
+		DependsOn: []string{"config"},
+		Start: func(ctx context.Context) error {
+			bot.Setup()
+			return nil
+		},
+	})
+
+	// Started early and stopped late so health checks answer through startup and shutdown
+	manager.Register(lifecycle.Hook{
+		Name:      "http_server",
+		DependsOn: []string{"redis", "discord_session"},
+		Start: func(ctx context.Context) error {
+			return health.Start()
+		},
+		Stop: health.Stop,
 	})
 
-	bot.Start()
+	manager.Register(lifecycle.Hook{
+		Name:      "discord_api_worker",
+		DependsOn: []string{"redis", "discord_session"},
+		Start: func(ctx context.Context) error {
+			discordAPIWorker.NewWorker(discord.GetClient())
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return lifecycle.Wait(ctx, discordAPIWorker.Stop)
+		},
+	})
+
+	// Stopped before the Discord API worker so alerts raised during shutdown are still sent
+	manager.Register(lifecycle.Hook{
+		Name:      "log_channel",
+		DependsOn: []string{"discord_api_worker"},
+		Start: func(ctx context.Context) error {
+			logChannel.Start()
+			return nil
+		},
+		Stop: logChannel.Stop,
+	})
+
+	manager.Register(lifecycle.Hook{
+		Name:      "monitoring",
+		DependsOn: []string{"redis", "config"},
+		Start: func(ctx context.Context) error {
+			return monitoring.Start()
+		},
+		Stop: monitoring.Stop,
+	})
+
+	manager.Register(lifecycle.Hook{
+		Name:      "event_worker",
+		DependsOn: []string{"discord_api_worker", "monitoring"},
+		Start: func(ctx context.Context) error {
+			eventWorker.NewWorkerPool()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return lifecycle.Wait(ctx, eventWorker.Shutdown)
+		},
+	})
+
+	manager.Register(lifecycle.Hook{
+		Name:      "task_processor",
+		DependsOn: []string{"event_worker", "discord_api_worker"},
+		Start: func(ctx context.Context) error {
+			taskworker.StartTaskProcessor()
+			return nil
+		},
+		Stop: taskworker.StopTaskProcessor,
+	})
+
+	// Set up before the gateway opens so strict mode can hold back events until the first check
+	manager.Register(lifecycle.Hook{
+		Name:      "config_validation",
+		DependsOn: []string{"config"},
+		Start: func(ctx context.Context) error {
+			validation.Start()
+			return nil
+		},
+	})
+
+	manager.Register(lifecycle.Hook{
+		Name:      "gateway",
+		DependsOn: []string{"event_worker", "monitoring", "task_processor", "config_validation"},
+		Start: func(ctx context.Context) error {
+			logger.Info(logger.LogData{
+				"action":  "startup",
+				"message": "All systems initialized, starting bot...",
+			})
+			if err := bot.Start(); err != nil {
+				return err
+			}
+			for _, guildID := range guilds.IDs() {
+				validation.Post(validation.Validate(guildID))
+			}
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return bot.Stop()
+		},
+	})
+}
+
+// shutdownTimeout reads SHUTDOWN_TIMEOUT, falling back to the default if it is missing or invalid
+func shutdownTimeout() time.Duration {
+	value, exists := os.LookupEnv("SHUTDOWN_TIMEOUT")
+	if !exists {
+		return defaultShutdownTimeout
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		logger.Warn(logger.LogData{
+			"action":  "server_shutdown",
+			"message": "Ignoring invalid SHUTDOWN_TIMEOUT, using the default",
+			"value":   value,
+		})
+		return defaultShutdownTimeout
+	}
+	return timeout
 }
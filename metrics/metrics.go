@@ -0,0 +1,154 @@
+// Package metrics defines the Prometheus metrics recorded by the bot. They are registered with the
+// default registry and served on /metrics by the health server.
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "astral"
+
+// Recruitment funnel stages counted by RecruitmentTransition
+const (
+	StageJoined          = "joined"
+	StageRecruit         = "recruit"
+	StageAuthenticated   = "authenticated"
+	StageMember          = "member"
+	StageRecruitRoleLost = "recruit_role_lost"
+	StageInactiveRemoved = "inactive_removed"
+	StageLeft            = "left"
+)
+
+// lateTaskThreshold is how far past its scheduled time a task must start to count as late
+const lateTaskThreshold = time.Minute
+
+var (
+	gatewayEvents = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "gateway_events_total",
+		Help:      "Gateway events received, by event type.",
+	}, []string{"type"})
+
+	eventHandlerDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "event_handler_duration_seconds",
+		Help:      "Time taken by event worker handlers.",
+		Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+	})
+
+	eventHandlerPanics = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "event_handler_panics_total",
+		Help:      "Event worker handlers that panicked.",
+	})
+
+	discordRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "discord_api_requests_total",
+		Help:      "Discord API request attempts, by route and result code.",
+	}, []string{"route", "code"})
+
+	discordRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "discord_api_request_duration_seconds",
+		Help:      "Time taken by Discord API request attempts, by route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route"})
+
+	tasksRun = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_total",
+		Help:      "Task runs, by task type and outcome.",
+	}, []string{"task_type", "outcome"})
+
+	tasksLate = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tasks_late_total",
+		Help:      "Tasks that started more than a minute after their scheduled time, by task type.",
+	}, []string{"task_type"})
+
+	taskDelay = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "task_start_delay_seconds",
+		Help:      "Time between a task's scheduled time and the start of its run, by task type.",
+		Buckets:   []float64{1, 5, 10, 30, 60, 300, 900, 3600},
+	}, []string{"task_type"})
+
+	taskDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "task_duration_seconds",
+		Help:      "Time taken by task handlers, by task type.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"task_type"})
+
+	recruitmentTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "recruitment_transitions_total",
+		Help:      "Members reaching each stage of the recruitment funnel.",
+	}, []string{"stage"})
+)
+
+// GatewayEvent counts an event received from the Discord gateway
+func GatewayEvent(eventType string) {
+	gatewayEvents.WithLabelValues(eventType).Inc()
+}
+
+// EventHandled records how long an event worker handler ran and whether it panicked
+func EventHandled(duration time.Duration, panicked bool) {
+	eventHandlerDuration.Observe(duration.Seconds())
+	if panicked {
+		eventHandlerPanics.Inc()
+	}
+}
+
+// DiscordRequest records one Discord API request attempt. The code is "ok" for success, the HTTP
+// status for errors returned by Discord and "error" for anything else, such as a network failure.
+func DiscordRequest(route string, duration time.Duration, err error) {
+	discordRequests.WithLabelValues(route, resultCode(err)).Inc()
+	discordRequestDuration.WithLabelValues(route).Observe(duration.Seconds())
+}
+
+// DiscordRequestSkipped counts a request that never ran, for example because a request it was chained after failed
+func DiscordRequestSkipped(route string) {
+	discordRequests.WithLabelValues(route, "skipped").Inc()
+}
+
+func resultCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		return strconv.Itoa(restErr.Response.StatusCode)
+	}
+	return "error"
+}
+
+// TaskStarted records how long after its scheduled time a task started
+func TaskStarted(taskType string, scheduled, started time.Time) {
+	delay := started.Sub(scheduled)
+	if delay < 0 {
+		delay = 0
+	}
+	taskDelay.WithLabelValues(taskType).Observe(delay.Seconds())
+	if delay > lateTaskThreshold {
+		tasksLate.WithLabelValues(taskType).Inc()
+	}
+}
+
+// TaskFinished records a task run's duration and outcome: completed, retried or dead_lettered
+func TaskFinished(taskType, outcome string, duration time.Duration) {
+	tasksRun.WithLabelValues(taskType, outcome).Inc()
+	taskDuration.WithLabelValues(taskType).Observe(duration.Seconds())
+}
+
+// RecruitmentTransition counts a member reaching a recruitment funnel stage
+func RecruitmentTransition(stage string) {
+	recruitmentTransitions.WithLabelValues(stage).Inc()
+}
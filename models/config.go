@@ -0,0 +1,11 @@
+package models
+
+// ConfigChange records who changed a runtime setting and what it was before and after
+type ConfigChange struct {
+	GuildID   string `json:"guild_id,omitempty"`
+	Key       string `json:"key"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	Actor     string `json:"actor"` // Discord user ID of the person who made the change
+	Timestamp int64  `json:"timestamp"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// PendingDiscordRequest is a queued Discord API request that had not run when the bot shut down.
+// Only requests built from a known operation can be saved, since arbitrary closures cannot be.
+type PendingDiscordRequest struct {
+	Operation string            `json:"operation"`
+	Args      map[string]string `json:"args"`
+	Priority  int               `json:"priority"`
+	TraceID   string            `json:"trace_id"`
+	UserID    string            `json:"user_id"`
+	QueuedAt  int64             `json:"queued_at"`
+}
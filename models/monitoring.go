@@ -1,6 +1,7 @@
 package models
 
 import (
+	"astralHRBot/clock"
 	"time"
 )
 
@@ -77,12 +78,6 @@ func GetTaskFunctionsForScenario(scenario MonitoringScenario) []string {
 	return []string{}
 }
 
-// TaskHandlerFunc represents a function that can handle a specific task type
-type TaskHandlerFunc func(Task)
-
-// TaskHandlers maps task types to their handler functions
-var TaskHandlers = map[TaskType]TaskHandlerFunc{}
-
 type UserAnalytics struct {
 	UserID       string
 	Messages     int64
@@ -92,17 +87,19 @@ type UserAnalytics struct {
 }
 
 type UserMonitoring struct {
+	GuildID   string
 	UserID    string
 	Scenarios map[MonitoringScenario]struct{}
 	StartedAt int64 // Unix timestamp when monitoring started
 	ExpiresAt int64 // Unix timestamp when monitoring should end (0 for indefinite)
 }
 
-func NewUserMonitoring(userID string) *UserMonitoring {
+func NewUserMonitoring(guildID, userID string) *UserMonitoring {
 	return &UserMonitoring{
+		GuildID:   guildID,
 		UserID:    userID,
 		Scenarios: make(map[MonitoringScenario]struct{}),
-		StartedAt: time.Now().Unix(),
+		StartedAt: clock.Now().Unix(),
 	}
 }
 
@@ -129,7 +126,7 @@ func (um *UserMonitoring) GetScenarios() []MonitoringScenario {
 
 func (um *UserMonitoring) SetExpiration(duration time.Duration) {
 	if duration > 0 {
-		um.ExpiresAt = time.Now().Add(duration).Unix()
+		um.ExpiresAt = clock.Now().Add(duration).Unix()
 	} else {
 		um.ExpiresAt = 0
 	}
@@ -147,7 +144,7 @@ func (um *UserMonitoring) IsExpired() bool {
 	if um.ExpiresAt == 0 {
 		return false
 	}
-	return time.Now().Unix() > um.ExpiresAt
+	return clock.Now().Unix() > um.ExpiresAt
 }
 
 // ShouldTrackAction checks if an action should be tracked based on the user's active scenarios
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTaskTimeout is used for task types that do not declare a timeout
+const DefaultTaskTimeout = 5 * time.Minute
+
+// TaskResult describes the outcome of a successful task run
+type TaskResult struct {
+	Summary string // Short human-readable description of what the task did
+}
+
+// TaskHandlerFunc runs a task with its raw parameters.
+// A non-nil error causes the task to be retried according to its retry policy.
+type TaskHandlerFunc func(ctx context.Context, task Task) (TaskResult, error)
+
+// TypedTaskHandler runs a task with its parameters already decoded into P
+type TypedTaskHandler[P TaskParams] func(ctx context.Context, task Task, params P) (TaskResult, error)
+
+// TaskDefinition declares everything the scheduler needs to know about a task type
+type TaskDefinition struct {
+	Type        TaskType
+	NewParams   func() TaskParams
+	Handler     TaskHandlerFunc
+	Timeout     time.Duration
+	RetryPolicy TaskRetryPolicy
+}
+
+// TaskOptions holds the optional settings for a task definition
+type TaskOptions struct {
+	Timeout     time.Duration   // Defaults to DefaultTaskTimeout
+	RetryPolicy TaskRetryPolicy // Defaults to DefaultTaskRetryPolicy
+}
+
+// NewTaskDefinition builds a definition whose handler receives typed parameters.
+// The params are decoded and type-checked before the handler is called, so a
+// mismatched task fails with an error instead of panicking.
+func NewTaskDefinition[P TaskParams](taskType TaskType, newParams func() P, handler TypedTaskHandler[P], opts TaskOptions) TaskDefinition {
+	def := TaskDefinition{
+		Type:        taskType,
+		Timeout:     opts.Timeout,
+		RetryPolicy: opts.RetryPolicy,
+	}
+
+	if def.Timeout <= 0 {
+		def.Timeout = DefaultTaskTimeout
+	}
+	if def.RetryPolicy.MaxAttempts <= 0 {
+		def.RetryPolicy = DefaultTaskRetryPolicy
+	}
+
+	if newParams != nil {
+		def.NewParams = func() TaskParams { return newParams() }
+	}
+
+	if handler != nil {
+		def.Handler = func(ctx context.Context, task Task) (TaskResult, error) {
+			raw, err := task.GetParams()
+			if err != nil {
+				return TaskResult{}, err
+			}
+
+			params, ok := raw.(P)
+			if !ok {
+				return TaskResult{}, fmt.Errorf("task %s has params of type %T, expected %T", task.TaskID, raw, *new(P))
+			}
+
+			return handler(ctx, task, params)
+		}
+	}
+
+	return def
+}
+
+var (
+	taskRegistry   = map[TaskType]TaskDefinition{}
+	taskRegistryMu sync.RWMutex
+)
+
+// RegisterTask adds or replaces the definition for a task type
+func RegisterTask(def TaskDefinition) {
+	taskRegistryMu.Lock()
+	defer taskRegistryMu.Unlock()
+	taskRegistry[def.Type] = def
+}
+
+// GetTaskDefinition returns the registered definition for a task type
+func GetTaskDefinition(taskType TaskType) (TaskDefinition, bool) {
+	taskRegistryMu.RLock()
+	defer taskRegistryMu.RUnlock()
+	def, exists := taskRegistry[taskType]
+	return def, exists
+}
+
+// ValidateTaskRegistry checks that every known task type has a handler and a params factory
+func ValidateTaskRegistry() error {
+	taskRegistryMu.RLock()
+	defer taskRegistryMu.RUnlock()
+
+	for _, taskType := range TaskTypes {
+		def, exists := taskRegistry[taskType]
+		if !exists {
+			return fmt.Errorf("task type %s is not registered", taskType)
+		}
+		if def.Handler == nil {
+			return fmt.Errorf("task type %s has no handler", taskType)
+		}
+		if def.NewParams == nil {
+			return fmt.Errorf("task type %s has no params factory", taskType)
+		}
+	}
+
+	for taskType := range taskRegistry {
+		known := false
+		for _, t := range TaskTypes {
+			if t == taskType {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("registered task type %s is not declared in TaskTypes", taskType)
+		}
+	}
+
+	return nil
+}
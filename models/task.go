@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // TaskType represents the type of task
@@ -14,11 +15,79 @@ const (
 	TaskRecruitmentReminder TaskType = "recruitmentReminder"
 )
 
-// TaskTypeMap maps task types to their parameter types
-var TaskTypeMap = map[TaskType]func() TaskParams{
-	TaskRecruitmentCleanup:  func() TaskParams { return &RecruitmentCleanupParams{} },
-	TaskUserCheckin:         func() TaskParams { return &UserCheckinParams{} },
-	TaskRecruitmentReminder: func() TaskParams { return &RecruitmentReminderParams{} },
+// TaskStatus represents where a task is in its lifecycle
+type TaskStatus string
+
+const (
+	// TaskStatusPending means the task is waiting in the queue for its scheduled time
+	TaskStatusPending TaskStatus = "pending"
+	// TaskStatusRunning means a worker holds a lease on the task and is executing it
+	TaskStatusRunning TaskStatus = "running"
+	// TaskStatusDone means the task handler acknowledged successful completion
+	TaskStatusDone TaskStatus = "done"
+	// TaskStatusFailed means the task could not be completed and was taken out of the queue
+	TaskStatusFailed TaskStatus = "failed"
+)
+
+// TaskTypes lists every task type the scheduler knows about.
+// Each one must be registered with RegisterTask before the task processor starts.
+var TaskTypes = []TaskType{
+	TaskRecruitmentCleanup,
+	TaskUserCheckin,
+	TaskRecruitmentReminder,
+}
+
+// TaskAuditAction names a manual change made to a scheduled task
+type TaskAuditAction string
+
+const (
+	TaskAuditReschedule TaskAuditAction = "reschedule"
+	TaskAuditRunNow     TaskAuditAction = "run_now"
+	TaskAuditCancel     TaskAuditAction = "cancel"
+)
+
+// TaskAuditEntry records who changed a scheduled task and how
+type TaskAuditEntry struct {
+	TaskID       string          `json:"task_id"`
+	TaskType     TaskType        `json:"task_type"`
+	Action       TaskAuditAction `json:"action"`
+	Actor        string          `json:"actor"`                   // Discord user ID of the person who made the change
+	PreviousTime int64           `json:"previous_time,omitempty"` // Scheduled unix time before the change
+	NewTime      int64           `json:"new_time,omitempty"`      // Scheduled unix time after the change (0 when cancelled)
+	Timestamp    int64           `json:"timestamp"`
+}
+
+// TaskQueueStats counts the tasks in each task set
+type TaskQueueStats struct {
+	Queued     int64 `json:"queued"`
+	InFlight   int64 `json:"in_flight"`
+	DeadLetter int64 `json:"dead_letter"`
+}
+
+// TaskRetryPolicy controls how a failed task is rescheduled
+type TaskRetryPolicy struct {
+	MaxAttempts int           // Total attempts before the task is moved to the dead-letter queue
+	BaseDelay   time.Duration // Delay before the first retry; doubled on each subsequent retry
+	MaxDelay    time.Duration // Upper bound on the delay between retries
+}
+
+// DefaultTaskRetryPolicy is used for task types without an explicit policy
+var DefaultTaskRetryPolicy = TaskRetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Minute,
+	MaxDelay:    time.Hour,
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry)
+func (p TaskRetryPolicy) Backoff(retry int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < retry && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
 }
 
 // TaskParams is an interface that all function-specific parameter structs must implement
@@ -35,23 +104,26 @@ type UserTaskParams interface {
 // Task represents a scheduled task in the system
 type Task struct {
 	TaskID        string          `json:"task_id"`
+	GuildID       string          `json:"guild_id,omitempty"` // Empty for tasks queued before the bot served several guilds
 	FunctionName  TaskType        `json:"function_name"`
 	Params        json.RawMessage `json:"params"` // Store raw JSON to be unmarshaled into specific param types
 	ScheduledTime int64           `json:"scheduled_time"`
-	Status        string          `json:"status"`
+	Status        TaskStatus      `json:"status"`
 	Retries       int             `json:"retries"`
+	LastError     string          `json:"last_error,omitempty"`
+	Result        string          `json:"result,omitempty"`
 	CreatedBy     string          `json:"created_by"`
 	Scenario      string          `json:"scenario,omitempty"` // Optional scenario that created this task
 }
 
 // GetParams unmarshals the raw params into the appropriate struct type
 func (t *Task) GetParams() (TaskParams, error) {
-	paramCreator, exists := TaskTypeMap[t.FunctionName]
-	if !exists {
+	def, exists := GetTaskDefinition(t.FunctionName)
+	if !exists || def.NewParams == nil {
 		return nil, fmt.Errorf("unknown function type: %s", t.FunctionName)
 	}
 
-	params := paramCreator()
+	params := def.NewParams()
 	if err := json.Unmarshal(t.Params, params); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
 	}
@@ -73,13 +145,27 @@ func (t *Task) IsForUser(userID string) bool {
 	return false
 }
 
+// UserID returns the user the task acts on, or "" if its params are not user-specific
+func (t *Task) UserID() string {
+	params, err := t.GetParams()
+	if err != nil {
+		return ""
+	}
+
+	if userParams, ok := params.(UserTaskParams); ok {
+		return userParams.GetUserID()
+	}
+
+	return ""
+}
+
 // IsForScenario checks if this task was created by a specific scenario
 func (t *Task) IsForScenario(scenario string) bool {
 	return t.Scenario == scenario
 }
 
-// NewTaskWithScenario creates a new task with scenario information
-func NewTaskWithScenario(functionName TaskType, params TaskParams, scheduledTime int64, scenario string) (*Task, error) {
+// NewTaskWithScenario creates a new task for a guild with scenario information
+func NewTaskWithScenario(guildID string, functionName TaskType, params TaskParams, scheduledTime int64, scenario string) (*Task, error) {
 	// Validate parameters
 	if err := params.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid parameters: %w", err)
@@ -100,10 +186,11 @@ func NewTaskWithScenario(functionName TaskType, params TaskParams, scheduledTime
 
 	return &Task{
 		TaskID:        taskID,
+		GuildID:       guildID,
 		FunctionName:  functionName,
 		Params:        paramsJSON,
 		ScheduledTime: scheduledTime,
-		Status:        "pending",
+		Status:        TaskStatusPending,
 		Retries:       0,
 		CreatedBy:     "system",
 		Scenario:      scenario,
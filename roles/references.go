@@ -1,11 +1,8 @@
 package roles
 
-import (
-	"log"
-	"os"
-)
+import "astralHRBot/config"
 
-// Content notification role environment variable names
+// Role setting keys, which are also the environment variables they are first seeded from
 const (
 	MiningRole   = "MINING_ROLE_ID"
 	IndustryRole = "INDUSTRY_ROLE_ID"
@@ -24,64 +21,97 @@ const (
 	AuthenticatedMember = "AUTHENTICATED_MEMBER_ROLE_ID"
 )
 
-// ContentNotificationRoles contains all content notification role env var names
+// ContentNotificationRoles contains all content notification role setting keys
 var ContentNotificationRoles = []string{
-	GetMiningRoleID(),
-	GetIndustryRoleID(),
-	GetPveRoleID(),
-	GetPvpRoleID(),
-	GetFwRoleID(),
-}
-
-// GetRoleIDFromEnv returns the role ID from environment variables
-func GetRoleIDFromEnv(roleEnvVar string) string {
-	id, exists := os.LookupEnv(roleEnvVar)
-	if !exists {
-		log.Printf("Warning: Role ID not found for environment variable: %s", roleEnvVar)
-		return ""
+	MiningRole,
+	IndustryRole,
+	PveRole,
+	PvpRole,
+	FwRole,
+}
+
+// ManagedRoles are the roles the bot adds or removes itself, so its own role has to sit above them
+var ManagedRoles = append([]string{
+	GuestRole,
+	RecruitRole,
+	NewcomerRole,
+	AbsenteeRole,
+}, ContentNotificationRoles...)
+
+func init() {
+	config.Register(
+		config.Setting{Key: MiningRole, Kind: config.KindRole, Description: "Mining content notifications"},
+		config.Setting{Key: IndustryRole, Kind: config.KindRole, Description: "Industry content notifications"},
+		config.Setting{Key: PveRole, Kind: config.KindRole, Description: "PvE content notifications"},
+		config.Setting{Key: PvpRole, Kind: config.KindRole, Description: "PvP content notifications"},
+		config.Setting{Key: FwRole, Kind: config.KindRole, Description: "Faction warfare content notifications"},
+		config.Setting{Key: MemberRole, Kind: config.KindRole, Description: "Corporation members"},
+		config.Setting{Key: RecruitRole, Kind: config.KindRole, Description: "Recruits going through the recruitment process"},
+		config.Setting{Key: GuestRole, Kind: config.KindRole, Description: "Guests"},
+		config.Setting{Key: AbsenteeRole, Kind: config.KindRole, Description: "Members on leave"},
+		config.Setting{Key: ServerClown, Kind: config.KindRole, Description: "Server clown"},
+		config.Setting{Key: BlueRole, Kind: config.KindRole, Description: "Allied pilots"},
+		config.Setting{Key: NewcomerRole, Kind: config.KindRole, Description: "Newcomers who have just joined the server"},
+		config.Setting{Key: AuthenticatedGuest, Kind: config.KindRole, Description: "Guests authenticated through Alliance Auth"},
+		config.Setting{Key: AuthenticatedMember, Kind: config.KindRole, Description: "Members authenticated through Alliance Auth"},
+	)
+}
+
+// GetRoleID returns the role ID configured under a setting key in a guild
+func GetRoleID(guildID, key string) string {
+	return config.RoleID(guildID, key)
+}
+
+// GetContentNotificationRoleIDs returns a map of role setting keys to their IDs
+func GetContentNotificationRoleIDs(guildID string) map[string]string {
+	roleIDs := make(map[string]string)
+	for _, key := range ContentNotificationRoles {
+		roleIDs[key] = GetRoleID(guildID, key)
 	}
-	return id
+	return roleIDs
 }
 
-// GetContentNotificationRoleIDs returns a map of role names to their IDs
-func GetContentNotificationRoleIDs() map[string]string {
-	roleIDs := make(map[string]string)
-	for _, envVar := range ContentNotificationRoles {
-		roleIDs[envVar] = GetRoleIDFromEnv(envVar)
+// ContentNotificationRoleIDs returns the IDs of the configured content notification roles
+func ContentNotificationRoleIDs(guildID string) []string {
+	roleIDs := make([]string, 0, len(ContentNotificationRoles))
+	for _, key := range ContentNotificationRoles {
+		if id := GetRoleID(guildID, key); id != "" {
+			roleIDs = append(roleIDs, id)
+		}
 	}
 	return roleIDs
 }
 
-func GetMemberRoleID() string {
-	return GetRoleIDFromEnv(MemberRole)
+func GetMemberRoleID(guildID string) string {
+	return GetRoleID(guildID, MemberRole)
 }
 
-func GetRecruitRoleID() string {
-	return GetRoleIDFromEnv(RecruitRole)
+func GetRecruitRoleID(guildID string) string {
+	return GetRoleID(guildID, RecruitRole)
 }
 
-func GetGuestRoleID() string {
-	return GetRoleIDFromEnv(GuestRole)
+func GetGuestRoleID(guildID string) string {
+	return GetRoleID(guildID, GuestRole)
 }
 
-func GetAbsenteeRoleID() string {
-	return GetRoleIDFromEnv(AbsenteeRole)
+func GetAbsenteeRoleID(guildID string) string {
+	return GetRoleID(guildID, AbsenteeRole)
 }
 
-func GetServerClownRoleID() string {
-	return GetRoleIDFromEnv(ServerClown)
+func GetServerClownRoleID(guildID string) string {
+	return GetRoleID(guildID, ServerClown)
 }
 
-func GetBlueRoleID() string {
-	return GetRoleIDFromEnv(BlueRole)
+func GetBlueRoleID(guildID string) string {
+	return GetRoleID(guildID, BlueRole)
 }
 
-func GetNewcomerRoleID() string {
-	return GetRoleIDFromEnv(NewcomerRole)
+func GetNewcomerRoleID(guildID string) string {
+	return GetRoleID(guildID, NewcomerRole)
 }
 
-func GetAuthenticatedGuestRoleID() string {
-	return GetRoleIDFromEnv(AuthenticatedGuest)
+func GetAuthenticatedGuestRoleID(guildID string) string {
+	return GetRoleID(guildID, AuthenticatedGuest)
 }
 
 // HasRole checks if a user has a specific role
@@ -94,26 +124,26 @@ func HasRole(roles []string, roleID string) bool {
 	return false
 }
 
-func GetAuthenticatedMemberRoleID() string {
-	return GetRoleIDFromEnv(AuthenticatedMember)
+func GetAuthenticatedMemberRoleID(guildID string) string {
+	return GetRoleID(guildID, AuthenticatedMember)
 }
 
-func GetMiningRoleID() string {
-	return GetRoleIDFromEnv(MiningRole)
+func GetMiningRoleID(guildID string) string {
+	return GetRoleID(guildID, MiningRole)
 }
 
-func GetIndustryRoleID() string {
-	return GetRoleIDFromEnv(IndustryRole)
+func GetIndustryRoleID(guildID string) string {
+	return GetRoleID(guildID, IndustryRole)
 }
 
-func GetPveRoleID() string {
-	return GetRoleIDFromEnv(PveRole)
+func GetPveRoleID(guildID string) string {
+	return GetRoleID(guildID, PveRole)
 }
 
-func GetPvpRoleID() string {
-	return GetRoleIDFromEnv(PvpRole)
+func GetPvpRoleID(guildID string) string {
+	return GetRoleID(guildID, PvpRole)
 }
 
-func GetFwRoleID() string {
-	return GetRoleIDFromEnv(FwRole)
+func GetFwRoleID(guildID string) string {
+	return GetRoleID(guildID, FwRole)
 }
@@ -0,0 +1,261 @@
+package tasks_test
+
+import (
+	"astralHRBot/db"
+	"astralHRBot/harness"
+	"astralHRBot/models"
+	"astralHRBot/tasks"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// h runs the handlers against a fake guild, an in-memory store and a fake clock
+var h *harness.Harness
+
+func TestMain(m *testing.M) {
+	var err error
+	h, err = harness.Start(harness.Config{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start harness: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(m.Run())
+}
+
+// recruit describes the member a handler test runs against
+type recruit struct {
+	inGuild  bool
+	roles    []string
+	thread   bool
+	messages int // recruitment process messages already counted
+}
+
+// setup puts a recruit into the fake guild and the store, returning the guild context
+func (r recruit) setup(t *testing.T, userID, name string, scenario models.MonitoringScenario) context.Context {
+	t.Helper()
+	ctx := db.WithGuild(context.Background(), harness.GuildID)
+
+	if r.inGuild {
+		h.Guild.AddMember(userID, name, r.roles...)
+	}
+	if r.thread {
+		if _, err := h.Guild.ForumThreadStart(harness.RecruitmentForumID, fmt.Sprintf("%s - %s", name, userID), 10080, name+" Joined Recruitment"); err != nil {
+			t.Fatalf("failed to open thread: %v", err)
+		}
+	}
+	if r.messages > 0 {
+		if err := h.Store.IncrementAnalytics(ctx, userID, scenario, "messages", r.messages); err != nil {
+			t.Fatalf("failed to seed analytics: %v", err)
+		}
+	}
+	return ctx
+}
+
+func newTask(t *testing.T, taskType models.TaskType, params models.TaskParams) models.Task {
+	t.Helper()
+	task, err := models.NewTaskWithScenario(harness.GuildID, taskType, params, h.Clock.Now().Unix(), "")
+	if err != nil {
+		t.Fatalf("failed to build task: %v", err)
+	}
+	return *task
+}
+
+func checkOutcome(t *testing.T, result models.TaskResult, err error, wantSummary, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("error = %v, want one containing %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Summary != wantSummary {
+		t.Errorf("summary = %q, want %q", result.Summary, wantSummary)
+	}
+	if err := h.Settle(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessRecruitmentReminder(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		recruit     recruit
+		wantSummary string
+		wantMessage string // part of the reminder posted in the recruitment channel, after the mention
+		wantErr     string
+	}{
+		{
+			name:        "unauthenticated recruit is reminded to authenticate",
+			userID:      "800000000000000101",
+			recruit:     recruit{inGuild: true, roles: []string{harness.RecruitRoleID}},
+			wantSummary: "reminder sent to unauthenticated recruit",
+			wantMessage: "Are you still interested",
+		},
+		{
+			name:        "quiet authenticated recruit is reminded to talk to a recruiter",
+			userID:      "800000000000000102",
+			recruit:     recruit{inGuild: true, roles: []string{harness.RecruitRoleID, harness.AuthenticatedGuestRoleID}},
+			wantSummary: "reminder sent to authenticated recruit",
+			wantMessage: "It looks like you have completed",
+		},
+		{
+			name:        "active authenticated recruit is left alone",
+			userID:      "800000000000000103",
+			recruit:     recruit{inGuild: true, roles: []string{harness.RecruitRoleID, harness.AuthenticatedGuestRoleID}, messages: 2},
+			wantSummary: "authenticated and active, no reminder needed",
+		},
+		{
+			name:    "recruit who left the server fails",
+			userID:  "800000000000000104",
+			recruit: recruit{},
+			wantErr: "unknown member",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.recruit.setup(t, tt.userID, "reminded", models.MonitoringScenarioRecruitmentProcess)
+			params := &models.RecruitmentReminderParams{UserID: tt.userID}
+
+			result, err := tasks.ProcessRecruitmentReminder(ctx, newTask(t, models.TaskRecruitmentReminder, params), params)
+			checkOutcome(t, result, err, tt.wantSummary, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			mention := "<@" + tt.userID + ">"
+			if tt.wantMessage != "" {
+				if err := h.ExpectMessage(harness.RecruitmentChannelID, mention+" "+tt.wantMessage); err != nil {
+					t.Error(err)
+				}
+			} else if err := h.ExpectNoMessage(harness.RecruitmentChannelID, mention); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestProcessRecruitmentCleanup(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        string
+		recruit       recruit
+		wantSummary   string
+		wantRole      bool
+		wantThreadMsg string
+		wantClosedTag string
+		wantErr       string
+	}{
+		{
+			name:          "active recruit keeps the recruit role",
+			userID:        "800000000000000201",
+			recruit:       recruit{inGuild: true, roles: []string{harness.RecruitRoleID}, thread: true, messages: 1},
+			wantSummary:   "user was active, recruit role kept",
+			wantRole:      true,
+			wantThreadMsg: "Automated check passed",
+		},
+		{
+			name:          "inactive recruit loses the recruit role",
+			userID:        "800000000000000202",
+			recruit:       recruit{inGuild: true, roles: []string{harness.RecruitRoleID}, thread: true},
+			wantSummary:   "no activity, recruit role removed",
+			wantThreadMsg: "No activity in recruitment process",
+			wantClosedTag: "Newbie role removed",
+		},
+		{
+			name:    "task for the bot itself is rejected",
+			userID:  harness.BotUserID,
+			wantErr: "bot user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := tt.recruit.setup(t, tt.userID, "cleaned", models.MonitoringScenarioRecruitmentProcess)
+			params := &models.RecruitmentCleanupParams{UserID: tt.userID}
+
+			result, err := tasks.ProcessRecruitmentCleanup(ctx, newTask(t, models.TaskRecruitmentCleanup, params), params)
+			checkOutcome(t, result, err, tt.wantSummary, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			check := h.ExpectNoRole
+			if tt.wantRole {
+				check = h.ExpectRole
+			}
+			if err := check(tt.userID, harness.RecruitRoleID); err != nil {
+				t.Error(err)
+			}
+			if err := h.ExpectThreadMessage(tt.userID, tt.wantThreadMsg); err != nil {
+				t.Error(err)
+			}
+			if tt.wantClosedTag != "" {
+				if err := h.ExpectThreadClosed(tt.userID, tt.wantClosedTag); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessUserCheckin(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		recruit     recruit
+		wantSummary string
+		wantErr     string
+	}{
+		{
+			name:        "first week analytics are posted to the hub and the thread",
+			userID:      "800000000000000301",
+			recruit:     recruit{inGuild: true, roles: []string{harness.MemberRoleID}, thread: true},
+			wantSummary: "first week analytics posted",
+		},
+		{
+			name:        "member without a recruitment thread still gets analytics in the hub",
+			userID:      "800000000000000302",
+			recruit:     recruit{inGuild: true, roles: []string{harness.MemberRoleID}},
+			wantSummary: "first week analytics posted to the hub, no recruitment thread found",
+		},
+		{
+			name:    "member who left the server fails",
+			userID:  "800000000000000303",
+			recruit: recruit{},
+			wantErr: "unknown member",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := "checkin" + tt.userID[len(tt.userID)-1:]
+			ctx := tt.recruit.setup(t, tt.userID, name, models.MonitoringScenarioNewRecruit)
+			params := &models.UserCheckinParams{UserID: tt.userID}
+
+			result, err := tasks.ProcessUserCheckin(ctx, newTask(t, models.TaskUserCheckin, params), params)
+			checkOutcome(t, result, err, tt.wantSummary, tt.wantErr)
+			if tt.wantErr != "" {
+				return
+			}
+
+			title := name + "'s First Week Analytics"
+			if err := h.ExpectMessage(harness.RecruitmentHubID, title); err != nil {
+				t.Error(err)
+			}
+			if !tt.recruit.thread {
+				return
+			}
+			if err := h.ExpectThreadMessage(tt.userID, title); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
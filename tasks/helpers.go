@@ -0,0 +1,58 @@
+package tasks
+
+import (
+	"astralHRBot/bot/identity"
+	"astralHRBot/models"
+	"astralHRBot/workers/eventWorker"
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Work states shared between runForUser and the closure it queues
+const (
+	workPending int32 = iota
+	workStarted
+	workAbandoned
+)
+
+// runForUser submits work for a user in the task's guild to the user's event worker so it stays
+// ordered with their other events, then waits for it to finish and returns its error.
+//
+// If ctx ends before the shard reaches the work, the work is abandoned and never runs, so the task
+// can be retried safely. Once the work has started it is always waited for, because giving up
+// then would let a retry repeat actions that are already under way.
+func runForUser(ctx context.Context, task models.Task, userID string, work func(e eventWorker.Event) error) error {
+	// Submit silently drops events for the bot itself, which would leave us waiting forever
+	if userID == identity.GetBotID() {
+		return fmt.Errorf("cannot run task for the bot user")
+	}
+
+	var state atomic.Int32
+	done := make(chan error, 1)
+
+	err := eventWorker.Submit(task.GuildID, userID, func(e eventWorker.Event) {
+		if ctx.Err() != nil || !state.CompareAndSwap(workPending, workStarted) {
+			return
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic while processing task: %v", r)
+			}
+		}()
+		done <- work(e)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to submit task to event worker: %w", err)
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if state.CompareAndSwap(workPending, workAbandoned) {
+			return ctx.Err()
+		}
+		return <-done
+	}
+}
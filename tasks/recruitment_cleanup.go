@@ -1,66 +1,47 @@
 package tasks
 
 import (
-	"astralHRBot/bot"
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/helper"
 	"astralHRBot/logger"
+	"astralHRBot/metrics"
 	"astralHRBot/models"
 	"astralHRBot/roles"
+	"astralHRBot/templates"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
 	"astralHRBot/workers/monitoring"
 	"context"
 	"fmt"
-	"strconv"
 )
 
 // ProcessRecruitmentCleanup handles the recruitment cleanup task
-func ProcessRecruitmentCleanup(task models.Task) {
-	params, err := task.GetParams()
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "process_recruitment_cleanup",
-			"message": "Failed to get params",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	parms := params.(*models.RecruitmentCleanupParams)
-	fmt.Println("Processing recruitment cleanup for user", parms.UserID)
-
-	eventWorker.Submit(parms.UserID, func(e eventWorker.Event) {
-		ctx := context.Background()
+func ProcessRecruitmentCleanup(ctx context.Context, task models.Task, params *models.RecruitmentCleanupParams) (models.TaskResult, error) {
+	fmt.Println("Processing recruitment cleanup for user", params.UserID)
 
+	var result models.TaskResult
+	err := runForUser(ctx, task, params.UserID, func(e eventWorker.Event) error {
 		// Get analytics for the recruitment process scenario
-		analyticsKey := fmt.Sprintf("user:%s:analytics:recruitment_process", e.UserID)
-		fields, err := db.GetRedisClient().HGetAll(ctx, analyticsKey).Result()
+		analytics, err := db.GetStore().GetScenarioAnalytics(ctx, e.UserID, models.MonitoringScenarioRecruitmentProcess)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "process_recruitment_cleanup",
 				"message": "Failed to get recruitment process analytics",
 				"error":   err.Error(),
 			})
-			return
+			return err
 		}
 
-		// Check if user has been active (sent messages or created invites)
-		hasActivity := false
-
-		// Check messages count
-		if messages, ok := fields["messages"]; ok {
-			if val, err := strconv.ParseInt(messages, 10, 64); err == nil && val > 0 {
-				hasActivity = true
-			}
-		}
+		// Check if user has been active (sent messages)
+		hasActivity := analytics.Messages > 0
 
 		logger.Debug(logger.LogData{
 			"trace_id":     e.TraceID,
 			"action":       "process_recruitment_cleanup",
 			"message":      "Checked recruitment process analytics",
 			"user_id":      e.UserID,
-			"analytics":    fields,
+			"analytics":    analytics,
 			"has_activity": hasActivity,
 		})
 
@@ -73,8 +54,9 @@ func ProcessRecruitmentCleanup(task models.Task) {
 			})
 
 			// Send confirmation message to recruitment thread
-			rtm := helper.NewRecruitmentThreadManager(bot.Discord, e, e.UserID)
-			rtm.SendMessage("✅ Automated check passed - user has shown activity in recruitment process scenario. Keeping recruit role.")
+			rtm := helper.NewRecruitmentThreadManager(discord.GetClient(), e, e.UserID)
+			rtm.SendMessage(templates.Render(templates.ThreadCleanupKept, templates.Data{GuildID: e.GuildID, UserID: e.UserID}))
+			result.Summary = "user was active, recruit role kept"
 		}
 
 		if !hasActivity {
@@ -85,54 +67,18 @@ func ProcessRecruitmentCleanup(task models.Task) {
 				"user_id":  e.UserID,
 			})
 
-			discordAPIWorker.NewRequest(e, func() error {
-				guildID, err := bot.GetGuildID()
-				if err != nil {
-					logger.Error(logger.LogData{
-						"trace_id": e.TraceID,
-						"action":   "process_recruitment_cleanup",
-						"message":  "Failed to get guild ID",
-						"error":    err.Error(),
-					})
-					return err
-				}
-
-				err = bot.Discord.GuildMemberRoleRemove(guildID, e.UserID, roles.GetRecruitRoleID())
-				if err != nil {
-					logger.Error(logger.LogData{
-						"trace_id": e.TraceID,
-						"action":   "process_recruitment_cleanup",
-						"message":  "Failed to remove recruit role",
-						"error":    err.Error(),
-					})
-					return err
-				} else {
-					logger.Debug(logger.LogData{
-						"trace_id": e.TraceID,
-						"action":   "process_recruitment_cleanup",
-						"message":  "Successfully removed recruit role",
-						"user_id":  e.UserID,
-					})
-				}
-				return nil
-			})
+			discordAPIWorker.RemoveRole(e, e.GuildID, e.UserID, roles.GetRecruitRoleID(e.GuildID), discordAPIWorker.WithPriority(discordAPIWorker.PriorityBulk))
+			metrics.RecruitmentTransition(metrics.StageInactiveRemoved)
 
-			rtm := helper.NewRecruitmentThreadManager(bot.Discord, e, e.UserID)
-			rtm.SendMessageAndClose("❌ No activity in recruitment process scenario within the last 7 days. Flagged for removal.", "Newbie role removed")
+			rtm := helper.NewRecruitmentThreadManager(discord.GetClient(), e, e.UserID)
+			rtm.SendMessageAndClose(templates.Render(templates.ThreadCleanupRemoved, templates.Data{GuildID: e.GuildID, UserID: e.UserID}), helper.TagNewbieRoleRemoved)
+			result.Summary = "no activity, recruit role removed"
 		}
 
-		err = db.DeleteTaskFromRedis(ctx, task.TaskID)
-		monitoring.RemoveScenario(e.UserID, models.MonitoringScenarioRecruitmentProcess)
+		monitoring.RemoveScenario(e.GuildID, e.UserID, models.MonitoringScenarioRecruitmentProcess)
 
-		if err != nil {
-			logger.Error(logger.LogData{
-				"trace_id": e.TraceID,
-				"action":   "process_recruitment_cleanup",
-				"message":  "Failed to delete task from redis",
-				"error":    err.Error(),
-			})
-			return
-		}
+		return nil
+	})
 
-	}, nil)
+	return result, err
 }
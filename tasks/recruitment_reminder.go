@@ -1,53 +1,23 @@
 package tasks
 
 import (
-	"astralHRBot/bot"
 	"astralHRBot/channels"
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/logger"
 	"astralHRBot/models"
 	"astralHRBot/roles"
+	"astralHRBot/templates"
 	discordAPIWorker "astralHRBot/workers/discordAPI"
 	"astralHRBot/workers/eventWorker"
 	"context"
-	"fmt"
 )
 
 // ProcessRecruitmentReminder sends or logs a reminder for upcoming recruitment cleanup
-func ProcessRecruitmentReminder(task models.Task) {
-	paramsAny, err := task.GetParams()
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "process_recruitment_reminder",
-			"message": "failed to parse task params",
-			"error":   err.Error(),
-			"task_id": task.TaskID,
-		})
-		return
-	}
-
-	params, ok := paramsAny.(*models.RecruitmentReminderParams)
-	if !ok {
-		logger.Error(logger.LogData{
-			"action":  "process_recruitment_reminder",
-			"message": "invalid params type",
-			"task_id": task.TaskID,
-		})
-		return
-	}
-
-	guildID, err := bot.GetGuildID()
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "process_recruitment_reminder",
-			"message": "failed to get guild ID",
-			"error":   err.Error(),
-			"task_id": task.TaskID,
-		})
-		return
-	}
+func ProcessRecruitmentReminder(ctx context.Context, task models.Task, params *models.RecruitmentReminderParams) (models.TaskResult, error) {
+	guildID := task.GuildID
 
-	user, err := bot.Discord.GuildMember(guildID, params.UserID)
+	user, err := discord.GetClient().GuildMember(guildID, params.UserID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "process_recruitment_reminder",
@@ -55,29 +25,25 @@ func ProcessRecruitmentReminder(task models.Task) {
 			"error":   err.Error(),
 			"user_id": params.UserID,
 		})
-		return
+		return models.TaskResult{}, err
 	}
 
 	// Get message count from analytics
-	analyticsKey := fmt.Sprintf("user:%s:analytics:recruitment_process", params.UserID)
-	fields, err := db.GetRedisClient().HGetAll(context.Background(), analyticsKey).Result()
+	analytics, err := db.GetStore().GetScenarioAnalytics(ctx, params.UserID, models.MonitoringScenarioRecruitmentProcess)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "process_recruitment_reminder",
 			"message": "failed to get analytics",
 			"error":   err.Error(),
 		})
-		return
+		return models.TaskResult{}, err
 	}
 
 	// Check if user has sent any messages during recruitment process
-	messageCount := 0
-	if messageCountStr, exists := fields["messages"]; exists {
-		fmt.Sscanf(messageCountStr, "%d", &messageCount)
-	}
+	messageCount := analytics.Messages
 
 	// Check if user is authenticated
-	isAuthenticated := roles.HasRole(user.Roles, roles.GetAuthenticatedGuestRoleID())
+	isAuthenticated := roles.HasRole(user.Roles, roles.GetAuthenticatedGuestRoleID(guildID))
 
 	// Early return if user is authenticated and has been active
 	if isAuthenticated && messageCount > 0 {
@@ -87,51 +53,31 @@ func ProcessRecruitmentReminder(task models.Task) {
 			"user_id":  params.UserID,
 			"messages": messageCount,
 		})
-		// Mark task as done by removing it
-		_ = db.DeleteTaskFromRedis(context.Background(), task.TaskID)
-		return
+		return models.TaskResult{Summary: "authenticated and active, no reminder needed"}, nil
 	}
 
 	// Handle reminder logic based on authentication status
+	result := models.TaskResult{Summary: "reminder sent to unauthenticated recruit"}
 	if isAuthenticated {
-		discordAPIWorker.NewRequest(eventWorker.Event{
+		result.Summary = "reminder sent to authenticated recruit"
+		discordAPIWorker.SendMessage(eventWorker.Event{
+			GuildID: guildID,
 			TraceID: task.TaskID,
 			UserID:  params.UserID,
-		}, func() error {
-			_, err := bot.Discord.ChannelMessageSend(channels.GetRecruitmentChannel(), fmt.Sprintf(
-				"<@%s> It looks like you have completed the authentication steps already. If you are still interested in joining the corporation, please reach out to a recruiter.",
-				params.UserID,
-			))
-			if err != nil {
-				logger.Error(logger.LogData{
-					"action":  "process_recruitment_reminder",
-					"message": "failed to send message to recruitment channel",
-					"error":   err.Error(),
-				})
-			}
-			return nil
-		})
+		}, channels.GetRecruitmentChannel(guildID), templates.Render(templates.ReminderAuthenticated, templates.Data{
+			GuildID: guildID,
+			UserID:  params.UserID,
+		}), discordAPIWorker.WithPriority(discordAPIWorker.PriorityBulk))
 	} else {
-		discordAPIWorker.NewRequest(eventWorker.Event{
+		discordAPIWorker.SendMessage(eventWorker.Event{
+			GuildID: guildID,
 			TraceID: task.TaskID,
 			UserID:  params.UserID,
-		}, func() error {
-
-			_, err := bot.Discord.ChannelMessageSend(channels.GetRecruitmentChannel(), fmt.Sprintf(
-				"<@%s> Are you still interested in joining the corporation? If so, please complete the authentication steps provided previously and reach out to a recruiter.",
-				params.UserID,
-			))
-			if err != nil {
-				logger.Error(logger.LogData{
-					"action":  "process_recruitment_reminder",
-					"message": "failed to send message to recruitment channel",
-					"error":   err.Error(),
-				})
-			}
-			return nil
-		})
+		}, channels.GetRecruitmentChannel(guildID), templates.Render(templates.ReminderUnauthenticated, templates.Data{
+			GuildID: guildID,
+			UserID:  params.UserID,
+		}), discordAPIWorker.WithPriority(discordAPIWorker.PriorityBulk))
 	}
 
-	// Mark task as done by removing it (idempotent with current queue model)
-	_ = db.DeleteTaskFromRedis(context.Background(), task.TaskID)
+	return result, nil
 }
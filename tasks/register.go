@@ -3,17 +3,55 @@ package tasks
 import (
 	"astralHRBot/logger"
 	"astralHRBot/models"
+	"time"
 )
 
-// RegisterHandlers registers all task handlers with the models package
-func RegisterHandlers() {
-	models.TaskHandlers[models.TaskRecruitmentCleanup] = ProcessRecruitmentCleanup
-	models.TaskHandlers[models.TaskUserCheckin] = ProcessUserCheckin
-	models.TaskHandlers[models.TaskRecruitmentReminder] = ProcessRecruitmentReminder
+// RegisterHandlers registers every task type with its params, handler, timeout and retry policy,
+// then validates the registry so a missing handler stops the bot at startup
+func RegisterHandlers() error {
+	models.RegisterTask(models.NewTaskDefinition(
+		models.TaskRecruitmentCleanup,
+		func() *models.RecruitmentCleanupParams { return &models.RecruitmentCleanupParams{} },
+		ProcessRecruitmentCleanup,
+		models.TaskOptions{
+			Timeout:     5 * time.Minute,
+			RetryPolicy: models.TaskRetryPolicy{MaxAttempts: 5, BaseDelay: time.Minute, MaxDelay: time.Hour},
+		},
+	))
+
+	models.RegisterTask(models.NewTaskDefinition(
+		models.TaskUserCheckin,
+		func() *models.UserCheckinParams { return &models.UserCheckinParams{} },
+		ProcessUserCheckin,
+		models.TaskOptions{
+			Timeout:     5 * time.Minute,
+			RetryPolicy: models.TaskRetryPolicy{MaxAttempts: 3, BaseDelay: 5 * time.Minute, MaxDelay: time.Hour},
+		},
+	))
+
+	models.RegisterTask(models.NewTaskDefinition(
+		models.TaskRecruitmentReminder,
+		func() *models.RecruitmentReminderParams { return &models.RecruitmentReminderParams{} },
+		ProcessRecruitmentReminder,
+		models.TaskOptions{
+			Timeout:     2 * time.Minute,
+			RetryPolicy: models.TaskRetryPolicy{MaxAttempts: 3, BaseDelay: time.Minute, MaxDelay: 30 * time.Minute},
+		},
+	))
+
+	if err := models.ValidateTaskRegistry(); err != nil {
+		logger.Error(logger.LogData{
+			"action":  "register_handlers",
+			"message": "Task registry is incomplete",
+			"error":   err.Error(),
+		})
+		return err
+	}
 
 	logger.Info(logger.LogData{
 		"action":  "register_handlers",
 		"message": "Task handlers registered",
-		"count":   len(models.TaskHandlers),
+		"count":   len(models.TaskTypes),
 	})
+	return nil
 }
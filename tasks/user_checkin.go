@@ -1,9 +1,9 @@
 package tasks
 
 import (
-	"astralHRBot/bot"
 	"astralHRBot/channels"
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/helper"
 	"astralHRBot/logger"
 	"astralHRBot/models"
@@ -12,43 +12,18 @@ import (
 	"astralHRBot/workers/monitoring"
 	"context"
 	"fmt"
-	"strconv"
 
 	"github.com/bwmarrin/discordgo"
 )
 
 // ProcessUserCheckin handles the user checkin task
-func ProcessUserCheckin(task models.Task) {
-	params, err := task.GetParams()
-	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "process_user_checkin",
-			"message": "Failed to get params",
-			"error":   err.Error(),
-		})
-		return
-	}
-
-	parms := params.(*models.UserCheckinParams)
-	fmt.Println("Processing user checkin for user", parms.UserID)
-
-	eventWorker.Submit(parms.UserID, func(e eventWorker.Event) {
-		ctx := context.Background()
-
-		// Get guild ID
-		guildID, err := bot.GetGuildID()
-		if err != nil {
-			logger.Error(logger.LogData{
-				"trace_id": e.TraceID,
-				"action":   "process_user_checkin",
-				"message":  "Failed to get guild ID",
-				"error":    err.Error(),
-			})
-			return
-		}
+func ProcessUserCheckin(ctx context.Context, task models.Task, params *models.UserCheckinParams) (models.TaskResult, error) {
+	fmt.Println("Processing user checkin for user", params.UserID)
 
+	summary := "first week analytics posted"
+	err := runForUser(ctx, task, params.UserID, func(e eventWorker.Event) error {
 		// Get user info from Discord
-		member, err := bot.Discord.GuildMember(guildID, e.UserID)
+		member, err := discord.GetClient().GuildMember(e.GuildID, e.UserID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": e.TraceID,
@@ -56,7 +31,7 @@ func ProcessUserCheckin(task models.Task) {
 				"message":  "Failed to get member from Discord",
 				"error":    err.Error(),
 			})
-			return
+			return err
 		}
 
 		displayName := member.Nick
@@ -68,8 +43,7 @@ func ProcessUserCheckin(task models.Task) {
 		}
 
 		// Get analytics for the new_recruit scenario
-		analyticsKey := fmt.Sprintf("user:%s:analytics:new_recruit", e.UserID)
-		fields, err := db.GetRedisClient().HGetAll(ctx, analyticsKey).Result()
+		analytics, err := db.GetStore().GetScenarioAnalytics(ctx, e.UserID, models.MonitoringScenarioNewRecruit)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": e.TraceID,
@@ -77,37 +51,13 @@ func ProcessUserCheckin(task models.Task) {
 				"message":  "Failed to get user analytics for new_recruit scenario",
 				"error":    err.Error(),
 			})
-			return
+			return err
 		}
 
-		// Parse analytics fields
-		messages := 0
-		voiceJoins := 0
-		invites := 0
-		topChannelID := ""
-
-		if msgStr, exists := fields["messages"]; exists {
-			if msg, err := strconv.Atoi(msgStr); err == nil {
-				messages = msg
-			}
-		}
-		if voiceStr, exists := fields["voice_joins"]; exists {
-			if voice, err := strconv.Atoi(voiceStr); err == nil {
-				voiceJoins = voice
-			}
-		}
-		if inviteStr, exists := fields["invites"]; exists {
-			if invite, err := strconv.Atoi(inviteStr); err == nil {
-				invites = invite
-			}
-		}
-
-		// Get the top channel from the new_recruit scenario sorted set
-		channelsKey := fmt.Sprintf("user:%s:channels:new_recruit", e.UserID)
-		topChan, err := db.GetRedisClient().ZRevRangeWithScores(ctx, channelsKey, 0, 0).Result()
-		if err == nil && len(topChan) > 0 {
-			topChannelID = topChan[0].Member.(string)
-		}
+		messages := analytics.Messages
+		voiceJoins := analytics.VoiceJoins
+		invites := analytics.Invites
+		topChannelID := analytics.TopChannelID
 
 		logger.Debug(logger.LogData{
 			"trace_id": e.TraceID,
@@ -159,7 +109,7 @@ func ProcessUserCheckin(task models.Task) {
 
 		// Send to recruitment hub
 		discordAPIWorker.NewRequest(e, func() error {
-			_, err := bot.Discord.ChannelMessageSendEmbed(channels.GetRecruitmentHub(), &embededMessage)
+			_, err := discord.GetClient().ChannelMessageSendEmbed(channels.GetRecruitmentHub(e.GuildID), &embededMessage)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"trace_id": e.TraceID,
@@ -170,35 +120,31 @@ func ProcessUserCheckin(task models.Task) {
 				return err
 			}
 			return nil
-		})
+		}, discordAPIWorker.WithRoute(discordAPIWorker.MessageRoute(channels.GetRecruitmentHub(e.GuildID))), discordAPIWorker.WithPriority(discordAPIWorker.PriorityBulk))
 
-		// Find and handle the recruitment thread
-		rtm := helper.NewRecruitmentThreadManager(bot.Discord, e, e.UserID)
+		// A missing thread is not retried, since each retry would post the analytics to the hub again
+		rtm := helper.NewRecruitmentThreadManager(discord.GetClient(), e, e.UserID)
 		if !rtm.HasThread() {
-			logger.Error(logger.LogData{
+			logger.Warn(logger.LogData{
 				"trace_id": e.TraceID,
 				"action":   "process_user_checkin",
-				"message":  "Failed to find recruitment thread",
+				"message":  "No recruitment thread found, analytics only posted to the recruitment hub",
 				"user_id":  e.UserID,
 			})
-			return
+			summary = "first week analytics posted to the hub, no recruitment thread found"
+		} else {
+			// Reopen thread, send message, then re-archive
+			rtm.ReopenThread()
+			rtm.SendMessageEmbed(&embededMessage)
+			rtm.CloseThread("")
 		}
 
-		// Reopen thread, send message, then re-archive
-		rtm.ReopenThread()
-		rtm.SendMessageEmbed(&embededMessage)
-		rtm.CloseThread("")
-
-		err = db.DeleteTaskFromRedis(ctx, task.TaskID)
-		if err != nil {
-			logger.Error(logger.LogData{
-				"trace_id": e.TraceID,
-				"action":   "process_user_checkin",
-				"message":  "Failed to delete task from redis",
-				"error":    err.Error(),
-			})
-			return
-		}
-		monitoring.RemoveScenario(e.UserID, models.MonitoringScenarioNewRecruit)
+		monitoring.RemoveScenario(e.GuildID, e.UserID, models.MonitoringScenarioNewRecruit)
+		return nil
 	})
+	if err != nil {
+		return models.TaskResult{}, err
+	}
+
+	return models.TaskResult{Summary: summary}, nil
 }
@@ -0,0 +1,155 @@
+package templates
+
+import (
+	"astralHRBot/config"
+	"astralHRBot/globals"
+)
+
+// AllianceAuthURL is the Alliance Auth site recruits register on
+const AllianceAuthURL = "ALLIANCE_AUTH_URL"
+
+// Templates for the recruitment and membership messages
+const (
+	// Welcomes
+	RecruitmentWelcome Name = "recruitment_welcome"
+	MemberWelcome      Name = "member_welcome"
+
+	// Join and leave announcements
+	ServerJoined         Name = "server_joined"
+	ServerLeft           Name = "server_left"
+	CorporationLeft      Name = "corporation_left"
+	AuthenticationHub    Name = "authentication_hub"
+	AuthenticationDirect Name = "authentication_direct"
+
+	// Reminders sent to recruits who have gone quiet
+	ReminderAuthenticated   Name = "reminder_authenticated"
+	ReminderUnauthenticated Name = "reminder_unauthenticated"
+
+	// Recruitment thread updates
+	ThreadOpened          Name = "thread_opened"
+	ThreadRejoined        Name = "thread_rejoined"
+	ThreadAuthenticated   Name = "thread_authenticated"
+	ThreadJoinedCorp      Name = "thread_joined_corporation"
+	ThreadCheckinBooked   Name = "thread_checkin_scheduled"
+	ThreadLeftRecruitment Name = "thread_left_recruitment"
+	ThreadLeftServer      Name = "thread_left_server"
+	ThreadCleanupKept     Name = "thread_cleanup_kept"
+	ThreadCleanupRemoved  Name = "thread_cleanup_removed"
+)
+
+func init() {
+	config.Register(config.Setting{
+		Key:         AllianceAuthURL,
+		Kind:        config.KindText,
+		Description: "Alliance Auth site recruits register on",
+		Default:     "https://auth.astralinc.space/",
+	})
+
+	register(definition{
+		name:        RecruitmentWelcome,
+		description: "Sent to the recruitment channel when someone is given the recruit role",
+		text: "Welcome {{.Mention}}! \n\n" +
+			"A member of the recruitment team will be with you shortly. In the meantime, please follow these steps:\n\n" +
+			"[Alliance Auth]({{setting \"ALLIANCE_AUTH_URL\"}})\n\n" +
+			"* Follow the above link and register your character(s).\n" +
+			"* In the **Char Link** tab, authorize each of your characters.\n" +
+			"* In the **Member Audit** tab, register each of your characters.\n" +
+			"* In the **Services** tab, click the checkbox to link your Discord account.\n\n" +
+			"Once you've completed this, a green tick should appear next to your character name on Discord.",
+	})
+	register(definition{
+		name:        MemberWelcome,
+		description: "Sent to the general channel when someone is given the member role",
+		text: "Welcome to Astral, {{.DisplayName}} {{.Mention}} o/ \n\n" +
+			"Please take a look at {{channel \"GUIDES_CHANNEL_ID\"}} for guides, and specifically the newbro doc for info on our region.\n\n" +
+			"If you need a hand moving your stuff around, feel free to head over to {{channel \"LOGISTICS_CHANNEL_ID\"}} to speak with them directly.\n\n" +
+			"Most importantly, head over to {{channel \"CONTENT_OPT_OUT_CHANNEL_ID\"}} to opt out of the content pings that do not interest you.\n\n" +
+			"Clear skies,\n" +
+			"And KTF!",
+	})
+
+	register(definition{
+		name:        ServerJoined,
+		description: "Sent to the landing channel when someone joins the server",
+		text:        "{{.DisplayName}} Joined The Server.",
+	})
+	register(definition{
+		name:        ServerLeft,
+		description: "Sent to the leavers channel when someone leaves the server",
+		text:        "{{.DisplayName}} Left The Server.",
+	})
+	register(definition{
+		name:        CorporationLeft,
+		description: "Sent to the HR channel when a member loses the member role",
+		text:        "{{.DisplayName}}, has left the corporation and their discord access has been removed.",
+	})
+	register(definition{
+		name:        AuthenticationHub,
+		description: "Sent to the recruitment hub when a recruit finishes authenticating",
+		text:        "{{.DisplayName}} has completed the authentication steps.",
+	})
+	register(definition{
+		name:        AuthenticationDirect,
+		description: "Sent to a recruit directly when they finish authenticating",
+		text:        "The authentication steps for Astral Acquisitions Inc have been completed. Please reach out to a recruiter in the recruitment channel.",
+	})
+
+	register(definition{
+		name:        ReminderAuthenticated,
+		description: "Reminder for an authenticated recruit who has not said anything",
+		text:        "{{.Mention}} It looks like you have completed the authentication steps already. If you are still interested in joining the corporation, please reach out to a recruiter.",
+	})
+	register(definition{
+		name:        ReminderUnauthenticated,
+		description: "Reminder for a recruit who has not authenticated",
+		text:        "{{.Mention}} Are you still interested in joining the corporation? If so, please complete the authentication steps provided previously and reach out to a recruiter.",
+	})
+
+	register(definition{
+		name:        ThreadOpened,
+		description: "First message of a new recruitment thread",
+		text:        "{{.DisplayName}} Joined Recruitment",
+	})
+	register(definition{
+		name:        ThreadRejoined,
+		description: "Posted when a recruit with an existing thread is given the recruit role again",
+		text:        "{{.DisplayName}} Rejoined Recruitment",
+	})
+	register(definition{
+		name:        ThreadAuthenticated,
+		description: "Posted when a recruit finishes authenticating",
+		text:        "{{.DisplayName}} Authentication Steps Complete.",
+	})
+	register(definition{
+		name:        ThreadJoinedCorp,
+		description: "Posted when a recruit is given the member role",
+		text:        "Character Joined Corporation.",
+	})
+	register(definition{
+		name:        ThreadCheckinBooked,
+		description: "Posted when a new member's check-in is scheduled",
+		text:        "User checkin scheduled for {{.Days}} days time.",
+		days:        globals.GetNewRecruitTrackingDays,
+	})
+	register(definition{
+		name:        ThreadLeftRecruitment,
+		description: "Posted when a recruit loses the recruit role without becoming a member",
+		text:        "{{.DisplayName}} has left the recruitment channel.",
+	})
+	register(definition{
+		name:        ThreadLeftServer,
+		description: "Posted before a thread is closed because the recruit left the server",
+		text:        "{{.DisplayName}} left the server.",
+	})
+	register(definition{
+		name:        ThreadCleanupKept,
+		description: "Posted when the recruitment cleanup finds activity and keeps the recruit role",
+		text:        "✅ Automated check passed - user has shown activity in recruitment process scenario. Keeping recruit role.",
+	})
+	register(definition{
+		name:        ThreadCleanupRemoved,
+		description: "Posted when the recruitment cleanup finds no activity and removes the recruit role",
+		text:        "❌ No activity in recruitment process scenario within the last {{.Days}} days. Flagged for removal.",
+		days:        globals.GetRecruitmentCleanupDelay,
+	})
+}
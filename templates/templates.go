@@ -0,0 +1,176 @@
+package templates
+
+import (
+	"astralHRBot/config"
+	"astralHRBot/logger"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// MaxLength is Discord's limit on message length, which a rendered template must fit within
+const MaxLength = 2000
+
+// sampleID stands in for user, channel and role IDs when a template is validated. It is as long
+// as the longest IDs Discord hands out, so the sample render is no shorter than a real one.
+const sampleID = "12345678901234567890"
+
+// sampleData is what a template is rendered with when it is validated. The display name is as
+// long as Discord allows.
+var sampleData = Data{UserID: sampleID, DisplayName: strings.Repeat("x", 32), Days: 1}
+
+// Name identifies a message template
+type Name string
+
+// Data is what a template can refer to. Days is filled in from the template's own setting, such
+// as the tracking period, when the caller leaves it at zero. GuildID picks whose templates and
+// settings are used.
+type Data struct {
+	GuildID     string
+	UserID      string
+	DisplayName string
+	Days        int
+}
+
+// Mention returns the user's mention, for use as {{.Mention}}
+func (d Data) Mention() string {
+	return fmt.Sprintf("<@%s>", d.UserID)
+}
+
+// definition is a template's built-in text and where its Days come from
+type definition struct {
+	name        Name
+	description string
+	text        string
+	days        func(guildID string) int
+}
+
+var definitions = make(map[Name]definition)
+
+// funcs are available in every template and read the settings of the guild being rendered for:
+//
+//	{{channel "RECRUITMENT_CHANNEL_ID"}} mentions a configured channel
+//	{{role "MEMBER_ROLE_ID"}} mentions a configured role
+//	{{setting "ALLIANCE_AUTH_URL"}} inserts any other setting
+func funcs(guildID string) template.FuncMap {
+	return template.FuncMap{
+		"channel": func(key string) string { return fmt.Sprintf("<#%s>", config.Get(guildID, key)) },
+		"role":    func(key string) string { return fmt.Sprintf("<@&%s>", config.Get(guildID, key)) },
+		"setting": func(key string) string { return config.Get(guildID, key) },
+	}
+}
+
+// sampleFuncs stand in for funcs when a template is validated, since no guild is being rendered for
+func sampleFuncs() template.FuncMap {
+	return template.FuncMap{
+		"channel": func(key string) string { return fmt.Sprintf("<#%s>", sampleID) },
+		"role":    func(key string) string { return fmt.Sprintf("<@&%s>", sampleID) },
+		"setting": func(key string) string { return config.Get("", key) },
+	}
+}
+
+// register adds a template and its config setting
+func register(def definition) {
+	definitions[def.name] = def
+	config.Register(config.Setting{
+		Key:         SettingKey(def.name),
+		Kind:        config.KindTemplate,
+		Description: def.description,
+		Default:     def.text,
+		Validate:    Validate,
+	})
+}
+
+// SettingKey is the config setting a template is stored under
+func SettingKey(name Name) string {
+	return "TEMPLATE_" + strings.ToUpper(string(name))
+}
+
+// Names returns every template name in order
+func Names() []Name {
+	names := make([]Name, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		return names[a] < names[b]
+	})
+	return names
+}
+
+// Description returns what a template is used for
+func Description(name Name) string {
+	return definitions[name].description
+}
+
+// Default returns a template's built-in text
+func Default(name Name) string {
+	return definitions[name].text
+}
+
+// Validate checks that text parses and renders with sample data within MaxLength, so a typo in a
+// placeholder or an over-long message is caught when the template is saved rather than when it
+// is next sent
+func Validate(text string) error {
+	_, err := execute(text, sampleData, sampleFuncs())
+	return err
+}
+
+// Render fills in a template. If the stored text fails to render or renders longer than
+// MaxLength, the error is logged and the built-in text is used instead so the message is still sent.
+func Render(name Name, data Data) string {
+	def, exists := definitions[name]
+	if !exists {
+		logger.Error(logger.LogData{
+			"action":   "template_render",
+			"message":  "Unknown template",
+			"template": string(name),
+		})
+		return ""
+	}
+	if data.Days == 0 && def.days != nil {
+		data.Days = def.days(data.GuildID)
+	}
+
+	text, err := execute(config.Get(data.GuildID, SettingKey(name)), data, funcs(data.GuildID))
+	if err == nil {
+		return text
+	}
+	logger.Error(logger.LogData{
+		"action":   "template_render",
+		"message":  "Failed to render template, using the default",
+		"template": string(name),
+		"guild_id": data.GuildID,
+		"error":    err.Error(),
+	})
+
+	text, err = execute(def.text, data, funcs(data.GuildID))
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":   "template_render",
+			"message":  "Failed to render default template",
+			"template": string(name),
+			"error":    err.Error(),
+		})
+	}
+	return text
+}
+
+// execute renders text, failing if the result is longer than MaxLength
+func execute(text string, data Data, funcs template.FuncMap) (string, error) {
+	tmpl, err := template.New("message").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, data); err != nil {
+		return "", err
+	}
+	if length := utf8.RuneCountInString(out.String()); length > MaxLength {
+		return "", fmt.Errorf("renders to %d characters, over Discord's limit of %d", length, MaxLength)
+	}
+	return out.String(), nil
+}
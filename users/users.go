@@ -1,12 +1,12 @@
 package users
 
 import (
+	"astralHRBot/clock"
 	"astralHRBot/db"
 	"astralHRBot/logger"
 	"astralHRBot/models"
 	"astralHRBot/workers/eventWorker"
 	"context"
-	"time"
 
 	"github.com/bwmarrin/discordgo"
 )
@@ -33,17 +33,17 @@ func CreateOrUpdateUser(e eventWorker.Event) {
 		return
 	}
 
-	ctx := context.Background()
-	existingUser, err := db.GetUserFromRedis(ctx, user.ID)
+	ctx := db.WithGuild(context.Background(), e.GuildID)
+	existingUser, err := db.GetStore().GetUser(ctx, user.ID)
 	if err != nil {
 		newUser := &models.User{
 			DiscordID:          user.ID,
 			CurrentDisplayName: user.GlobalName,
-			CurrentJoinDate:    time.Now(),
+			CurrentJoinDate:    clock.Now(),
 			Monitored:          false,
 		}
 
-		err = db.SaveUserToRedis(ctx, newUser)
+		err = db.GetStore().SaveUser(ctx, newUser)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": t,
@@ -63,10 +63,10 @@ func CreateOrUpdateUser(e eventWorker.Event) {
 		})
 	} else {
 		existingUser.PreviousJoinDate = existingUser.CurrentJoinDate
-		existingUser.CurrentJoinDate = time.Now()
+		existingUser.CurrentJoinDate = clock.Now()
 		existingUser.CurrentDisplayName = user.GlobalName
 
-		err = db.SaveUserToRedis(ctx, existingUser)
+		err = db.GetStore().SaveUser(ctx, existingUser)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": t,
@@ -91,15 +91,14 @@ func CreateOrUpdateUser(e eventWorker.Event) {
 	}
 }
 
-func UpdateRecruitmentDate(userID string) error {
-	ctx := context.Background()
+func UpdateRecruitmentDate(guildID, userID string) error {
+	ctx := db.WithGuild(context.Background(), guildID)
 
-	key := "User:" + userID
 	fields := map[string]any{
-		"DateJoinedRecruitment": time.Now(),
+		"DateJoinedRecruitment": clock.Now(),
 	}
 
-	err := db.UpdateHashFields(ctx, key, fields)
+	err := db.GetStore().UpdateUserFields(ctx, userID, fields)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "user_update",
@@ -115,22 +114,21 @@ func UpdateRecruitmentDate(userID string) error {
 		"message": "Updated user recruitment date",
 		"user_id": userID,
 		"details": map[string]any{
-			"recruitment_date": time.Now(),
+			"recruitment_date": clock.Now(),
 		},
 	})
 
 	return nil
 }
 
-func RemoveRecruitmentDate(userID string) error {
-	ctx := context.Background()
+func RemoveRecruitmentDate(guildID, userID string) error {
+	ctx := db.WithGuild(context.Background(), guildID)
 
-	key := "User:" + userID
 	fields := map[string]any{
 		"DateJoinedRecruitment": nil,
 	}
 
-	err := db.UpdateHashFields(ctx, key, fields)
+	err := db.GetStore().UpdateUserFields(ctx, userID, fields)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "user_update",
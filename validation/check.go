@@ -0,0 +1,206 @@
+package validation
+
+import (
+	"astralHRBot/channels"
+	"astralHRBot/config"
+	"astralHRBot/discord"
+	"astralHRBot/guilds"
+	"astralHRBot/helper"
+	"astralHRBot/roles"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Problem is a setting that does not match the guild. Key is empty for problems with the bot itself.
+type Problem struct {
+	Key     string
+	Message string
+}
+
+// Report is the outcome of checking the role and channel settings against the guild
+type Report struct {
+	GuildID string
+	// Checked is the number of role and channel settings looked at
+	Checked  int
+	Problems []Problem
+	// Paused is set when automation is paused because of the problems
+	Paused bool
+}
+
+// OK reports whether no problems were found
+func (r Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+func (r *Report) add(key, format string, args ...any) {
+	r.Problems = append(r.Problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+}
+
+// textChannelTypes are what any channel setting without an entry in channelTypes has to be
+var textChannelTypes = []discordgo.ChannelType{discordgo.ChannelTypeGuildText, discordgo.ChannelTypeGuildNews}
+
+// channelTypes lists the channel settings that have to be something other than a text channel
+var channelTypes = map[string][]discordgo.ChannelType{
+	channels.RecruitmentForum: {discordgo.ChannelTypeGuildForum},
+}
+
+// Check compares every role and channel setting with the live guild. It checks that each one is
+// set, unless it is optional, and exists, that channels are of the right type, that the recruitment forum offers the
+// tags threads are closed with, and that the bot's highest role sits above the roles it manages.
+func Check(s discord.DiscordClient, guildID string) Report {
+	report := Report{GuildID: guildID}
+
+	guildRoles, err := s.GuildRoles(guildID)
+	if err != nil {
+		report.add("", "Could not fetch the guild's roles: %s", err.Error())
+		return report
+	}
+	guildChannels, err := s.GuildChannels(guildID)
+	if err != nil {
+		report.add("", "Could not fetch the guild's channels: %s", err.Error())
+		return report
+	}
+
+	rolesByID := make(map[string]*discordgo.Role, len(guildRoles))
+	for _, role := range guildRoles {
+		rolesByID[role.ID] = role
+	}
+	channelsByID := make(map[string]*discordgo.Channel, len(guildChannels))
+	for _, channel := range guildChannels {
+		channelsByID[channel.ID] = channel
+	}
+
+	for _, setting := range config.Settings() {
+		if setting.Kind != config.KindRole && setting.Kind != config.KindChannel {
+			continue
+		}
+		// Global settings point into the home guild, and optional ones may be left empty
+		if setting.Global && guildID != guilds.Home() {
+			continue
+		}
+		if setting.Optional && config.Get(guildID, setting.Key) == "" {
+			continue
+		}
+
+		report.Checked++
+		if setting.Kind == config.KindRole {
+			checkRole(&report, setting.Key, rolesByID)
+		} else {
+			checkChannel(&report, setting.Key, channelsByID)
+		}
+	}
+
+	checkRoleHierarchy(&report, s, guildID, rolesByID)
+	return report
+}
+
+func checkRole(report *Report, key string, rolesByID map[string]*discordgo.Role) {
+	roleID := config.Get(report.GuildID, key)
+	if roleID == "" {
+		report.add(key, "Not set")
+		return
+	}
+	if _, exists := rolesByID[roleID]; !exists {
+		report.add(key, "Role `%s` does not exist in the guild", roleID)
+	}
+}
+
+func checkChannel(report *Report, key string, channelsByID map[string]*discordgo.Channel) {
+	channelID := config.Get(report.GuildID, key)
+	if channelID == "" {
+		report.add(key, "Not set")
+		return
+	}
+	channel, exists := channelsByID[channelID]
+	if !exists {
+		report.add(key, "Channel `%s` does not exist in the guild", channelID)
+		return
+	}
+
+	expected, exists := channelTypes[key]
+	if !exists {
+		expected = textChannelTypes
+	}
+	if !hasChannelType(expected, channel.Type) {
+		report.add(key, "<#%s> is a %s channel, expected a %s channel", channelID, channelTypeName(channel.Type), channelTypeName(expected[0]))
+		return
+	}
+
+	if key == channels.RecruitmentForum {
+		var missing []string
+		for _, tag := range helper.RecruitmentForumTags {
+			if !hasTag(channel.AvailableTags, tag) {
+				missing = append(missing, tag)
+			}
+		}
+		if len(missing) > 0 {
+			report.add(key, "<#%s> is missing the tags: %s", channelID, strings.Join(missing, ", "))
+		}
+	}
+}
+
+// checkRoleHierarchy reports managed roles the bot cannot add or remove because they are not below
+// its own highest role
+func checkRoleHierarchy(report *Report, s discord.DiscordClient, guildID string, rolesByID map[string]*discordgo.Role) {
+	member, err := s.GuildMember(guildID, s.BotUserID())
+	if err != nil {
+		report.add("", "Could not fetch the bot's roles: %s", err.Error())
+		return
+	}
+
+	highest := 0
+	for _, roleID := range member.Roles {
+		if role, exists := rolesByID[roleID]; exists && role.Position > highest {
+			highest = role.Position
+		}
+	}
+
+	for _, key := range roles.ManagedRoles {
+		role, exists := rolesByID[config.Get(guildID, key)]
+		if !exists {
+			// Missing roles are already reported
+			continue
+		}
+		if role.Position >= highest {
+			report.add(key, "<@&%s> is not below the bot's highest role, so the bot cannot add or remove it", role.ID)
+		}
+	}
+}
+
+func hasChannelType(types []discordgo.ChannelType, channelType discordgo.ChannelType) bool {
+	for _, t := range types {
+		if t == channelType {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTag(tags []discordgo.ForumTag, name string) bool {
+	for _, tag := range tags {
+		if strings.EqualFold(tag.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func channelTypeName(channelType discordgo.ChannelType) string {
+	switch channelType {
+	case discordgo.ChannelTypeGuildText:
+		return "text"
+	case discordgo.ChannelTypeGuildNews:
+		return "announcement"
+	case discordgo.ChannelTypeGuildForum:
+		return "forum"
+	case discordgo.ChannelTypeGuildVoice:
+		return "voice"
+	case discordgo.ChannelTypeGuildStageVoice:
+		return "stage"
+	case discordgo.ChannelTypeGuildCategory:
+		return "category"
+	}
+	return fmt.Sprintf("type %d", channelType)
+}
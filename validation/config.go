@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"astralHRBot/logger"
+	"os"
+	"strconv"
+)
+
+// Config controls what happens when the configuration does not match the guild
+type Config struct {
+	// Strict pauses automation from startup until a check finds no problems
+	Strict bool
+}
+
+// DefaultConfig reports problems without pausing automation
+func DefaultConfig() Config {
+	return Config{}
+}
+
+// ConfigFromEnv reads CONFIG_VALIDATION_STRICT, keeping the default if it is missing or invalid
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if value, exists := os.LookupEnv("CONFIG_VALIDATION_STRICT"); exists {
+		if strict, err := strconv.ParseBool(value); err == nil {
+			cfg.Strict = strict
+		} else {
+			warnInvalidSetting("CONFIG_VALIDATION_STRICT", value)
+		}
+	}
+	return cfg
+}
+
+func warnInvalidSetting(name, value string) {
+	logger.Warn(logger.LogData{
+		"action":  "config_validation",
+		"message": "Ignoring invalid setting, using the default",
+		"setting": name,
+		"value":   value,
+	})
+}
@@ -0,0 +1,169 @@
+// Package validation checks each guild's configured roles and channels against the live guild once
+// the gateway is connected and reports what is wrong to the guild's HR channel. In strict mode the
+// guild's automation is paused until the problems have been fixed.
+package validation
+
+import (
+	"astralHRBot/automation"
+	"astralHRBot/channels"
+	"astralHRBot/config"
+	"astralHRBot/discord"
+	"astralHRBot/guilds"
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"astralHRBot/workers/eventWorker"
+	"fmt"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// maxProblemFields is Discord's limit on the number of fields in an embed
+const maxProblemFields = 25
+
+var (
+	mu  sync.Mutex
+	cfg = DefaultConfig()
+	// holding has the guilds whose automation is paused by validation rather than by anything else
+	holding = make(map[string]bool)
+)
+
+// Start reads the configuration from the environment and calls StartWithConfig
+func Start() {
+	StartWithConfig(ConfigFromEnv())
+}
+
+// StartWithConfig prepares validation before the gateway is opened. In strict mode every guild's
+// automation is paused straight away so no event is acted on before its first check has passed.
+func StartWithConfig(c Config) {
+	mu.Lock()
+	cfg = c
+	if cfg.Strict {
+		for _, guildID := range guilds.IDs() {
+			holding[guildID] = true
+			automation.Pause(guildID, "waiting for the configuration to be checked against the guild")
+		}
+	}
+	mu.Unlock()
+
+	config.OnChange(recheck)
+
+	logger.Info(logger.LogData{
+		"action":  "config_validation",
+		"message": "Configuration validation ready",
+		"strict":  c.Strict,
+	})
+}
+
+// Validate checks a guild's configuration against the guild, logs each problem and, in strict
+// mode, pauses the guild's automation while there are problems and resumes it once there are none
+func Validate(guildID string) Report {
+	report := Check(discord.GetClient(), guildID)
+
+	for _, problem := range report.Problems {
+		logger.Warn(logger.LogData{
+			"action":   "config_validation",
+			"message":  problem.Message,
+			"guild_id": guildID,
+			"setting":  problem.Key,
+		})
+	}
+
+	mu.Lock()
+	switch {
+	case cfg.Strict && !report.OK():
+		holding[guildID] = true
+		automation.Pause(guildID, fmt.Sprintf("%d configuration problems found", len(report.Problems)))
+	case holding[guildID] && report.OK():
+		delete(holding, guildID)
+		automation.Resume(guildID)
+	}
+	mu.Unlock()
+	report.Paused = automation.Paused(guildID)
+
+	logger.Info(logger.LogData{
+		"action":   "config_validation",
+		"message":  "Configuration checked against the guild",
+		"guild_id": report.GuildID,
+		"checked":  report.Checked,
+		"problems": len(report.Problems),
+		"paused":   report.Paused,
+	})
+	return report
+}
+
+// Post sends a report to its guild's HR channel
+func Post(report Report) {
+	channelID := channels.GetHRChannel(report.GuildID)
+	if channelID == "" {
+		return
+	}
+
+	embed := report.Embed()
+	discordAPIWorker.NewRequest(eventWorker.Event{GuildID: report.GuildID}, func() error {
+		_, err := discord.GetClient().ChannelMessageSendEmbed(channelID, embed)
+		return err
+	}, discordAPIWorker.WithRoute(discordAPIWorker.MessageRoute(channelID)))
+}
+
+// recheck validates a guild again when one of its roles or channels is changed while validation is
+// holding its automation, so fixing the last problem with /config set resumes it
+func recheck(change models.ConfigChange) {
+	setting, exists := config.Lookup(change.Key)
+	if !exists || (setting.Kind != config.KindRole && setting.Kind != config.KindChannel) {
+		return
+	}
+
+	mu.Lock()
+	wasHolding := holding[change.GuildID]
+	mu.Unlock()
+	if !wasHolding {
+		return
+	}
+
+	go func() {
+		if report := Validate(change.GuildID); report.OK() {
+			Post(report)
+		}
+	}()
+}
+
+// Embed summarises the report for the HR channel
+func (r Report) Embed() *discordgo.MessageEmbed {
+	if r.OK() {
+		return &discordgo.MessageEmbed{
+			Title:       "Configuration check",
+			Description: fmt.Sprintf("✅ All %d role and channel settings match the guild.", r.Checked),
+			Color:       0x2ECC71,
+		}
+	}
+
+	description := fmt.Sprintf("❌ Found %d problems with the role and channel settings.", len(r.Problems))
+	if r.Paused {
+		description += "\n\nAutomation is paused until they are fixed with `/config set`."
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "Configuration check",
+		Description: description,
+		Color:       0xE74C3C,
+	}
+
+	for _, problem := range r.Problems {
+		if len(embed.Fields) == maxProblemFields {
+			embed.Footer = &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("%d more problems are in the logs.", len(r.Problems)-maxProblemFields),
+			}
+			break
+		}
+		name := problem.Key
+		if name == "" {
+			name = "Bot"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  name,
+			Value: problem.Message,
+		})
+	}
+	return embed
+}
@@ -0,0 +1,113 @@
+package discordAPIWorker
+
+import (
+	"astralHRBot/logger"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// OverflowPolicy decides what NewRequest does when the queue is at capacity
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes the caller wait until a request is picked up and frees a slot
+	OverflowBlock OverflowPolicy = iota
+	// OverflowReject fails the new request's future with ErrQueueFull
+	OverflowReject
+	// OverflowDropOldest evicts the oldest queued request from the lowest-priority lane, failing its
+	// future with ErrDropped. A new request is rejected instead if everything queued outranks it.
+	OverflowDropOldest
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowBlock:
+		return "block"
+	case OverflowReject:
+		return "reject"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	}
+	return "unknown"
+}
+
+// ParseOverflowPolicy reads a policy name as written by String
+func ParseOverflowPolicy(name string) (OverflowPolicy, error) {
+	for _, policy := range []OverflowPolicy{OverflowBlock, OverflowReject, OverflowDropOldest} {
+		if policy.String() == name {
+			return policy, nil
+		}
+	}
+	return OverflowBlock, fmt.Errorf("unknown overflow policy %q", name)
+}
+
+// Config sizes the worker
+type Config struct {
+	// Capacity is the most requests that may wait in the queue; running requests do not count
+	Capacity int
+	// Overflow is applied when a request is submitted to a full queue. OverflowBlock must be chosen
+	// explicitly, since it stalls whichever event worker or task is submitting until a slot frees.
+	Overflow OverflowPolicy
+	// Concurrency is how many requests on different routes may be in flight at once
+	Concurrency int
+	// DrainTimeout bounds how long Stop waits for queued requests to run before saving the rest
+	DrainTimeout time.Duration
+}
+
+// DefaultConfig is used for any setting not given in the environment
+func DefaultConfig() Config {
+	return Config{
+		Capacity:     1000,
+		Overflow:     OverflowDropOldest,
+		Concurrency:  4,
+		DrainTimeout: 15 * time.Second,
+	}
+}
+
+// ConfigFromEnv reads DISCORD_API_QUEUE_CAPACITY, DISCORD_API_QUEUE_OVERFLOW, DISCORD_API_CONCURRENCY
+// and DISCORD_API_DRAIN_TIMEOUT, keeping the default for any that is missing or invalid
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if value, exists := os.LookupEnv("DISCORD_API_QUEUE_CAPACITY"); exists {
+		if capacity, err := strconv.Atoi(value); err == nil && capacity > 0 {
+			cfg.Capacity = capacity
+		} else {
+			warnInvalidSetting("DISCORD_API_QUEUE_CAPACITY", value)
+		}
+	}
+	if value, exists := os.LookupEnv("DISCORD_API_QUEUE_OVERFLOW"); exists {
+		if policy, err := ParseOverflowPolicy(value); err == nil {
+			cfg.Overflow = policy
+		} else {
+			warnInvalidSetting("DISCORD_API_QUEUE_OVERFLOW", value)
+		}
+	}
+	if value, exists := os.LookupEnv("DISCORD_API_CONCURRENCY"); exists {
+		if concurrency, err := strconv.Atoi(value); err == nil && concurrency > 0 {
+			cfg.Concurrency = concurrency
+		} else {
+			warnInvalidSetting("DISCORD_API_CONCURRENCY", value)
+		}
+	}
+	if value, exists := os.LookupEnv("DISCORD_API_DRAIN_TIMEOUT"); exists {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout >= 0 {
+			cfg.DrainTimeout = timeout
+		} else {
+			warnInvalidSetting("DISCORD_API_DRAIN_TIMEOUT", value)
+		}
+	}
+
+	return cfg
+}
+
+func warnInvalidSetting(name, value string) {
+	logger.Warn(logger.LogData{
+		"action":  "discord_api_worker_config",
+		"message": "Ignoring invalid setting, using the default",
+		"setting": name,
+		"value":   value,
+	})
+}
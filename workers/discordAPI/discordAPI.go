@@ -1,12 +1,14 @@
 package discordAPIWorker
 
 import (
+	"astralHRBot/discord"
 	"astralHRBot/logger"
+	"astralHRBot/metrics"
+	"astralHRBot/models"
 	"astralHRBot/workers/eventWorker"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/bwmarrin/discordgo"
 )
 
 var discordAPIWorker *DiscordAPISubmissionWorker
@@ -14,74 +16,340 @@ var once sync.Once
 var workerReady sync.WaitGroup
 
 type apiRequest struct {
-	Event   eventWorker.Event
-	Execute func() error
+	Event    eventWorker.Event
+	Execute  func() error
+	route    Route
+	priority Priority
+	retry    RetryPolicy
+	after    []Awaitable
+	sequence uint64
+
+	attempts  int
+	notBefore time.Time                     // set while waiting to retry
+	resolve   func(error)                   // settles the caller's future
+	operation *models.PendingDiscordRequest // set for requests that can be saved at shutdown
+	queuedAt  time.Time
+}
+
+// routeQueue holds the requests waiting on one route key. busy is set while one of them runs.
+type routeQueue struct {
+	route    Route
+	requests []*apiRequest
+	busy     bool
 }
 
 type DiscordAPISubmissionWorker struct {
-	session      *discordgo.Session
-	requestQueue chan apiRequest
-	quit         chan struct{}
-	wg           sync.WaitGroup
+	session  discord.DiscordClient
+	cfg      Config
+	mu       sync.Mutex
+	space    *sync.Cond // signalled when a queued request is picked up or the worker stops
+	routes   map[string]*routeQueue
+	sequence uint64
+	closing  bool
+	wake     chan struct{}
+	quit     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+	pending  atomic.Int64 // requests submitted but not yet executed
+
+	// Queue depth, guarded by mu
+	queued     int
+	queuedLane [numPriorities]int
+	inFlight   int
+	highWater  int
+
+	dropped   atomic.Int64
+	rejected  atomic.Int64
+	persisted atomic.Int64
+	lost      atomic.Int64
+}
+
+// NewWorker starts the worker with settings from the environment
+func NewWorker(s discord.DiscordClient) {
+	NewWorkerWithConfig(s, ConfigFromEnv())
 }
 
-func NewWorker(s *discordgo.Session) {
+// NewWorkerWithConfig starts the worker and replays any requests saved at the last shutdown
+func NewWorkerWithConfig(s discord.DiscordClient, cfg Config) {
 	once.Do(func() {
 		discordAPIWorker = &DiscordAPISubmissionWorker{
-			session:      s,
-			requestQueue: make(chan apiRequest),
-			quit:         make(chan struct{}),
+			session: s,
+			cfg:     cfg,
+			routes:  make(map[string]*routeQueue),
+			wake:    make(chan struct{}, 1),
+			quit:    make(chan struct{}),
+		}
+		discordAPIWorker.space = sync.NewCond(&discordAPIWorker.mu)
+		logger.Info(logger.LogData{
+			"action":      "discord_api_worker_startup",
+			"message":     "DiscordAPIWoker Running",
+			"concurrency": cfg.Concurrency,
+			"capacity":    cfg.Capacity,
+			"overflow":    cfg.Overflow.String(),
+		})
+		for range cfg.Concurrency {
+			discordAPIWorker.wg.Add(1)
+			workerReady.Add(1)
+			go discordAPIWorker.run()
 		}
-		discordAPIWorker.wg.Add(1)
-		workerReady.Add(1)
-		go discordAPIWorker.run()
+		workerReady.Wait()
+		discordAPIWorker.replayBacklog()
 	})
-	workerReady.Wait()
 }
 
 func (w *DiscordAPISubmissionWorker) run() {
 	defer w.wg.Done()
-	logger.Info(logger.LogData{
-		"action":  "discord_api_worker_startup",
-		"message": "DiscordAPIWoker Running",
-	})
 	workerReady.Done()
 	for {
+		request, ok := w.next()
+		if !ok {
+			return
+		}
+
+		if err := dependencyError(request.after); err != nil {
+			logger.Warn(logger.LogData{
+				"trace_id": request.Event.TraceID,
+				"action":   "discord_api_skipped",
+				"message":  "Skipping request because a request it depends on failed",
+				"route":    request.route.Key,
+				"error":    err.Error(),
+			})
+			metrics.DiscordRequestSkipped(request.route.Name)
+			w.complete(request, err)
+			continue
+		}
+
+		request.attempts++
+		started := time.Now()
+		err := request.Execute()
+		metrics.DiscordRequest(request.route.Name, time.Since(started), err)
+		if err != nil && request.retry.shouldRetry(err, request.attempts) {
+			delay := request.retry.delay(request.attempts)
+			logger.Warn(logger.LogData{
+				"trace_id": request.Event.TraceID,
+				"action":   "discord_api_retry",
+				"message":  "Retrying failed Discord API request",
+				"route":    request.route.Key,
+				"attempt":  request.attempts,
+				"delay":    delay.String(),
+				"error":    err.Error(),
+			})
+			w.retryLater(request, delay)
+			continue
+		}
+
+		if err != nil {
+			logger.Error(logger.LogData{
+				"trace_id": request.Event.TraceID,
+				"action":   "discord_api_error",
+				"route":    request.route.Key,
+				"priority": request.priority.String(),
+				"attempts": request.attempts,
+				"error":    err.Error(),
+			})
+		}
+		w.complete(request, err)
+	}
+}
+
+// next blocks until a request can run or the worker is stopped
+func (w *DiscordAPISubmissionWorker) next() (*apiRequest, bool) {
+	for {
+		// Stop ends the drain by closing quit; whatever is still queued is saved instead of run
 		select {
-		case request := <-w.requestQueue:
-			if err := request.Execute(); err != nil {
-				logger.Error(logger.LogData{
-					"trace_id": request.Event.TraceID,
-					"action":   "discord_api_error",
-					"error":    err.Error(),
-				})
-			}
-			time.Sleep(1 * time.Second)
+		case <-w.quit:
+			return nil, false
+		default:
+		}
+
+		w.mu.Lock()
+		request, wait := w.pickLocked()
+		w.mu.Unlock()
+
+		if request != nil {
+			// Another idle worker may be able to take the next ready route
+			w.signal()
+			return request, true
+		}
 
+		var retry <-chan time.Time
+		if wait > 0 {
+			retry = time.After(wait)
+		}
+		select {
+		case <-w.wake:
+		case <-retry:
 		case <-w.quit:
-			return
+			return nil, false
 		}
 	}
 }
 
-func NewRequest(e eventWorker.Event, f func() error) {
+// pickLocked takes the oldest request from the highest-priority lane whose route is idle, whose
+// dependencies have finished and whose rate-limit buckets have capacity. When nothing is ready it
+// returns how long until a delayed route frees up, or zero if the worker should wait for a signal.
+func (w *DiscordAPISubmissionWorker) pickLocked() (*apiRequest, time.Duration) {
+	var best *routeQueue
+	var wait time.Duration
+	now := time.Now()
+
+	for _, queue := range w.routes {
+		if queue.busy || len(queue.requests) == 0 {
+			continue
+		}
+		head := queue.requests[0]
+		if best != nil {
+			bestHead := best.requests[0]
+			if head.priority > bestHead.priority || (head.priority == bestHead.priority && head.sequence > bestHead.sequence) {
+				continue
+			}
+		}
+		// Finishing a dependency signals the worker, so there is nothing to time
+		if !dependenciesDone(head.after) {
+			continue
+		}
+		limited := w.rateLimitWait(queue.route)
+		if head.notBefore.After(now) {
+			limited = max(limited, head.notBefore.Sub(now))
+		}
+		if limited > 0 {
+			if wait == 0 || limited < wait {
+				wait = limited
+			}
+			continue
+		}
+		best = queue
+	}
+
+	if best == nil {
+		return nil, wait
+	}
+
+	request := best.requests[0]
+	best.requests[0] = nil
+	best.requests = best.requests[1:]
+	best.busy = true
+	w.dequeuedLocked(request)
+	w.inFlight++
+	return request, 0
+}
+
+// rateLimitWait returns how long the longest-blocked bucket on a route needs before it has capacity
+func (w *DiscordAPISubmissionWorker) rateLimitWait(route Route) time.Duration {
+	var wait time.Duration
+	for _, bucket := range route.Buckets {
+		if bucketWait := w.session.RateLimitWait(bucket); bucketWait > wait {
+			wait = bucketWait
+		}
+	}
+	return wait
+}
+
+// retryLater puts a failed request back at the front of its route so requests queued behind it
+// still run after it
+func (w *DiscordAPISubmissionWorker) retryLater(request *apiRequest, delay time.Duration) {
+	w.mu.Lock()
+	request.notBefore = time.Now().Add(delay)
+	queue, exists := w.routes[request.route.Key]
+	if !exists {
+		queue = &routeQueue{route: request.route}
+		w.routes[request.route.Key] = queue
+	}
+	queue.requests = append([]*apiRequest{request}, queue.requests...)
+	queue.busy = false
+	w.inFlight--
+	// A retry was already admitted, so it does not wait for capacity
+	w.enqueuedLocked(request)
+	w.mu.Unlock()
+
+	w.signal()
+}
+
+// complete settles a request's future, then releases its route. The future is resolved first so
+// requests chained after it see it finished when the worker wakes.
+func (w *DiscordAPISubmissionWorker) complete(request *apiRequest, err error) {
+	request.resolve(err)
+	w.finish(request)
+}
+
+// finish releases a request's route so the next request on it can run
+func (w *DiscordAPISubmissionWorker) finish(request *apiRequest) {
+	w.mu.Lock()
+	w.inFlight--
+	if queue, exists := w.routes[request.route.Key]; exists {
+		queue.busy = false
+		if len(queue.requests) == 0 {
+			delete(w.routes, request.route.Key)
+		}
+	}
+	w.mu.Unlock()
+
+	w.pending.Add(-1)
+	w.signal()
+}
+
+func (w *DiscordAPISubmissionWorker) signal() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// NewRequest queues f to run against the Discord API and returns a future for its error. Requests
+// without a route share DefaultRoute and run in submission order; requests without a priority use
+// PriorityNormal. Callers that only need the request sent can ignore the future.
+func NewRequest(e eventWorker.Event, f func() error, opts ...RequestOption) *Future[struct{}] {
+	return NewRequestWithValue(e, func() (struct{}, error) {
+		return struct{}{}, f()
+	}, opts...)
+}
+
+// NewRequestWithValue is NewRequest for requests that produce a value, such as a created thread
+func NewRequestWithValue[T any](e eventWorker.Event, f func() (T, error), opts ...RequestOption) *Future[T] {
 	if discordAPIWorker == nil {
 		logger.Error(logger.LogData{
 			"action":  "discord_api_worker_not_initialized",
 			"message": "Worker not initialized yet!",
 		})
-		return
+		return failedFuture[T](ErrWorkerNotInitialized)
+	}
+
+	future := newFuture[T]()
+	request := &apiRequest{
+		Event: e,
+		Execute: func() error {
+			value, err := f()
+			future.value = value
+			return err
+		},
+		route:    DefaultRoute,
+		priority: PriorityNormal,
+		resolve:  future.resolve,
+	}
+	for _, opt := range opts {
+		opt(request)
 	}
 
-	discordAPIWorker.requestQueue <- apiRequest{
-		Event:   e,
-		Execute: f,
+	if err := discordAPIWorker.enqueue(request); err != nil {
+		return failedFuture[T](err)
+	}
+	return future
+}
+
+// Pending returns the number of requests that have been submitted but not yet executed
+func Pending() int64 {
+	if discordAPIWorker == nil {
+		return 0
 	}
+	return discordAPIWorker.pending.Load()
 }
 
+// Stop stops accepting requests, waits up to the drain timeout for queued requests to run, then
+// saves the operations still queued so the next start can replay them. Requests made with a
+// closure cannot be saved: any still queued fail with ErrWorkerStopped and are logged and counted
+// as lost in Stats.
 func Stop() {
 	if discordAPIWorker != nil {
-		close(discordAPIWorker.quit)
-		discordAPIWorker.wg.Wait()
+		discordAPIWorker.stop()
 	}
 }
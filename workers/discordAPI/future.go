@@ -0,0 +1,99 @@
+package discordAPIWorker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrWorkerNotInitialized is returned by futures for requests made before NewWorker
+var ErrWorkerNotInitialized = errors.New("discord API worker not initialized")
+
+// ErrDependencyFailed is returned by a request that was skipped because a request it was chained
+// after failed. The dependency's error is wrapped alongside it.
+var ErrDependencyFailed = errors.New("dependent request failed")
+
+// Awaitable is implemented by every Future regardless of its value type, so requests with
+// different results can be chained after each other
+type Awaitable interface {
+	// Done is closed once the request has finished, successfully or not
+	Done() <-chan struct{}
+	// Err returns the request's final error. It is only meaningful once Done is closed.
+	Err() error
+}
+
+// Future is the outcome of a queued request. It resolves once the request succeeds, fails with an
+// error that is not retried, or runs out of retries.
+type Future[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+func newFuture[T any]() *Future[T] {
+	return &Future[T]{done: make(chan struct{})}
+}
+
+func failedFuture[T any](err error) *Future[T] {
+	f := newFuture[T]()
+	f.resolve(err)
+	return f
+}
+
+// resolve records the final error; value has already been stored by the request
+func (f *Future[T]) resolve(err error) {
+	f.err = err
+	close(f.done)
+}
+
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *Future[T]) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+// Wait blocks until the request has finished and returns its value and error
+func (f *Future[T]) Wait() (T, error) {
+	<-f.done
+	return f.value, f.err
+}
+
+// WaitContext is Wait bounded by ctx. Giving up does not cancel the request.
+func (f *Future[T]) WaitContext(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// dependencyError returns the first failure among deps, which must all be done
+func dependencyError(deps []Awaitable) error {
+	for _, dep := range deps {
+		if err := dep.Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrDependencyFailed, err)
+		}
+	}
+	return nil
+}
+
+// dependenciesDone reports whether every dependency has finished
+func dependenciesDone(deps []Awaitable) bool {
+	for _, dep := range deps {
+		select {
+		case <-dep.Done():
+		default:
+			return false
+		}
+	}
+	return true
+}
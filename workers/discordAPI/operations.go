@@ -0,0 +1,88 @@
+package discordAPIWorker
+
+import (
+	"astralHRBot/models"
+	"astralHRBot/workers/eventWorker"
+	"fmt"
+)
+
+// Operations are requests described by data rather than a closure. If the bot shuts down before
+// one runs, it is saved to the store and replayed when the worker next starts.
+const (
+	OperationSendMessage = "send_message"
+	OperationAddRole     = "add_role"
+	OperationRemoveRole  = "remove_role"
+)
+
+// SendMessage queues a plain text message to a channel
+func SendMessage(e eventWorker.Event, channelID, content string, opts ...RequestOption) *Future[struct{}] {
+	return submitOperation(e, OperationSendMessage, map[string]string{
+		"channel_id": channelID,
+		"content":    content,
+	}, opts...)
+}
+
+// AddRole queues giving a member a role. Role changes are retried on server errors.
+func AddRole(e eventWorker.Event, guildID, userID, roleID string, opts ...RequestOption) *Future[struct{}] {
+	return submitOperation(e, OperationAddRole, map[string]string{
+		"guild_id": guildID,
+		"user_id":  userID,
+		"role_id":  roleID,
+	}, opts...)
+}
+
+// RemoveRole queues taking a role from a member. Role changes are retried on server errors.
+func RemoveRole(e eventWorker.Event, guildID, userID, roleID string, opts ...RequestOption) *Future[struct{}] {
+	return submitOperation(e, OperationRemoveRole, map[string]string{
+		"guild_id": guildID,
+		"user_id":  userID,
+		"role_id":  roleID,
+	}, opts...)
+}
+
+// submitOperation queues an operation with its default route and retry policy; opts may override them
+func submitOperation(e eventWorker.Event, operation string, args map[string]string, opts ...RequestOption) *Future[struct{}] {
+	execute, defaults, err := buildOperation(operation, args)
+	if err != nil {
+		return failedFuture[struct{}](err)
+	}
+
+	defaults = append(defaults, withOperation(operation, args))
+	return NewRequest(e, execute, append(defaults, opts...)...)
+}
+
+// buildOperation turns an operation back into a request, for both new and replayed requests
+func buildOperation(operation string, args map[string]string) (func() error, []RequestOption, error) {
+	switch operation {
+	case OperationSendMessage:
+		execute := func() error {
+			_, err := discordAPIWorker.session.ChannelMessageSend(args["channel_id"], args["content"])
+			return err
+		}
+		return execute, []RequestOption{WithRoute(MessageRoute(args["channel_id"]))}, nil
+
+	case OperationAddRole:
+		execute := func() error {
+			return discordAPIWorker.session.GuildMemberRoleAdd(args["guild_id"], args["user_id"], args["role_id"])
+		}
+		return execute, []RequestOption{WithRoute(MemberRolesRoute(args["guild_id"])), WithRetry(DefaultRetryPolicy)}, nil
+
+	case OperationRemoveRole:
+		execute := func() error {
+			return discordAPIWorker.session.GuildMemberRoleRemove(args["guild_id"], args["user_id"], args["role_id"])
+		}
+		return execute, []RequestOption{WithRoute(MemberRolesRoute(args["guild_id"])), WithRetry(DefaultRetryPolicy)}, nil
+	}
+
+	return nil, nil, fmt.Errorf("unknown Discord API operation %q", operation)
+}
+
+// withOperation records what a request does so it can be saved at shutdown
+func withOperation(operation string, args map[string]string) RequestOption {
+	return func(r *apiRequest) {
+		r.operation = &models.PendingDiscordRequest{
+			Operation: operation,
+			Args:      args,
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package discordAPIWorker
+
+import (
+	"astralHRBot/db"
+	"astralHRBot/logger"
+	"astralHRBot/models"
+	"astralHRBot/workers/eventWorker"
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrQueueFull is returned for a request rejected because the queue was at capacity
+var ErrQueueFull = errors.New("discord API queue is full")
+
+// ErrDropped is returned for a queued request evicted to make room under OverflowDropOldest
+var ErrDropped = errors.New("discord API request dropped from a full queue")
+
+// ErrWorkerStopped is returned for requests made during shutdown and for requests still queued
+// when the drain timeout ran out
+var ErrWorkerStopped = errors.New("discord API worker stopped")
+
+// backlogSaveTimeout bounds writing unsent operations to the store at shutdown
+const backlogSaveTimeout = 5 * time.Second
+
+// QueueStats is a snapshot of the worker's queue
+type QueueStats struct {
+	Queued   int            `json:"queued"`    // requests waiting to run, including ones waiting to retry
+	ByLane   map[string]int `json:"by_lane"`   // queued requests per priority lane
+	InFlight int            `json:"in_flight"` // requests currently running
+	Capacity int            `json:"capacity"`
+	// HighWater is the deepest the queue has been since startup
+	HighWater int `json:"high_water"`
+	// Dropped, Rejected, Persisted and Lost count requests since startup that were evicted under
+	// OverflowDropOldest, refused because the queue was full or during shutdown, saved at shutdown,
+	// and still queued at shutdown but not saved because they were made with a closure
+	Dropped   int64 `json:"dropped"`
+	Rejected  int64 `json:"rejected"`
+	Persisted int64 `json:"persisted"`
+	Lost      int64 `json:"lost"`
+}
+
+// Stats returns the current queue depth and overflow counters
+func Stats() QueueStats {
+	w := discordAPIWorker
+	if w == nil {
+		return QueueStats{ByLane: map[string]int{}}
+	}
+
+	w.mu.Lock()
+	stats := QueueStats{
+		Queued:    w.queued,
+		ByLane:    make(map[string]int, numPriorities),
+		InFlight:  w.inFlight,
+		Capacity:  w.cfg.Capacity,
+		HighWater: w.highWater,
+	}
+	for lane, count := range w.queuedLane {
+		stats.ByLane[Priority(lane).String()] = count
+	}
+	w.mu.Unlock()
+
+	stats.Dropped = w.dropped.Load()
+	stats.Rejected = w.rejected.Load()
+	stats.Persisted = w.persisted.Load()
+	stats.Lost = w.lost.Load()
+	return stats
+}
+
+// enqueue admits a request under the overflow policy and adds it to its route
+func (w *DiscordAPISubmissionWorker) enqueue(request *apiRequest) error {
+	w.mu.Lock()
+	for !w.closing && w.queued >= w.cfg.Capacity {
+		if w.cfg.Overflow == OverflowBlock {
+			w.space.Wait()
+			continue
+		}
+		if w.cfg.Overflow == OverflowDropOldest && w.evictLocked(request.priority) {
+			continue
+		}
+		w.mu.Unlock()
+		w.rejected.Add(1)
+		logger.Warn(logger.LogData{
+			"trace_id": request.Event.TraceID,
+			"action":   "discord_api_queue_full",
+			"message":  "Rejected Discord API request because the queue is full",
+			"route":    request.route.Key,
+			"priority": request.priority.String(),
+			"capacity": w.cfg.Capacity,
+		})
+		return ErrQueueFull
+	}
+	if w.closing {
+		w.mu.Unlock()
+		w.rejected.Add(1)
+		return ErrWorkerStopped
+	}
+
+	w.sequence++
+	request.sequence = w.sequence
+	request.queuedAt = time.Now()
+	queue, exists := w.routes[request.route.Key]
+	if !exists {
+		queue = &routeQueue{route: request.route}
+		w.routes[request.route.Key] = queue
+	}
+	queue.requests = append(queue.requests, request)
+	w.enqueuedLocked(request)
+	w.pending.Add(1)
+	w.mu.Unlock()
+
+	w.signal()
+	return nil
+}
+
+func (w *DiscordAPISubmissionWorker) enqueuedLocked(request *apiRequest) {
+	w.queued++
+	w.queuedLane[request.priority]++
+	w.highWater = max(w.highWater, w.queued)
+}
+
+func (w *DiscordAPISubmissionWorker) dequeuedLocked(request *apiRequest) {
+	w.queued--
+	w.queuedLane[request.priority]--
+	w.space.Signal()
+}
+
+// evictLocked drops the oldest queued request in the lowest-priority lane, as long as that lane is
+// no more important than the incoming request. Requests waiting to retry are never evicted.
+func (w *DiscordAPISubmissionWorker) evictLocked(incoming Priority) bool {
+	var victimQueue *routeQueue
+	victimIndex := -1
+	for _, queue := range w.routes {
+		for i, request := range queue.requests {
+			if request.attempts > 0 || request.priority < incoming {
+				continue
+			}
+			if victimQueue != nil {
+				victim := victimQueue.requests[victimIndex]
+				if request.priority < victim.priority || (request.priority == victim.priority && request.sequence > victim.sequence) {
+					continue
+				}
+			}
+			victimQueue, victimIndex = queue, i
+		}
+	}
+	if victimQueue == nil {
+		return false
+	}
+
+	victim := victimQueue.requests[victimIndex]
+	victimQueue.requests = append(victimQueue.requests[:victimIndex], victimQueue.requests[victimIndex+1:]...)
+	if len(victimQueue.requests) == 0 && !victimQueue.busy {
+		delete(w.routes, victim.route.Key)
+	}
+	w.queued--
+	w.queuedLane[victim.priority]--
+	w.pending.Add(-1)
+	w.dropped.Add(1)
+	victim.resolve(ErrDropped)
+
+	logger.Warn(logger.LogData{
+		"trace_id": victim.Event.TraceID,
+		"action":   "discord_api_queue_full",
+		"message":  "Dropped oldest Discord API request to make room",
+		"route":    victim.route.Key,
+		"priority": victim.priority.String(),
+	})
+	return true
+}
+
+// stop closes the queue to new requests, drains it until the timeout, then stops the workers and
+// saves what is left
+func (w *DiscordAPISubmissionWorker) stop() {
+	w.stopOnce.Do(func() {
+		w.mu.Lock()
+		w.closing = true
+		w.space.Broadcast()
+		w.mu.Unlock()
+
+		deadline := time.Now().Add(w.cfg.DrainTimeout)
+		for w.pending.Load() > 0 && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		close(w.quit)
+		w.wg.Wait()
+		w.saveBacklog()
+	})
+}
+
+// saveBacklog removes every request still queued, saves the operations among them and fails the rest
+func (w *DiscordAPISubmissionWorker) saveBacklog() {
+	w.mu.Lock()
+	var remaining []*apiRequest
+	for key, queue := range w.routes {
+		remaining = append(remaining, queue.requests...)
+		delete(w.routes, key)
+	}
+	w.queued = 0
+	w.queuedLane = [numPriorities]int{}
+	w.mu.Unlock()
+
+	if len(remaining) == 0 {
+		return
+	}
+
+	var backlog []models.PendingDiscordRequest
+	for _, request := range remaining {
+		if request.operation != nil {
+			entry := *request.operation
+			entry.Priority = int(request.priority)
+			entry.TraceID = request.Event.TraceID
+			entry.UserID = request.Event.UserID
+			entry.QueuedAt = request.queuedAt.Unix()
+			backlog = append(backlog, entry)
+		} else {
+			w.lost.Add(1)
+			logger.Warn(logger.LogData{
+				"action":   "discord_api_shutdown",
+				"message":  "Dropping unsent Discord API request that cannot be saved",
+				"route":    request.route.Key,
+				"priority": request.priority.String(),
+				"trace_id": request.Event.TraceID,
+				"user_id":  request.Event.UserID,
+			})
+		}
+		w.pending.Add(-1)
+		request.resolve(ErrWorkerStopped)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backlogSaveTimeout)
+	defer cancel()
+
+	if err := db.GetStore().SavePendingDiscordRequests(ctx, backlog); err != nil {
+		logger.Error(logger.LogData{
+			"action":  "discord_api_shutdown",
+			"message": "Failed to save unsent Discord API requests",
+			"count":   len(backlog),
+			"error":   err.Error(),
+		})
+		return
+	}
+	w.persisted.Add(int64(len(backlog)))
+
+	logger.Warn(logger.LogData{
+		"action":    "discord_api_shutdown",
+		"message":   "Discord API queue did not drain before shutdown",
+		"remaining": len(remaining),
+		"saved":     len(backlog),
+		"lost":      len(remaining) - len(backlog),
+	})
+}
+
+// replayBacklog queues the operations saved at the last shutdown, in their original order
+func (w *DiscordAPISubmissionWorker) replayBacklog() {
+	backlog, err := db.GetStore().TakePendingDiscordRequests(context.Background())
+	if err != nil {
+		logger.Error(logger.LogData{
+			"action":  "discord_api_startup",
+			"message": "Failed to load saved Discord API requests",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if len(backlog) == 0 {
+		return
+	}
+
+	for _, entry := range backlog {
+		e := eventWorker.Event{TraceID: entry.TraceID, UserID: entry.UserID}
+		submitOperation(e, entry.Operation, entry.Args, WithPriority(Priority(entry.Priority)))
+	}
+
+	logger.Info(logger.LogData{
+		"action":  "discord_api_startup",
+		"message": "Replaying Discord API requests saved at the last shutdown",
+		"count":   len(backlog),
+	})
+}
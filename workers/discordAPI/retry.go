@@ -0,0 +1,70 @@
+package discordAPIWorker
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// RetryPolicy controls how a failed request is retried. The zero value never retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry; it doubles on each retry after that
+	BaseDelay time.Duration
+	// MaxDelay caps the wait between attempts
+	MaxDelay time.Duration
+	// Statuses are the HTTP status codes that are worth retrying
+	Statuses []int
+}
+
+// DefaultRetryPolicy retries server errors and network failures a few times. Use it for requests
+// that are safe to repeat, such as role changes and channel edits.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   time.Second,
+	MaxDelay:    10 * time.Second,
+	Statuses: []int{
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// WithRetry sets the retry policy for a request
+func WithRetry(policy RetryPolicy) RequestOption {
+	return func(r *apiRequest) {
+		r.retry = policy
+	}
+}
+
+// shouldRetry reports whether another attempt is allowed after attempt number attempt failed with err
+func (p RetryPolicy) shouldRetry(err error, attempt int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) {
+		return restErr.Response != nil && slices.Contains(p.Statuses, restErr.Response.StatusCode)
+	}
+
+	// Connection resets and timeouts never reached Discord or lost the response on the way back
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// delay returns the wait before the retry that follows attempt number attempt
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	delay := p.BaseDelay << (attempt - 1)
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay <= 0) {
+		delay = p.MaxDelay
+	}
+	return delay
+}
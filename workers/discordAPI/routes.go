@@ -0,0 +1,122 @@
+package discordAPIWorker
+
+import (
+	"github.com/bwmarrin/discordgo"
+)
+
+// Priority selects the lane a request waits in. Lanes are served in order, so an interactive
+// request is picked ahead of any normal or bulk request that is ready to run.
+type Priority int
+
+const (
+	// PriorityInteractive is for replies a user is waiting on, such as slash command responses
+	PriorityInteractive Priority = iota
+	// PriorityNormal is for requests made while handling gateway events
+	PriorityNormal
+	// PriorityBulk is for maintenance traffic such as scheduled tasks and rebuild commands
+	PriorityBulk
+)
+
+// numPriorities is the number of lanes
+const numPriorities = int(PriorityBulk) + 1
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityNormal:
+		return "normal"
+	case PriorityBulk:
+		return "bulk"
+	}
+	return "unknown"
+}
+
+// Route describes what a request touches. Requests sharing a Key run one at a time in the order
+// they were submitted, while requests on different keys may run concurrently. Buckets are the
+// discordgo rate-limit buckets the request will hit; the worker holds a request back while any
+// of them is exhausted instead of letting it block a worker slot. Name labels the route in
+// metrics without the IDs in Key.
+type Route struct {
+	Name    string
+	Key     string
+	Buckets []string
+}
+
+// DefaultRoute is used by requests that do not name a route, which keeps them strictly ordered
+var DefaultRoute = Route{Name: "default", Key: "default"}
+
+// MessageRoute is for sending messages to a channel
+func MessageRoute(channelID string) Route {
+	endpoint := discordgo.EndpointChannelMessages(channelID)
+	return Route{Name: "channel_messages", Key: endpoint, Buckets: []string{endpoint}}
+}
+
+// MemberRolesRoute is for adding and removing member roles. Discord shares one bucket per guild
+// for role changes, so they are ordered across all members.
+func MemberRolesRoute(guildID string) Route {
+	endpoint := discordgo.EndpointGuildMemberRole(guildID, "", "")
+	return Route{Name: "member_roles", Key: endpoint, Buckets: []string{endpoint}}
+}
+
+// ThreadRoute is for messages and edits on one thread. They share a key so a message sent before
+// an archive is not posted afterwards, which would reopen the thread.
+func ThreadRoute(threadID string) Route {
+	return Route{
+		Name:    "thread",
+		Key:     "thread:" + threadID,
+		Buckets: []string{discordgo.EndpointChannelMessages(threadID), discordgo.EndpointChannel(threadID)},
+	}
+}
+
+// ForumRoute is for starting threads in a forum channel
+func ForumRoute(forumID string) Route {
+	endpoint := discordgo.EndpointChannelThreads(forumID)
+	return Route{Name: "forum_threads", Key: endpoint, Buckets: []string{endpoint}}
+}
+
+// DirectMessageRoute is for opening a DM channel with a user and messaging them
+func DirectMessageRoute(userID string) Route {
+	return Route{
+		Name:    "direct_message",
+		Key:     "dm:" + userID,
+		Buckets: []string{discordgo.EndpointUserChannels("")},
+	}
+}
+
+// InteractionRoute is for the response and follow-ups to one interaction. Interaction webhooks
+// are not limited by the bot's buckets, so only the ordering key is set.
+func InteractionRoute(interaction *discordgo.Interaction) Route {
+	return Route{Name: "interaction", Key: "interaction:" + interaction.ID}
+}
+
+// RequestOption configures a request passed to NewRequest
+type RequestOption func(*apiRequest)
+
+// WithRoute sets the route a request is ordered and rate limited on
+func WithRoute(route Route) RequestOption {
+	return func(r *apiRequest) {
+		r.route = route
+	}
+}
+
+// WithPriority sets the lane a request waits in
+func WithPriority(priority Priority) RequestOption {
+	return func(r *apiRequest) {
+		if priority >= 0 && int(priority) < numPriorities {
+			r.priority = priority
+		}
+	}
+}
+
+// After chains a request behind others: it waits until they have all finished, even if they are
+// on other routes, and is skipped with ErrDependencyFailed if any of them failed
+func After(deps ...Awaitable) RequestOption {
+	return func(r *apiRequest) {
+		for _, dep := range deps {
+			if dep != nil {
+				r.after = append(r.after, dep)
+			}
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package eventWorker
+
+import (
+	"astralHRBot/logger"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config sizes the worker pool
+type Config struct {
+	// Shards is the number of workers. Each user is assigned to one shard, so a user's events run
+	// in submission order while events for users on different shards run concurrently.
+	Shards int
+	// BufferSize is how many events may wait on each shard before Submit has to wait
+	BufferSize int
+	// IdleTimeout is how long a shard's worker waits for events before exiting; the next event
+	// for the shard starts it again
+	IdleTimeout time.Duration
+	// SubmitTimeout bounds how long Submit waits for space on a full shard. Zero waits until
+	// space frees up or the pool shuts down.
+	SubmitTimeout time.Duration
+}
+
+// DefaultConfig is used for any setting not given in the environment
+func DefaultConfig() Config {
+	return Config{
+		Shards:        32,
+		BufferSize:    256,
+		IdleTimeout:   10 * time.Second,
+		SubmitTimeout: 10 * time.Second,
+	}
+}
+
+// ConfigFromEnv reads EVENT_WORKER_SHARDS, EVENT_WORKER_BUFFER_SIZE, EVENT_WORKER_IDLE_TIMEOUT and
+// EVENT_WORKER_SUBMIT_TIMEOUT, keeping the default for any that is missing or invalid
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+
+	if value, exists := os.LookupEnv("EVENT_WORKER_SHARDS"); exists {
+		if shards, err := strconv.Atoi(value); err == nil && shards > 0 {
+			cfg.Shards = shards
+		} else {
+			warnInvalidSetting("EVENT_WORKER_SHARDS", value)
+		}
+	}
+	if value, exists := os.LookupEnv("EVENT_WORKER_BUFFER_SIZE"); exists {
+		if size, err := strconv.Atoi(value); err == nil && size > 0 {
+			cfg.BufferSize = size
+		} else {
+			warnInvalidSetting("EVENT_WORKER_BUFFER_SIZE", value)
+		}
+	}
+	if value, exists := os.LookupEnv("EVENT_WORKER_IDLE_TIMEOUT"); exists {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+			cfg.IdleTimeout = timeout
+		} else {
+			warnInvalidSetting("EVENT_WORKER_IDLE_TIMEOUT", value)
+		}
+	}
+	if value, exists := os.LookupEnv("EVENT_WORKER_SUBMIT_TIMEOUT"); exists {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout >= 0 {
+			cfg.SubmitTimeout = timeout
+		} else {
+			warnInvalidSetting("EVENT_WORKER_SUBMIT_TIMEOUT", value)
+		}
+	}
+
+	return cfg
+}
+
+func warnInvalidSetting(name, value string) {
+	logger.Warn(logger.LogData{
+		"action":  "worker_pool_config",
+		"message": "Ignoring invalid setting, using the default",
+		"setting": name,
+		"value":   value,
+	})
+}
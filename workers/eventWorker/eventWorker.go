@@ -3,47 +3,112 @@ package eventWorker
 import (
 	"astralHRBot/bot/identity"
 	"astralHRBot/logger"
+	"astralHRBot/metrics"
+	"context"
 	"errors"
+	"fmt"
+	"hash/fnv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrPoolNotInitialized is returned by Submit before NewWorkerPool has run
+var ErrPoolNotInitialized = errors.New("worker pool not initialized")
+
+// ErrShuttingDown is returned for events submitted after Shutdown has started
+var ErrShuttingDown = errors.New("worker pool is shutting down")
+
+// ErrSubmitTimeout is returned when an event could not be queued before the submit context ended
+var ErrSubmitTimeout = errors.New("timed out waiting for space in the worker pool")
+
 type Event struct {
+	GuildID string
 	UserID  string
 	Handler func(Event)
 	TraceID string
 	Payload []any
 }
 
+// shard is one worker and the events waiting for it. running and senders are guarded by the pool's mu.
+type shard struct {
+	id      int
+	events  chan Event
+	running bool
+	// senders counts submitters that have reserved the shard but not yet queued their event, so
+	// the worker does not exit while an event is on its way
+	senders int
+}
+
 type WorkerPool struct {
+	cfg          Config
 	mu           sync.Mutex
-	userChannels map[string]chan Event
-	idleTimeout  time.Duration
+	shards       []*shard
 	shuttingDown bool
+	quit         chan struct{} // closed when Shutdown starts, releases submitters waiting for space
+	stop         chan struct{} // closed once no submitter is left, tells the workers to drain and exit
+	submitting   sync.WaitGroup
 	wg           sync.WaitGroup
+	pending      atomic.Int64 // events submitted but not yet handled
+
+	handled   atomic.Int64
+	panics    atomic.Int64
+	timeouts  atomic.Int64
+	highWater atomic.Int64
+	latency   latencyStats
 }
 
 var wp *WorkerPool
 
-// NewWorkerPool initializes the singleton worker pool
+// NewWorkerPool initializes the singleton worker pool with settings from the environment
 func NewWorkerPool() *WorkerPool {
+	return NewWorkerPoolWithConfig(ConfigFromEnv())
+}
+
+// NewWorkerPoolWithConfig initializes the singleton worker pool. Shard workers start with the first
+// event for their shard.
+func NewWorkerPoolWithConfig(cfg Config) *WorkerPool {
 	wp = &WorkerPool{
-		userChannels: make(map[string]chan Event),
-		idleTimeout:  10 * time.Second,
+		cfg:    cfg,
+		shards: make([]*shard, cfg.Shards),
+		quit:   make(chan struct{}),
+		stop:   make(chan struct{}),
+	}
+	for i := range wp.shards {
+		wp.shards[i] = &shard{id: i, events: make(chan Event, cfg.BufferSize)}
 	}
 	logger.Info(logger.LogData{
-		"action":  "worker_pool_init",
-		"message": "Worker pool initialized",
+		"action":       "worker_pool_init",
+		"message":      "Worker pool initialized",
+		"shards":       cfg.Shards,
+		"buffer_size":  cfg.BufferSize,
+		"idle_timeout": cfg.IdleTimeout.String(),
 	})
 	return wp
 }
 
-// Submit adds an event to the appropriate user's channel
-func Submit(userID string, handler func(Event), payload ...any) error {
+// Submit queues an event from a guild on the user's shard, waiting up to the configured submit
+// timeout for space
+func Submit(guildID, userID string, handler func(Event), payload ...any) error {
+	if wp == nil {
+		return ErrPoolNotInitialized
+	}
+	ctx := context.Background()
+	if wp.cfg.SubmitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, wp.cfg.SubmitTimeout)
+		defer cancel()
+	}
+	return SubmitWithContext(ctx, guildID, userID, handler, payload...)
+}
+
+// SubmitWithContext queues an event on the user's shard. If the shard is full it waits for space
+// until ctx ends, then gives up with ErrSubmitTimeout.
+func SubmitWithContext(ctx context.Context, guildID, userID string, handler func(Event), payload ...any) error {
 	if wp == nil {
-		return errors.New("worker pool not initialized")
+		return ErrPoolNotInitialized
 	}
 
 	// Skip events from the bot itself
@@ -56,118 +121,215 @@ func Submit(userID string, handler func(Event), payload ...any) error {
 		return nil
 	}
 
-	wp.mu.Lock()
-	if wp.shuttingDown {
-		wp.mu.Unlock()
-		return errors.New("worker pool is shutting down")
-	}
-
 	event := Event{
+		GuildID: guildID,
 		UserID:  userID,
 		TraceID: uuid.New().String(),
 		Payload: payload,
 		Handler: handler,
 	}
+	s := wp.shardFor(userID)
 
-	ch, exists := wp.userChannels[userID]
-	if !exists {
-		ch = make(chan Event, 100)
-		wp.userChannels[userID] = ch
+	wp.mu.Lock()
+	if wp.shuttingDown {
+		wp.mu.Unlock()
+		return ErrShuttingDown
+	}
+	if !s.running {
+		s.running = true
 		wp.wg.Add(1)
-		go wp.startUserRoutine(userID, ch)
+		go wp.runShard(s)
 		logger.Debug(logger.LogData{
-			"action":  "new_user_routine",
-			"message": "Started new user routine",
-			"user_id": userID,
+			"action":  "shard_started",
+			"message": "Started shard worker",
+			"shard":   s.id,
 		})
 	}
+	s.senders++
+	wp.submitting.Add(1)
+	wp.pending.Add(1)
 	wp.mu.Unlock()
 
-	ch <- event
-	return nil
+	err := wp.send(ctx, s, event)
+
+	wp.mu.Lock()
+	s.senders--
+	wp.mu.Unlock()
+	wp.submitting.Done()
+	return err
+}
+
+// send queues the event on its shard, waiting for space if the shard is full
+func (wp *WorkerPool) send(ctx context.Context, s *shard, event Event) error {
+	select {
+	case s.events <- event:
+		wp.recordDepth(s)
+		return nil
+	default:
+	}
+
+	select {
+	case s.events <- event:
+		wp.recordDepth(s)
+		return nil
+	case <-ctx.Done():
+		wp.pending.Add(-1)
+		wp.timeouts.Add(1)
+		logger.Warn(logger.LogData{
+			"trace_id": event.TraceID,
+			"action":   "submit_timeout",
+			"message":  "Dropped event because its shard stayed full",
+			"guild_id": event.GuildID,
+			"user_id":  event.UserID,
+			"shard":    s.id,
+			"error":    ctx.Err().Error(),
+		})
+		return fmt.Errorf("%w: %w", ErrSubmitTimeout, ctx.Err())
+	case <-wp.quit:
+		wp.pending.Add(-1)
+		return ErrShuttingDown
+	}
+}
+
+func (wp *WorkerPool) recordDepth(s *shard) {
+	depth := int64(len(s.events))
+	for {
+		highWater := wp.highWater.Load()
+		if depth <= highWater || wp.highWater.CompareAndSwap(highWater, depth) {
+			return
+		}
+	}
 }
 
-// startUserRoutine manages the processing of events for a user
-func (wp *WorkerPool) startUserRoutine(userID string, ch chan Event) {
+// shardFor assigns a user to a shard so all of the user's events run on the same worker
+func (wp *WorkerPool) shardFor(userID string) *shard {
+	hash := fnv.New32a()
+	hash.Write([]byte(userID))
+	return wp.shards[hash.Sum32()%uint32(len(wp.shards))]
+}
+
+// Pending returns the number of submitted events that have not finished running
+func Pending() int64 {
+	if wp == nil {
+		return 0
+	}
+	return wp.pending.Load()
+}
+
+// runShard handles a shard's events in order until it is idle or the pool shuts down
+func (wp *WorkerPool) runShard(s *shard) {
 	defer wp.wg.Done()
-	idleTimer := time.NewTimer(wp.idleTimeout)
+	idleTimer := time.NewTimer(wp.cfg.IdleTimeout)
 	defer idleTimer.Stop()
 
 	for {
 		select {
-		case event, ok := <-ch:
-			if !ok {
-				logger.Debug(logger.LogData{
-					"action":  "routine_shutdown",
-					"message": "User routine shutting down",
-					"user_id": userID,
-				})
-				return // channel closed externally
-			}
-			safeHandle(event)
-
-			// Reset idle timer on activity
-			if !idleTimer.Stop() {
-				<-idleTimer.C
-			}
-			idleTimer.Reset(wp.idleTimeout)
+		case event := <-s.events:
+			wp.handle(event)
+			idleTimer.Reset(wp.cfg.IdleTimeout)
 
 		case <-idleTimer.C:
 			wp.mu.Lock()
-			if len(ch) == 0 {
-				close(ch)
-				delete(wp.userChannels, userID)
+			if len(s.events) == 0 && s.senders == 0 {
+				s.running = false
+				wp.mu.Unlock()
 				logger.Debug(logger.LogData{
 					"action":  "cleanup",
-					"message": "User Routine closed due to inactivity",
-					"user_id": userID,
+					"message": "Shard worker closed due to inactivity",
+					"shard":   s.id,
 				})
-				wp.mu.Unlock()
 				return
 			}
-			idleTimer.Reset(wp.idleTimeout)
 			wp.mu.Unlock()
+			idleTimer.Reset(wp.cfg.IdleTimeout)
+
+		case <-wp.stop:
+			for {
+				select {
+				case event := <-s.events:
+					wp.handle(event)
+				default:
+					wp.mu.Lock()
+					s.running = false
+					wp.mu.Unlock()
+					logger.Debug(logger.LogData{
+						"action":  "routine_shutdown",
+						"message": "Shard worker shutting down",
+						"shard":   s.id,
+					})
+					return
+				}
+			}
 		}
 	}
 }
 
-// safeHandle wraps event handling to recover from potential panics
-func safeHandle(e Event) {
+// handle runs one event and records its latency
+func (wp *WorkerPool) handle(e Event) {
+	start := time.Now()
+	panicked := !safeHandle(e)
+	if panicked {
+		wp.panics.Add(1)
+	}
+	duration := time.Since(start)
+	wp.latency.observe(duration)
+	metrics.EventHandled(duration, panicked)
+	wp.handled.Add(1)
+	wp.pending.Add(-1)
+}
+
+// safeHandle wraps event handling to recover from potential panics. It reports false if the handler panicked.
+func safeHandle(e Event) (ok bool) {
 	defer func() {
 		if r := recover(); r != nil {
+			ok = false
 			logger.Error(logger.LogData{
 				"trace_id": e.TraceID,
 				"action":   "handler_panic",
 				"message":  "Recovered from panic in handler",
+				"guild_id": e.GuildID,
 				"user_id":  e.UserID,
 				"error":    r,
 			})
 		}
 	}()
 	e.Handler(e)
+	return true
 }
 
-// Shutdown gracefully shuts down the worker pool
+// Shutdown stops accepting events, lets the workers finish everything already queued and waits for them
 func Shutdown() {
 	if wp == nil {
 		return
 	}
 
 	wp.mu.Lock()
+	if wp.shuttingDown {
+		wp.mu.Unlock()
+		return
+	}
 	wp.shuttingDown = true
-	userCount := len(wp.userChannels)
-	for _, ch := range wp.userChannels {
-		close(ch)
+	activeShards := 0
+	for _, s := range wp.shards {
+		if s.running {
+			activeShards++
+		}
 	}
 	wp.mu.Unlock()
 
 	logger.Info(logger.LogData{
-		"action":     "shutdown_start",
-		"message":    "Starting worker pool shutdown",
-		"user_count": userCount,
+		"action":        "shutdown_start",
+		"message":       "Starting worker pool shutdown",
+		"active_shards": activeShards,
+		"pending":       wp.pending.Load(),
 	})
 
-	// Wait for all routines to finish
+	// Submitters waiting for space give up; the rest finish queueing before the workers drain
+	close(wp.quit)
+	wp.submitting.Wait()
+	close(wp.stop)
+
+	// Wait for all workers to finish
 	wp.wg.Wait()
 	logger.Info(logger.LogData{
 		"action":  "shutdown_complete",
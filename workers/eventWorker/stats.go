@@ -0,0 +1,86 @@
+package eventWorker
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// latencyStats accumulates handler durations without locking
+type latencyStats struct {
+	count atomic.Int64
+	total atomic.Int64 // nanoseconds
+	max   atomic.Int64 // nanoseconds
+}
+
+func (l *latencyStats) observe(d time.Duration) {
+	l.count.Add(1)
+	l.total.Add(int64(d))
+	for {
+		longest := l.max.Load()
+		if int64(d) <= longest || l.max.CompareAndSwap(longest, int64(d)) {
+			return
+		}
+	}
+}
+
+// LatencyStats summarizes how long handlers have taken since startup
+type LatencyStats struct {
+	Count int64         `json:"count"`
+	Total time.Duration `json:"total_ns"`
+	Max   time.Duration `json:"max_ns"`
+}
+
+// Mean returns the average handler duration, or zero before any event has been handled
+func (l LatencyStats) Mean() time.Duration {
+	if l.Count == 0 {
+		return 0
+	}
+	return l.Total / time.Duration(l.Count)
+}
+
+// PoolStats is a snapshot of the worker pool
+type PoolStats struct {
+	Shards       int `json:"shards"`
+	ActiveShards int `json:"active_shards"` // shards with a running worker
+	BufferSize   int `json:"buffer_size"`   // capacity of each shard's queue
+	Queued       int `json:"queued"`        // events waiting on all shards
+	// HighWater is the deepest any single shard's queue has been since startup
+	HighWater int `json:"high_water"`
+	// Handled, Panics and Timeouts count events since startup that ran, whose handler panicked and
+	// that were given up on because their shard stayed full
+	Handled        int64        `json:"handled"`
+	Panics         int64        `json:"panics"`
+	Timeouts       int64        `json:"timeouts"`
+	HandlerLatency LatencyStats `json:"handler_latency"`
+}
+
+// Stats returns the pool's current queue depth, counters and handler latency
+func Stats() PoolStats {
+	if wp == nil {
+		return PoolStats{}
+	}
+
+	stats := PoolStats{
+		Shards:     len(wp.shards),
+		BufferSize: wp.cfg.BufferSize,
+	}
+	wp.mu.Lock()
+	for _, s := range wp.shards {
+		if s.running {
+			stats.ActiveShards++
+		}
+		stats.Queued += len(s.events)
+	}
+	wp.mu.Unlock()
+
+	stats.HighWater = int(wp.highWater.Load())
+	stats.Handled = wp.handled.Load()
+	stats.Panics = wp.panics.Load()
+	stats.Timeouts = wp.timeouts.Load()
+	stats.HandlerLatency = LatencyStats{
+		Count: wp.latency.count.Load(),
+		Total: time.Duration(wp.latency.total.Load()),
+		Max:   time.Duration(wp.latency.max.Load()),
+	}
+	return stats
+}
@@ -0,0 +1,100 @@
+package logChannel
+
+import (
+	"astralHRBot/channels"
+	"astralHRBot/config"
+	"astralHRBot/logger"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config controls which log records are forwarded to the staff channel and how often
+type Config struct {
+	// ChannelID is the channel alerts are posted to; forwarding is disabled when it is empty
+	ChannelID string
+	// Level is the lowest level forwarded
+	Level slog.Level
+	// Actions are forwarded whatever their level
+	Actions map[string]bool
+	// PerMinute caps how many alerts are posted in any minute
+	PerMinute int
+	// DedupeWindow is how long an alert with the same level, action, message and error is held
+	// back after it was last posted
+	DedupeWindow time.Duration
+	// BufferSize is how many alerts may wait to be posted before new ones are dropped
+	BufferSize int
+}
+
+// DefaultConfig is used for any setting not given in the environment
+func DefaultConfig() Config {
+	return Config{
+		Level: slog.LevelWarn,
+		Actions: map[string]bool{
+			"discord_api_error": true,
+			"handler_panic":     true,
+		},
+		PerMinute:    10,
+		DedupeWindow: 10 * time.Minute,
+		BufferSize:   100,
+	}
+}
+
+// ConfigFromEnv reads the LOG_CHANNEL_ID setting from the config store, then LOG_CHANNEL_LEVEL,
+// LOG_CHANNEL_ACTIONS, LOG_CHANNEL_PER_MINUTE, LOG_CHANNEL_DEDUPE_WINDOW and LOG_CHANNEL_BUFFER_SIZE
+// from the environment, keeping the default for any that is missing or invalid. LOG_CHANNEL_ACTIONS
+// is a comma-separated list that replaces the default actions.
+func ConfigFromEnv() Config {
+	cfg := DefaultConfig()
+	cfg.ChannelID = config.Get("", channels.LogChannel)
+
+	if value, exists := os.LookupEnv("LOG_CHANNEL_LEVEL"); exists {
+		if level, err := logger.ParseLevel(value); err == nil {
+			cfg.Level = level
+		} else {
+			warnInvalidSetting("LOG_CHANNEL_LEVEL", value)
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_CHANNEL_ACTIONS"); exists {
+		cfg.Actions = make(map[string]bool)
+		for _, action := range strings.Split(value, ",") {
+			if action = strings.TrimSpace(action); action != "" {
+				cfg.Actions[action] = true
+			}
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_CHANNEL_PER_MINUTE"); exists {
+		if perMinute, err := strconv.Atoi(value); err == nil && perMinute > 0 {
+			cfg.PerMinute = perMinute
+		} else {
+			warnInvalidSetting("LOG_CHANNEL_PER_MINUTE", value)
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_CHANNEL_DEDUPE_WINDOW"); exists {
+		if window, err := time.ParseDuration(value); err == nil && window >= 0 {
+			cfg.DedupeWindow = window
+		} else {
+			warnInvalidSetting("LOG_CHANNEL_DEDUPE_WINDOW", value)
+		}
+	}
+	if value, exists := os.LookupEnv("LOG_CHANNEL_BUFFER_SIZE"); exists {
+		if size, err := strconv.Atoi(value); err == nil && size > 0 {
+			cfg.BufferSize = size
+		} else {
+			warnInvalidSetting("LOG_CHANNEL_BUFFER_SIZE", value)
+		}
+	}
+
+	return cfg
+}
+
+func warnInvalidSetting(name, value string) {
+	logger.Warn(logger.LogData{
+		"action":  "log_channel_config",
+		"message": "Ignoring invalid setting, using the default",
+		"setting": name,
+		"value":   value,
+	})
+}
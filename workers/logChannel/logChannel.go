@@ -0,0 +1,329 @@
+package logChannel
+
+import (
+	"astralHRBot/discord"
+	"astralHRBot/logger"
+	discordAPIWorker "astralHRBot/workers/discordAPI"
+	"astralHRBot/workers/eventWorker"
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Discord's limits on embed sizes
+const (
+	maxTitleLength = 256
+	maxFieldLength = 1024
+	maxFields      = 25
+)
+
+// alert is a log record picked out for the staff channel
+type alert struct {
+	level   slog.Level
+	time    time.Time
+	message string
+	action  string
+	pkg     string
+	traceID string
+	err     string
+	fields  []slog.Attr
+}
+
+// key identifies alerts that are duplicates of each other
+func (a alert) key() string {
+	return fmt.Sprintf("%d|%s|%s|%s", a.level, a.action, a.message, a.err)
+}
+
+// recentAlert tracks an alert that was posted within the dedupe window and how many copies of it
+// have been held back since
+type recentAlert struct {
+	posted  time.Time
+	repeats int
+}
+
+// sink is the logger sink. It only picks out and queues alerts, so a slow or rate limited
+// channel never holds up the logger; the worker posts them.
+type sink struct {
+	cfg     Config
+	route   string // the route alerts are posted on, whose own failures are not forwarded
+	alerts  chan alert
+	stopped atomic.Bool
+	dropped atomic.Int64
+}
+
+type worker struct {
+	sink        *sink
+	recent      map[string]*recentAlert
+	posted      []time.Time // when alerts were posted in the last minute
+	rateLimited int         // alerts skipped by the rate limit since the last one posted
+	quit        chan struct{}
+	done        chan struct{}
+	stopOnce    sync.Once
+}
+
+var forwarder *worker
+
+// Start forwards warnings and errors to the staff channel with settings from the environment
+func Start() {
+	StartWithConfig(ConfigFromEnv())
+}
+
+// StartWithConfig adds the log channel sink to the logger and starts posting alerts. It does
+// nothing if no channel is configured.
+func StartWithConfig(cfg Config) {
+	if cfg.ChannelID == "" {
+		logger.Info(logger.LogData{
+			"action":  "log_channel_startup",
+			"message": "No log channel configured, warnings and errors are not forwarded to Discord",
+		})
+		return
+	}
+
+	s := &sink{
+		cfg:    cfg,
+		route:  discordAPIWorker.MessageRoute(cfg.ChannelID).Key,
+		alerts: make(chan alert, cfg.BufferSize),
+	}
+	forwarder = &worker{
+		sink:   s,
+		recent: make(map[string]*recentAlert),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	logger.AddSink(s)
+	go forwarder.run()
+
+	logger.Info(logger.LogData{
+		"action":     "log_channel_startup",
+		"message":    "Forwarding warnings and errors to the log channel",
+		"channel_id": cfg.ChannelID,
+		"level":      logger.LevelName(cfg.Level),
+	})
+}
+
+// Stop stops forwarding and hands any alerts still waiting to the Discord API worker
+func Stop(ctx context.Context) error {
+	w := forwarder
+	if w == nil {
+		return nil
+	}
+
+	w.stopOnce.Do(func() {
+		w.sink.stopped.Store(true)
+		close(w.quit)
+	})
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Dropped returns the number of alerts discarded since startup because the buffer was full
+func Dropped() int64 {
+	if forwarder == nil {
+		return 0
+	}
+	return forwarder.sink.dropped.Load()
+}
+
+func (s *sink) Enabled(_ context.Context, level slog.Level) bool {
+	// Listed actions are forwarded at any level, so records below the threshold are only ruled
+	// out once their action is known
+	return level >= s.cfg.Level || len(s.cfg.Actions) > 0
+}
+
+func (s *sink) Handle(_ context.Context, record slog.Record) error {
+	if s.stopped.Load() {
+		return nil
+	}
+
+	a := alert{
+		level:   record.Level,
+		time:    record.Time,
+		message: record.Message,
+	}
+	var route string
+	record.Attrs(func(attr slog.Attr) bool {
+		switch attr.Key {
+		case "action":
+			a.action = attr.Value.String()
+		case "package":
+			a.pkg = attr.Value.String()
+		case "trace_id":
+			a.traceID = attr.Value.String()
+		case "error":
+			a.err = attr.Value.String()
+		case "route":
+			route = attr.Value.String()
+			a.fields = append(a.fields, attr)
+		default:
+			a.fields = append(a.fields, attr)
+		}
+		return true
+	})
+
+	if record.Level < s.cfg.Level && !s.cfg.Actions[a.action] {
+		return nil
+	}
+	// Failing to post an alert would otherwise raise another alert about the same channel
+	if route == s.route {
+		return nil
+	}
+
+	select {
+	case s.alerts <- a:
+	default:
+		s.dropped.Add(1)
+	}
+	return nil
+}
+
+// WithAttrs and WithGroup are never used by the logger, which passes every attribute on the record
+func (s *sink) WithAttrs([]slog.Attr) slog.Handler { return s }
+
+func (s *sink) WithGroup(string) slog.Handler { return s }
+
+func (w *worker) run() {
+	defer close(w.done)
+
+	prune := time.NewTicker(time.Minute)
+	defer prune.Stop()
+
+	for {
+		select {
+		case a := <-w.sink.alerts:
+			w.post(a)
+		case <-prune.C:
+			w.prune(time.Now())
+		case <-w.quit:
+			for {
+				select {
+				case a := <-w.sink.alerts:
+					w.post(a)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// post sends an alert unless a copy of it was posted within the dedupe window or the rate limit
+// has been reached. Held back copies are counted in the next alert of the same kind.
+func (w *worker) post(a alert) {
+	now := time.Now()
+	key := a.key()
+	recent, seen := w.recent[key]
+	if seen && now.Sub(recent.posted) < w.sink.cfg.DedupeWindow {
+		recent.repeats++
+		return
+	}
+
+	w.trimPosted(now)
+	if len(w.posted) >= w.sink.cfg.PerMinute {
+		w.rateLimited++
+		return
+	}
+
+	repeats := 0
+	if seen {
+		repeats = recent.repeats
+	}
+	embed := buildEmbed(a, repeats, w.rateLimited)
+	w.recent[key] = &recentAlert{posted: now}
+	w.posted = append(w.posted, now)
+	w.rateLimited = 0
+
+	channelID := w.sink.cfg.ChannelID
+	discordAPIWorker.NewRequest(eventWorker.Event{TraceID: a.traceID}, func() error {
+		_, err := discord.GetClient().ChannelMessageSendEmbed(channelID, embed)
+		return err
+	}, discordAPIWorker.WithRoute(discordAPIWorker.MessageRoute(channelID)), discordAPIWorker.WithPriority(discordAPIWorker.PriorityBulk))
+}
+
+// trimPosted forgets posts older than a minute
+func (w *worker) trimPosted(now time.Time) {
+	kept := w.posted[:0]
+	for _, posted := range w.posted {
+		if now.Sub(posted) < time.Minute {
+			kept = append(kept, posted)
+		}
+	}
+	w.posted = kept
+}
+
+// prune forgets alerts whose dedupe window has passed
+func (w *worker) prune(now time.Time) {
+	for key, recent := range w.recent {
+		if now.Sub(recent.posted) >= w.sink.cfg.DedupeWindow {
+			delete(w.recent, key)
+		}
+	}
+}
+
+func buildEmbed(a alert, repeats, rateLimited int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     truncate(fmt.Sprintf("%s: %s", logger.LevelName(a.level), a.message), maxTitleLength),
+		Color:     levelColor(a.level),
+		Timestamp: a.time.Format(time.RFC3339),
+	}
+
+	addField := func(name, value string, inline bool) {
+		if value == "" || len(embed.Fields) >= maxFields {
+			return
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   name,
+			Value:  truncate(value, maxFieldLength),
+			Inline: inline,
+		})
+	}
+	addField("Trace ID", a.traceID, true)
+	addField("Action", a.action, true)
+	addField("Package", a.pkg, true)
+	if a.err != "" {
+		addField("Error", "```"+truncate(a.err, maxFieldLength-6)+"```", false)
+	}
+	for _, attr := range a.fields {
+		addField(attr.Key, attr.Value.String(), true)
+	}
+
+	var notes []string
+	if repeats > 0 {
+		notes = append(notes, fmt.Sprintf("Repeated %d times since last posted.", repeats))
+	}
+	if rateLimited > 0 {
+		notes = append(notes, fmt.Sprintf("%d other alerts skipped by the rate limit.", rateLimited))
+	}
+	if len(notes) > 0 {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: strings.Join(notes, " ")}
+	}
+	return embed
+}
+
+func levelColor(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 0xE74C3C
+	case level >= slog.LevelWarn:
+		return 0xF39C12
+	}
+	return 0x95A5A6
+}
+
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
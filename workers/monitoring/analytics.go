@@ -2,8 +2,8 @@ package monitoring
 
 import (
 	"astralHRBot/db"
+	"astralHRBot/discord"
 	"astralHRBot/globals"
-	"astralHRBot/helper"
 	"astralHRBot/logger"
 	"astralHRBot/models"
 	"context"
@@ -27,8 +27,8 @@ type AnalyticsResult struct {
 
 // rebuildAnalyticsForWindow is the core implementation used by public wrappers.
 // It computes analytics in the provided time window and writes results for the given scenarios.
-func rebuildAnalyticsForWindow(userID string, scenarios []models.MonitoringScenario, startTime, endTime time.Time, s *discordgo.Session, traceID string) (*AnalyticsResult, error) {
-	ctx := context.Background()
+func rebuildAnalyticsForWindow(guildID, userID string, scenarios []models.MonitoringScenario, startTime, endTime time.Time, s discord.DiscordClient, traceID string) (*AnalyticsResult, error) {
+	ctx := db.WithGuild(context.Background(), guildID)
 	var err error
 
 	logger.Debug(logger.LogData{
@@ -80,25 +80,16 @@ func rebuildAnalyticsForWindow(userID string, scenarios []models.MonitoringScena
 	topChannelID := ""
 	channelMessageCounts := make(map[string]int64)
 
-	var guildID string
 	if needMessages || needVoice || needInvites {
-		// Get guild ID using the helper function
-		var err error
-		guildID, err = helper.GetGuildIDFromSession(s)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get guild ID: %w", err)
-		}
-
 		// Get guild from state to access channels
-		guild, err := s.State.Guild(guildID)
+		guild, err := s.StateGuild(guildID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get guild: %w", err)
 		}
-		guildID = guild.ID
 
 		if needMessages {
 			// Build allow-list from scenarios; empty map means unrestricted
-			allowedChannelIDs := getAllowedChannelIDsForScenarios(scenarios)
+			allowedChannelIDs := getAllowedChannelIDsForScenarios(guildID, scenarios)
 
 			// Scan each channel for user messages
 			for _, channel := range guild.Channels {
@@ -188,7 +179,7 @@ func rebuildAnalyticsForWindow(userID string, scenarios []models.MonitoringScena
 
 	// Update analytics in Redis for each scenario
 	for _, scenario := range scenarios {
-		err = db.UpdateUserAnalytics(ctx, userID, string(scenario), messages, voiceJoins, invites, topChannelID)
+		err = db.GetStore().UpdateUserAnalytics(ctx, userID, string(scenario), messages, voiceJoins, invites, topChannelID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"trace_id": traceID,
@@ -215,7 +206,7 @@ func rebuildAnalyticsForWindow(userID string, scenarios []models.MonitoringScena
 
 // RebuildUserAnalytics rebuilds analytics data for a specific user using their monitoring data
 // This preserves existing behavior for callers that have monitoring state available.
-func RebuildUserAnalytics(userID string, monitoringData *models.UserMonitoring, s *discordgo.Session, traceID string) (*AnalyticsResult, error) {
+func RebuildUserAnalytics(guildID, userID string, monitoringData *models.UserMonitoring, s discord.DiscordClient, traceID string) (*AnalyticsResult, error) {
 	scenarios := monitoringData.GetScenarios()
 	if len(scenarios) == 0 {
 		return nil, fmt.Errorf("no scenarios available for user %s", userID)
@@ -243,9 +234,9 @@ func RebuildUserAnalytics(userID string, monitoringData *models.UserMonitoring,
 			// Determine default window based on scenario
 			switch scenario {
 			case models.MonitoringScenarioNewRecruit:
-				endTime = startTime.Add(time.Duration(globals.GetNewRecruitTrackingDays()) * 24 * time.Hour)
+				endTime = startTime.Add(time.Duration(globals.GetNewRecruitTrackingDays(guildID)) * 24 * time.Hour)
 			case models.MonitoringScenarioRecruitmentProcess:
-				endTime = startTime.Add(time.Duration(globals.GetRecruitmentCleanupDelay()) * 24 * time.Hour)
+				endTime = startTime.Add(time.Duration(globals.GetRecruitmentCleanupDelay(guildID)) * 24 * time.Hour)
 			default:
 				// Fallback to 7 days if unknown scenario
 				endTime = startTime.Add(7 * 24 * time.Hour)
@@ -253,7 +244,7 @@ func RebuildUserAnalytics(userID string, monitoringData *models.UserMonitoring,
 		}
 
 		// Compute and persist analytics for this scenario only
-		res, err := rebuildAnalyticsForWindow(userID, []models.MonitoringScenario{scenario}, startTime, endTime, s, traceID)
+		res, err := rebuildAnalyticsForWindow(guildID, userID, []models.MonitoringScenario{scenario}, startTime, endTime, s, traceID)
 		if err != nil {
 			return nil, err
 		}
@@ -268,12 +259,12 @@ func RebuildUserAnalytics(userID string, monitoringData *models.UserMonitoring,
 
 // RebuildUserAnalyticsForScenario rebuilds analytics for an explicit window and single scenario
 // This is useful when reconstructing scenarios from forum threads where monitoring state may be missing.
-func RebuildUserAnalyticsForScenario(userID string, scenario models.MonitoringScenario, startTime, endTime time.Time, s *discordgo.Session, traceID string) (*AnalyticsResult, error) {
-	return rebuildAnalyticsForWindow(userID, []models.MonitoringScenario{scenario}, startTime, endTime, s, traceID)
+func RebuildUserAnalyticsForScenario(guildID, userID string, scenario models.MonitoringScenario, startTime, endTime time.Time, s discord.DiscordClient, traceID string) (*AnalyticsResult, error) {
+	return rebuildAnalyticsForWindow(guildID, userID, []models.MonitoringScenario{scenario}, startTime, endTime, s, traceID)
 }
 
 // getChannelMessagesForUser gets messages from a channel for a specific user within a time period
-func getChannelMessagesForUser(s *discordgo.Session, channelID, userID string, startTime, endTime time.Time) ([]*discordgo.Message, error) {
+func getChannelMessagesForUser(s discord.DiscordClient, channelID, userID string, startTime, endTime time.Time) ([]*discordgo.Message, error) {
 	var allMessages []*discordgo.Message
 	before := ""
 
@@ -323,7 +314,7 @@ func getChannelMessagesForUser(s *discordgo.Session, channelID, userID string, s
 }
 
 // getVoiceJoinsFromAuditLog gets voice join events from audit log
-func getVoiceJoinsFromAuditLog(s *discordgo.Session, guildID, userID string, startTime, endTime time.Time, traceID string) (int64, error) {
+func getVoiceJoinsFromAuditLog(s discord.DiscordClient, guildID, userID string, startTime, endTime time.Time, traceID string) (int64, error) {
 	var totalJoins int64
 
 	// Get audit logs in batches
@@ -379,7 +370,7 @@ func getVoiceJoinsFromAuditLog(s *discordgo.Session, guildID, userID string, sta
 }
 
 // getInvitesFromAuditLog gets invite creation events from audit log
-func getInvitesFromAuditLog(s *discordgo.Session, guildID, userID string, startTime, endTime time.Time, traceID string) (int64, error) {
+func getInvitesFromAuditLog(s discord.DiscordClient, guildID, userID string, startTime, endTime time.Time, traceID string) (int64, error) {
 	var totalInvites int64
 
 	// Get audit logs in batches
@@ -5,14 +5,14 @@ import (
 	"astralHRBot/models"
 )
 
-// getAllowedChannelIDsForScenarios returns a set of channel IDs allowed across any of the provided scenarios.
+// getAllowedChannelIDsForScenarios returns a set of a guild's channel IDs allowed across any of the provided scenarios.
 // If no scenarios specify channel filters, the returned set will be empty, indicating no restriction.
-func getAllowedChannelIDsForScenarios(scenarios []models.MonitoringScenario) map[string]struct{} {
+func getAllowedChannelIDsForScenarios(guildID string, scenarios []models.MonitoringScenario) map[string]struct{} {
 	allowed := map[string]struct{}{}
 	for _, sc := range scenarios {
 		if envVars, ok := models.ScenarioChannelEnvFilter[sc]; ok {
 			for _, envVar := range envVars {
-				if id := channels.GetChannelID(envVar); id != "" {
+				if id := channels.GetChannelID(guildID, envVar); id != "" {
 					allowed[id] = struct{}{}
 				}
 			}
@@ -22,11 +22,11 @@ func getAllowedChannelIDsForScenarios(scenarios []models.MonitoringScenario) map
 }
 
 // isChannelAllowedForScenario returns true if the scenario either has no channel restrictions
-// or if the provided channelID is included in its allow-list resolved from environment variables.
-func isChannelAllowedForScenario(scenario models.MonitoringScenario, channelID string) bool {
+// or if the provided channelID is included in its allow-list resolved from the guild's settings.
+func isChannelAllowedForScenario(guildID string, scenario models.MonitoringScenario, channelID string) bool {
 	if envVars, ok := models.ScenarioChannelEnvFilter[scenario]; ok && len(envVars) > 0 {
 		for _, envVar := range envVars {
-			if id := channels.GetChannelID(envVar); id != "" && id == channelID {
+			if id := channels.GetChannelID(guildID, envVar); id != "" && id == channelID {
 				return true
 			}
 		}
@@ -1,6 +1,7 @@
 package monitoring
 
 import (
+	"astralHRBot/clock"
 	"astralHRBot/db"
 	"astralHRBot/globals"
 	"astralHRBot/logger"
@@ -10,20 +11,20 @@ import (
 	"time"
 )
 
-// EnsureScenarioWindow attaches a scenario to a user's monitoring record and
+// EnsureScenarioWindow attaches a scenario to a user's monitoring record in the guild in ctx and
 // persists the provided start and end times.
 func EnsureScenarioWindow(ctx context.Context, userID string, scenario models.MonitoringScenario, start time.Time, end time.Time) error {
 	// Add the scenario (creates monitoring record if needed via AddScenario)
-	AddScenario(userID, scenario)
+	AddScenario(db.GuildFromContext(ctx), userID, scenario)
 
-	md, err := db.GetUserMonitoring(ctx, userID)
+	md, err := db.GetStore().GetUserMonitoring(ctx, userID)
 	if err != nil || md == nil {
 		return err
 	}
 
 	md.SetStartTime(start)
 	md.SetExpiry(end)
-	if err := db.SaveUserMonitoring(ctx, md); err != nil {
+	if err := db.GetStore().SaveUserMonitoring(ctx, md); err != nil {
 		logger.Error(logger.LogData{
 			"action":   "ensure_scenario_window",
 			"message":  "Failed to persist monitoring window",
@@ -41,10 +42,11 @@ func EnsureScenarioWindow(ctx context.Context, userID string, scenario models.Mo
 // when monitoring data is missing or has no scenarios. It returns the updated
 // monitoring data and the list of scenarios that were added.
 func BackfillMonitoringFromTasks(ctx context.Context, userID string, monitoringData *models.UserMonitoring, tasks []models.Task) (*models.UserMonitoring, []models.MonitoringScenario, error) {
+	guildID := db.GuildFromContext(ctx)
 	scenariosAdded := []models.MonitoringScenario{}
 
 	if monitoringData == nil {
-		monitoringData = models.NewUserMonitoring(userID)
+		monitoringData = models.NewUserMonitoring(guildID, userID)
 	}
 
 	// Map task types to scenarios
@@ -74,34 +76,37 @@ func BackfillMonitoringFromTasks(ctx context.Context, userID string, monitoringD
 			monitoringData.ExpiresAt = earliest
 			defaultDays := 7
 			if monitoringData.HasScenario(models.MonitoringScenarioNewRecruit) {
-				defaultDays = globals.GetNewRecruitTrackingDays()
+				defaultDays = globals.GetNewRecruitTrackingDays(guildID)
 			} else if monitoringData.HasScenario(models.MonitoringScenarioRecruitmentProcess) {
-				defaultDays = globals.GetRecruitmentCleanupDelay()
+				defaultDays = globals.GetRecruitmentCleanupDelay(guildID)
 			}
 			monitoringData.StartedAt = time.Unix(earliest, 0).Add(-time.Duration(defaultDays) * 24 * time.Hour).Unix()
 		}
 	}
 
-	if err := db.SaveUserMonitoring(ctx, monitoringData); err != nil {
+	if err := db.GetStore().SaveUserMonitoring(ctx, monitoringData); err != nil {
 		return monitoringData, scenariosAdded, err
 	}
 
 	return monitoringData, scenariosAdded, nil
 }
 
-// CreateRecruitmentReminderAtMidpoint creates a recruitment reminder task at the midpoint
-// of the recruitment process duration, but only if the midpoint is in the future.
+// CreateRecruitmentReminderAtMidpoint creates a recruitment reminder task in the guild in ctx at
+// the midpoint of the recruitment process duration, but only if the midpoint is in the future.
 func CreateRecruitmentReminderAtMidpoint(ctx context.Context, userID string, startTime time.Time, scenario models.MonitoringScenario) error {
+	guildID := db.GuildFromContext(ctx)
+
 	// Calculate midpoint: start + (delay * 12 hours)
-	midpoint := startTime.Add(time.Duration(globals.GetRecruitmentCleanupDelay()) * 12 * time.Hour).Unix()
+	midpoint := startTime.Add(time.Duration(globals.GetRecruitmentCleanupDelay(guildID)) * 12 * time.Hour).Unix()
 
 	// Only create the reminder if it's in the future
-	if midpoint <= time.Now().Unix() {
+	if midpoint <= clock.Now().Unix() {
 		return nil
 	}
 
 	reminderParams := &models.RecruitmentReminderParams{UserID: userID}
 	reminderTask, err := models.NewTaskWithScenario(
+		guildID,
 		models.TaskRecruitmentReminder,
 		reminderParams,
 		midpoint,
@@ -111,7 +116,7 @@ func CreateRecruitmentReminderAtMidpoint(ctx context.Context, userID string, sta
 		return fmt.Errorf("failed to create recruitment reminder task: %w", err)
 	}
 
-	if err := db.SaveTaskToRedis(ctx, *reminderTask); err != nil {
+	if err := db.GetStore().SaveTask(ctx, *reminderTask); err != nil {
 		return fmt.Errorf("failed to save recruitment reminder task: %w", err)
 	}
 
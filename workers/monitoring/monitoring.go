@@ -1,8 +1,10 @@
 package monitoring
 
 import (
+	"astralHRBot/clock"
 	"astralHRBot/db"
 	"astralHRBot/globals"
+	"astralHRBot/guilds"
 	"astralHRBot/logger"
 	"astralHRBot/models"
 	"context"
@@ -13,128 +15,214 @@ import (
 	"github.com/bwmarrin/discordgo"
 )
 
+// trackedKey identifies a user's monitoring in one guild
+type trackedKey struct {
+	guildID string
+	userID  string
+}
+
 type tracker struct {
-	trackedUsers map[string]*models.UserMonitoring
+	trackedUsers map[trackedKey]*models.UserMonitoring
 	eventChan    chan any
 	mu           sync.RWMutex
+	quit         chan struct{}
+	done         chan struct{} // closed when run returns
+	stopOnce     sync.Once
 }
 
+// eventBufferSize is how many events can wait for the tracker before SubmitEvent drops them
+const eventBufferSize = 1000
+
 var mon *tracker
 var readyChan = make(chan struct{})
 
-func Start() {
+// Start loads every guild's tracked users and starts the monitoring worker. If loading fails the
+// worker is not started and the error is returned.
+func Start() error {
 	mon = &tracker{
-		trackedUsers: make(map[string]*models.UserMonitoring),
-		eventChan:    make(chan any),
+		trackedUsers: make(map[trackedKey]*models.UserMonitoring),
+		eventChan:    make(chan any, eventBufferSize),
+		quit:         make(chan struct{}),
+		done:         make(chan struct{}),
 	}
 
-	users, err := db.GetTrackedUsers(context.Background())
+	for _, guildID := range guilds.IDs() {
+		if err := mon.load(guildID); err != nil {
+			logger.Error(logger.LogData{
+				"action":   "monitoring_startup",
+				"message":  "Failed to get tracked users",
+				"error":    err.Error(),
+				"guild_id": guildID,
+			})
+			// The worker never runs, so there is nothing for Stop to wait on
+			close(mon.done)
+			return fmt.Errorf("failed to load tracked users for guild %s: %w", guildID, err)
+		}
+	}
+
+	logger.Info(logger.LogData{
+		"action":        "monitoring_startup",
+		"message":       "Starting monitoring system",
+		"tracked_users": len(mon.trackedUsers),
+	})
+
+	go mon.run()
+	close(readyChan)
+	return nil
+}
+
+// load reads a guild's tracked users, dropping expired monitoring and recreating missing tasks
+func (t *tracker) load(guildID string) error {
+	ctx := db.WithGuild(context.Background(), guildID)
+
+	users, err := db.GetStore().GetTrackedUsers(ctx)
 	if err != nil {
-		logger.Error(logger.LogData{
-			"action":  "monitoring_startup",
-			"message": "Failed to get tracked users",
-			"error":   err.Error(),
-		})
-		return
+		return err
 	}
 
 	// Clean up expired monitoring on startup
 	for _, id := range users {
-		monitoringData, err := db.GetUserMonitoring(context.Background(), id)
+		monitoringData, err := db.GetStore().GetUserMonitoring(ctx, id)
 		if err != nil {
 			logger.Error(logger.LogData{
-				"action":  "monitoring_startup",
-				"message": "Failed to get monitoring data for user",
-				"error":   err.Error(),
-				"user_id": id,
+				"action":   "monitoring_startup",
+				"message":  "Failed to get monitoring data for user",
+				"error":    err.Error(),
+				"user_id":  id,
+				"guild_id": guildID,
 			})
 			continue
 		}
 
 		if monitoringData == nil || monitoringData.IsExpired() {
 			logger.Info(logger.LogData{
-				"action":  "monitoring_startup",
-				"message": "Removing expired monitoring",
-				"user_id": id,
+				"action":   "monitoring_startup",
+				"message":  "Removing expired monitoring",
+				"user_id":  id,
+				"guild_id": guildID,
 			})
-			err := db.RemoveTrackedUser(context.Background(), id)
+			err := db.GetStore().RemoveTrackedUser(ctx, id)
 			if err != nil {
 				logger.Error(logger.LogData{
-					"action":  "monitoring_startup",
-					"message": "Failed to remove expired monitoring",
-					"error":   err.Error(),
-					"user_id": id,
+					"action":   "monitoring_startup",
+					"message":  "Failed to remove expired monitoring",
+					"error":    err.Error(),
+					"user_id":  id,
+					"guild_id": guildID,
 				})
 			}
 			continue
 		}
 
-		mon.trackedUsers[id] = monitoringData
+		// Sessions saved before the bot served several guilds do not record one
+		if monitoringData.GuildID == "" {
+			monitoringData.GuildID = guildID
+		}
+		t.trackedUsers[trackedKey{guildID, id}] = monitoringData
 
 		// Recreate tasks for this user's monitoring scenarios
-		err = RecreateTasksForUser(id, monitoringData)
+		err = RecreateTasksForUser(guildID, id, monitoringData)
 		if err != nil {
 			logger.Error(logger.LogData{
-				"action":  "monitoring_startup",
-				"message": "Failed to recreate tasks for user",
-				"error":   err.Error(),
-				"user_id": id,
+				"action":   "monitoring_startup",
+				"message":  "Failed to recreate tasks for user",
+				"error":    err.Error(),
+				"user_id":  id,
+				"guild_id": guildID,
 			})
 		}
 	}
+	return nil
+}
 
-	logger.Info(logger.LogData{
-		"action":        "monitoring_startup",
-		"message":       "Starting monitoring system",
-		"tracked_users": len(mon.trackedUsers),
-	})
+// Stop ends the monitoring worker once it has handled every event already submitted, waiting for
+// it until ctx ends. Events submitted after Stop is called are dropped.
+func Stop(ctx context.Context) error {
+	if mon == nil {
+		return nil
+	}
+	mon.stopOnce.Do(func() { close(mon.quit) })
 
-	go mon.run()
-	close(readyChan)
+	select {
+	case <-mon.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-// WaitForReady blocks until the monitoring system is ready
-func WaitForReady() {
-	<-readyChan
+// IsReady reports whether Start has loaded the tracked users, without blocking
+func IsReady() bool {
+	select {
+	case <-readyChan:
+		return true
+	default:
+		return false
+	}
 }
 
 func (t *tracker) run() {
+	defer close(t.done)
 	logger.Info(logger.LogData{
 		"action":  "monitoring_worker",
 		"message": "Monitoring worker started",
 	})
-	for raw := range t.eventChan {
-		logger.Debug(logger.LogData{
-			"action":  "monitoring_event",
-			"message": "Received monitoring event",
-			"type":    fmt.Sprintf("%T", raw),
-		})
-		switch evt := raw.(type) {
-		case *discordgo.MessageCreate:
-			t.handleMessageCreate(evt)
-		case *discordgo.VoiceStateUpdate:
-			t.handleVoiceState(evt)
-		case *discordgo.InviteCreate:
-			t.handleInviteCreate(evt)
+	for {
+		select {
+		case raw := <-t.eventChan:
+			t.handle(raw)
+		case <-t.quit:
+			// Handle what was already submitted so no analytics are lost on shutdown
+			drained := 0
+			for {
+				select {
+				case raw := <-t.eventChan:
+					t.handle(raw)
+					drained++
+				default:
+					logger.Info(logger.LogData{
+						"action":  "monitoring_worker",
+						"message": "Monitoring worker stopped",
+						"drained": drained,
+					})
+					return
+				}
+			}
 		}
 	}
 }
 
-func (t *tracker) isTracked(userID string, action models.MonitoringAction) bool {
+func (t *tracker) handle(raw any) {
+	logger.Debug(logger.LogData{
+		"action":  "monitoring_event",
+		"message": "Received monitoring event",
+		"type":    fmt.Sprintf("%T", raw),
+	})
+	switch evt := raw.(type) {
+	case *discordgo.MessageCreate:
+		t.handleMessageCreate(evt)
+	case *discordgo.VoiceStateUpdate:
+		t.handleVoiceState(evt)
+	case *discordgo.InviteCreate:
+		t.handleInviteCreate(evt)
+	}
+}
+
+func (t *tracker) isTracked(guildID, userID string, action models.MonitoringAction) bool {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
-	if user, exists := t.trackedUsers[userID]; exists {
+	if user, exists := t.trackedUsers[trackedKey{guildID, userID}]; exists {
 		return user.ShouldTrackAction(action)
 	}
 	return false
 }
 
 // Helper function to update analytics for all active scenarios that track a specific action
-func (t *tracker) updateAnalyticsForAction(userID string, action models.MonitoringAction, field string, amount int) {
-	ctx := context.Background()
+func (t *tracker) updateAnalyticsForAction(guildID, userID string, action models.MonitoringAction, field string, amount int) {
+	ctx := db.WithGuild(context.Background(), guildID)
 
 	// Get user's active scenarios
-	userMonitoring, err := db.GetUserMonitoring(ctx, userID)
+	userMonitoring, err := db.GetStore().GetUserMonitoring(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "update_analytics_for_action",
@@ -165,8 +253,7 @@ func (t *tracker) updateAnalyticsForAction(userID string, action models.Monitori
 		}
 
 		if tracksAction {
-			key := fmt.Sprintf("user:%s:analytics:%s", userID, scenario)
-			err := db.IncreaseAttributeCount(ctx, key, field, amount)
+			err := db.GetStore().IncrementAnalytics(ctx, userID, scenario, field, amount)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"action":   "update_analytics_for_action",
@@ -183,10 +270,10 @@ func (t *tracker) updateAnalyticsForAction(userID string, action models.Monitori
 // updateAnalyticsForActionInChannel updates analytics only for scenarios that
 // both track the given action AND allow the provided channel via
 // models.ScenarioChannelEnvFilter. If a scenario has no channel filter, it is allowed.
-func (t *tracker) updateAnalyticsForActionInChannel(userID string, channelID string, action models.MonitoringAction, field string, amount int) {
-	ctx := context.Background()
+func (t *tracker) updateAnalyticsForActionInChannel(guildID, userID string, channelID string, action models.MonitoringAction, field string, amount int) {
+	ctx := db.WithGuild(context.Background(), guildID)
 
-	userMonitoring, err := db.GetUserMonitoring(ctx, userID)
+	userMonitoring, err := db.GetStore().GetUserMonitoring(ctx, userID)
 	if err != nil || userMonitoring == nil {
 		return
 	}
@@ -209,12 +296,11 @@ func (t *tracker) updateAnalyticsForActionInChannel(userID string, channelID str
 		}
 
 		// Channel allow-list check
-		if !isChannelAllowedForScenario(scenario, channelID) {
+		if !isChannelAllowedForScenario(guildID, scenario, channelID) {
 			continue
 		}
 
-		key := fmt.Sprintf("user:%s:analytics:%s", userID, scenario)
-		err := db.IncreaseAttributeCount(ctx, key, field, amount)
+		err := db.GetStore().IncrementAnalytics(ctx, userID, scenario, field, amount)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":   "update_analytics_for_action_in_channel",
@@ -234,17 +320,17 @@ func (t *tracker) handleMessageCreate(m *discordgo.MessageCreate) {
 		return
 	}
 
-	ctx := context.Background()
+	ctx := db.WithGuild(context.Background(), m.GuildID)
 
 	// Update channel activity for all active scenarios, respecting channel filters
-	userMonitoring, err := db.GetUserMonitoring(ctx, m.Author.ID)
+	userMonitoring, err := db.GetStore().GetUserMonitoring(ctx, m.Author.ID)
 	if err == nil && userMonitoring != nil {
 		for scenario := range userMonitoring.Scenarios {
 			// Only count channel usage if scenario allows this channel (or has no filter)
-			if !isChannelAllowedForScenario(scenario, m.ChannelID) {
+			if !isChannelAllowedForScenario(m.GuildID, scenario, m.ChannelID) {
 				continue
 			}
-			err := db.IncreaseChannelCount(ctx, m.Author.ID, m.ChannelID, string(scenario))
+			err := db.GetStore().IncrementChannelCount(ctx, m.Author.ID, scenario, m.ChannelID)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"action":   "increase_channel_count",
@@ -257,7 +343,7 @@ func (t *tracker) handleMessageCreate(m *discordgo.MessageCreate) {
 	}
 
 	// Only process analytics if user is being tracked for message creation
-	if !t.isTracked(m.Author.ID, models.ActionMessageCreate) {
+	if !t.isTracked(m.GuildID, m.Author.ID, models.ActionMessageCreate) {
 		return
 	}
 
@@ -268,7 +354,7 @@ func (t *tracker) handleMessageCreate(m *discordgo.MessageCreate) {
 	})
 
 	// Update analytics only for scenarios that track message creation AND allow this channel
-	t.updateAnalyticsForActionInChannel(m.Author.ID, m.ChannelID, models.ActionMessageCreate, "messages", 1)
+	t.updateAnalyticsForActionInChannel(m.GuildID, m.Author.ID, m.ChannelID, models.ActionMessageCreate, "messages", 1)
 
 	logger.Debug(logger.LogData{
 		"action":     "handle_message_create",
@@ -283,27 +369,27 @@ func (t *tracker) handleMessageEdit(m *discordgo.MessageUpdate) {
 		return
 	}
 
-	if !t.isTracked(m.Author.ID, models.ActionMessageEdit) {
+	if !t.isTracked(m.GuildID, m.Author.ID, models.ActionMessageEdit) {
 		return
 	}
 
 	// Update analytics only for scenarios that track message edits AND allow this channel
-	t.updateAnalyticsForActionInChannel(m.Author.ID, m.ChannelID, models.ActionMessageEdit, "message_edits", 1)
+	t.updateAnalyticsForActionInChannel(m.GuildID, m.Author.ID, m.ChannelID, models.ActionMessageEdit, "message_edits", 1)
 }
 
 func (t *tracker) handleMessageDelete(m *discordgo.MessageDelete) {
-	if !t.isTracked(m.Author.ID, models.ActionMessageDelete) {
+	if !t.isTracked(m.GuildID, m.Author.ID, models.ActionMessageDelete) {
 		return
 	}
 
 	// Update analytics only for scenarios that track message deletes AND allow this channel
-	t.updateAnalyticsForActionInChannel(m.Author.ID, m.ChannelID, models.ActionMessageDelete, "message_deletes", 1)
+	t.updateAnalyticsForActionInChannel(m.GuildID, m.Author.ID, m.ChannelID, models.ActionMessageDelete, "message_deletes", 1)
 }
 
 func (t *tracker) handleVoiceState(v *discordgo.VoiceStateUpdate) {
 	// Handle voice join
 	if v.BeforeUpdate == nil && v.ChannelID != "" {
-		if !t.isTracked(v.UserID, models.ActionVoiceJoin) {
+		if !t.isTracked(v.GuildID, v.UserID, models.ActionVoiceJoin) {
 			return
 		}
 
@@ -315,18 +401,18 @@ func (t *tracker) handleVoiceState(v *discordgo.VoiceStateUpdate) {
 		})
 
 		// Update analytics for all scenarios that track voice joins
-		t.updateAnalyticsForAction(v.UserID, models.ActionVoiceJoin, "voice_joins", 1)
+		t.updateAnalyticsForAction(v.GuildID, v.UserID, models.ActionVoiceJoin, "voice_joins", 1)
 		return
 	}
 
 	// Handle voice leave
 	if v.BeforeUpdate != nil && v.ChannelID == "" {
-		if !t.isTracked(v.UserID, models.ActionVoiceLeave) {
+		if !t.isTracked(v.GuildID, v.UserID, models.ActionVoiceLeave) {
 			return
 		}
 
 		// Update analytics for all scenarios that track voice leaves
-		t.updateAnalyticsForAction(v.UserID, models.ActionVoiceLeave, "voice_leaves", 1)
+		t.updateAnalyticsForAction(v.GuildID, v.UserID, models.ActionVoiceLeave, "voice_leaves", 1)
 	}
 }
 
@@ -335,12 +421,12 @@ func (t *tracker) handleInviteCreate(i *discordgo.InviteCreate) {
 		return
 	}
 
-	if !t.isTracked(i.Inviter.ID, models.ActionInviteCreate) {
+	if !t.isTracked(i.GuildID, i.Inviter.ID, models.ActionInviteCreate) {
 		return
 	}
 
 	// Update analytics for all scenarios that track invite creation
-	t.updateAnalyticsForAction(i.Inviter.ID, models.ActionInviteCreate, "invites", 1)
+	t.updateAnalyticsForAction(i.GuildID, i.Inviter.ID, models.ActionInviteCreate, "invites", 1)
 
 	logger.Debug(logger.LogData{
 		"action":  "handle_invite_create",
@@ -350,24 +436,24 @@ func (t *tracker) handleInviteCreate(i *discordgo.InviteCreate) {
 }
 
 func (t *tracker) handleReactionAdd(r *discordgo.MessageReactionAdd) {
-	if !t.isTracked(r.UserID, models.ActionReactionAdd) {
+	if !t.isTracked(r.GuildID, r.UserID, models.ActionReactionAdd) {
 		return
 	}
 
 	// Update analytics for all scenarios that track reaction adds
-	t.updateAnalyticsForAction(r.UserID, models.ActionReactionAdd, "reactions_added", 1)
+	t.updateAnalyticsForAction(r.GuildID, r.UserID, models.ActionReactionAdd, "reactions_added", 1)
 }
 
 func (t *tracker) handleReactionRemove(r *discordgo.MessageReactionRemove) {
-	if !t.isTracked(r.UserID, models.ActionReactionRemove) {
+	if !t.isTracked(r.GuildID, r.UserID, models.ActionReactionRemove) {
 		return
 	}
 
 	// Update analytics for all scenarios that track reaction removes
-	t.updateAnalyticsForAction(r.UserID, models.ActionReactionRemove, "reactions_removed", 1)
+	t.updateAnalyticsForAction(r.GuildID, r.UserID, models.ActionReactionRemove, "reactions_removed", 1)
 }
 
-func AddUserTracking(userID string, scenario models.MonitoringScenario, trackingDuration time.Duration) {
+func AddUserTracking(guildID, userID string, scenario models.MonitoringScenario, trackingDuration time.Duration) {
 	if mon == nil {
 		logger.Error(logger.LogData{
 			"action":  "add_user_tracking",
@@ -380,17 +466,17 @@ func AddUserTracking(userID string, scenario models.MonitoringScenario, tracking
 	mon.mu.Lock()
 	defer mon.mu.Unlock()
 
-	userMonitoring, exists := mon.trackedUsers[userID]
+	userMonitoring, exists := mon.trackedUsers[trackedKey{guildID, userID}]
 	if !exists {
-		userMonitoring = models.NewUserMonitoring(userID)
-		mon.trackedUsers[userID] = userMonitoring
+		userMonitoring = models.NewUserMonitoring(guildID, userID)
+		mon.trackedUsers[trackedKey{guildID, userID}] = userMonitoring
 	}
 
 	userMonitoring.AddScenario(scenario)
 	userMonitoring.SetExpiration(trackingDuration)
 
 	// Save to Redis
-	err := db.SaveUserMonitoring(context.Background(), userMonitoring)
+	err := db.GetStore().SaveUserMonitoring(db.WithGuild(context.Background(), guildID), userMonitoring)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "add_user_tracking",
@@ -403,12 +489,14 @@ func AddUserTracking(userID string, scenario models.MonitoringScenario, tracking
 	logger.Info(logger.LogData{
 		"action":   "add_user_tracking",
 		"message":  "Successfully added monitoring scenario for user",
+		"guild_id": guildID,
 		"user_id":  userID,
 		"scenario": scenario,
 		"duration": trackingDuration.String(),
 	})
 }
 
+// GetTrackedUsers returns the IDs of the users tracked in any guild, once for each guild they are tracked in
 func GetTrackedUsers() []string {
 	if mon == nil {
 		return nil
@@ -416,26 +504,42 @@ func GetTrackedUsers() []string {
 	mon.mu.RLock()
 	defer mon.mu.RUnlock()
 	ids := make([]string, 0, len(mon.trackedUsers))
-	for id := range mon.trackedUsers {
-		ids = append(ids, id)
+	for key := range mon.trackedUsers {
+		ids = append(ids, key.userID)
 	}
 	return ids
 }
 
-func GetUserMonitoringScenarios(userID string) []models.MonitoringScenario {
+// ScenarioCounts returns how many tracked users are in each monitoring scenario across every guild
+func ScenarioCounts() map[models.MonitoringScenario]int {
+	if mon == nil {
+		return nil
+	}
+	mon.mu.RLock()
+	defer mon.mu.RUnlock()
+	counts := make(map[models.MonitoringScenario]int)
+	for _, userMonitoring := range mon.trackedUsers {
+		for scenario := range userMonitoring.Scenarios {
+			counts[scenario]++
+		}
+	}
+	return counts
+}
+
+func GetUserMonitoringScenarios(guildID, userID string) []models.MonitoringScenario {
 	if mon == nil {
 		return nil
 	}
 	mon.mu.RLock()
 	defer mon.mu.RUnlock()
-	if userMonitoring, exists := mon.trackedUsers[userID]; exists {
+	if userMonitoring, exists := mon.trackedUsers[trackedKey{guildID, userID}]; exists {
 		return userMonitoring.GetScenarios()
 	}
 	return nil
 }
 
 // GetUserMonitoringStatus returns the current monitoring status for a user
-func GetUserMonitoringStatus(userID string) (*models.UserMonitoring, error) {
+func GetUserMonitoringStatus(guildID, userID string) (*models.UserMonitoring, error) {
 	if mon == nil {
 		return nil, fmt.Errorf("monitoring system not initialized")
 	}
@@ -443,7 +547,7 @@ func GetUserMonitoringStatus(userID string) (*models.UserMonitoring, error) {
 	mon.mu.RLock()
 	defer mon.mu.RUnlock()
 
-	if userMonitoring, exists := mon.trackedUsers[userID]; exists {
+	if userMonitoring, exists := mon.trackedUsers[trackedKey{guildID, userID}]; exists {
 		return userMonitoring, nil
 	}
 
@@ -451,7 +555,7 @@ func GetUserMonitoringStatus(userID string) (*models.UserMonitoring, error) {
 }
 
 // IsUserMonitored checks if a user is currently being monitored
-func IsUserMonitored(userID string) bool {
+func IsUserMonitored(guildID, userID string) bool {
 	if mon == nil {
 		return false
 	}
@@ -459,12 +563,12 @@ func IsUserMonitored(userID string) bool {
 	mon.mu.RLock()
 	defer mon.mu.RUnlock()
 
-	_, exists := mon.trackedUsers[userID]
+	_, exists := mon.trackedUsers[trackedKey{guildID, userID}]
 	return exists
 }
 
 // GetActiveMonitoringScenarios returns all active monitoring scenarios for a user
-func GetActiveMonitoringScenarios(userID string) ([]models.MonitoringScenario, error) {
+func GetActiveMonitoringScenarios(guildID, userID string) ([]models.MonitoringScenario, error) {
 	if mon == nil {
 		return nil, fmt.Errorf("monitoring system not initialized")
 	}
@@ -472,7 +576,7 @@ func GetActiveMonitoringScenarios(userID string) ([]models.MonitoringScenario, e
 	mon.mu.RLock()
 	defer mon.mu.RUnlock()
 
-	if userMonitoring, exists := mon.trackedUsers[userID]; exists {
+	if userMonitoring, exists := mon.trackedUsers[trackedKey{guildID, userID}]; exists {
 		return userMonitoring.GetScenarios(), nil
 	}
 
@@ -480,7 +584,7 @@ func GetActiveMonitoringScenarios(userID string) ([]models.MonitoringScenario, e
 }
 
 // AddScenario adds a monitoring scenario to a user
-func AddScenario(userID string, scenario models.MonitoringScenario) {
+func AddScenario(guildID, userID string, scenario models.MonitoringScenario) {
 	if mon == nil {
 		return
 	}
@@ -488,19 +592,19 @@ func AddScenario(userID string, scenario models.MonitoringScenario) {
 	mon.mu.Lock()
 	defer mon.mu.Unlock()
 
-	userMonitoring, exists := mon.trackedUsers[userID]
+	userMonitoring, exists := mon.trackedUsers[trackedKey{guildID, userID}]
 	if !exists {
-		userMonitoring = models.NewUserMonitoring(userID)
-		mon.trackedUsers[userID] = userMonitoring
+		userMonitoring = models.NewUserMonitoring(guildID, userID)
+		mon.trackedUsers[trackedKey{guildID, userID}] = userMonitoring
 	}
 
 	userMonitoring.AddScenario(scenario)
-	db.SaveUserMonitoring(context.Background(), userMonitoring)
+	db.GetStore().SaveUserMonitoring(db.WithGuild(context.Background(), guildID), userMonitoring)
 
 }
 
 // RemoveScenario removes a specific monitoring scenario from a user
-func RemoveScenario(userID string, scenario models.MonitoringScenario) error {
+func RemoveScenario(guildID, userID string, scenario models.MonitoringScenario) error {
 	if mon == nil {
 		return fmt.Errorf("monitoring system not initialized")
 	}
@@ -508,7 +612,7 @@ func RemoveScenario(userID string, scenario models.MonitoringScenario) error {
 	mon.mu.Lock()
 	defer mon.mu.Unlock()
 
-	userMonitoring, exists := mon.trackedUsers[userID]
+	userMonitoring, exists := mon.trackedUsers[trackedKey{guildID, userID}]
 	if !exists {
 		return fmt.Errorf("user %s is not being monitored", userID)
 	}
@@ -531,7 +635,7 @@ func RemoveScenario(userID string, scenario models.MonitoringScenario) error {
 	userMonitoring.RemoveScenario(scenario)
 
 	// Remove associated tasks for this scenario
-	err := RemoveTasksForScenario(userID, scenario)
+	err := RemoveTasksForScenario(guildID, userID, scenario)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":   "remove_scenario",
@@ -546,7 +650,7 @@ func RemoveScenario(userID string, scenario models.MonitoringScenario) error {
 	// If no more scenarios, remove user completely and all their tasks
 	if len(userMonitoring.Scenarios) == 0 {
 		// Remove all remaining tasks for this user
-		err := RemoveAllTasksForUser(userID)
+		err := RemoveAllTasksForUser(guildID, userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "remove_scenario",
@@ -557,8 +661,8 @@ func RemoveScenario(userID string, scenario models.MonitoringScenario) error {
 			// Continue with user removal even if task removal fails
 		}
 
-		delete(mon.trackedUsers, userID)
-		err = db.RemoveTrackedUser(context.Background(), userID)
+		delete(mon.trackedUsers, trackedKey{guildID, userID})
+		err = db.GetStore().RemoveTrackedUser(db.WithGuild(context.Background(), guildID), userID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "remove_scenario",
@@ -577,7 +681,7 @@ func RemoveScenario(userID string, scenario models.MonitoringScenario) error {
 		})
 	} else {
 		// Save updated monitoring scenarios
-		err := db.SaveUserMonitoring(context.Background(), userMonitoring)
+		err := db.GetStore().SaveUserMonitoring(db.WithGuild(context.Background(), guildID), userMonitoring)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "remove_scenario",
@@ -602,7 +706,7 @@ func RemoveScenario(userID string, scenario models.MonitoringScenario) error {
 // RemoveAllScenarios removes all active monitoring scenarios for a user.
 // This will also remove all associated tasks for each scenario, and if the
 // user has no scenarios left it will fully clean up their monitoring state.
-func RemoveAllScenarios(userID string) error {
+func RemoveAllScenarios(guildID, userID string) error {
 	if mon == nil {
 		return fmt.Errorf("monitoring system not initialized")
 	}
@@ -610,7 +714,7 @@ func RemoveAllScenarios(userID string) error {
 	// Take a snapshot of current scenarios under read lock
 	mon.mu.RLock()
 	var scenarios []models.MonitoringScenario
-	if userMonitoring, exists := mon.trackedUsers[userID]; exists {
+	if userMonitoring, exists := mon.trackedUsers[trackedKey{guildID, userID}]; exists {
 		scenarios = userMonitoring.GetScenarios()
 	}
 	mon.mu.RUnlock()
@@ -621,7 +725,7 @@ func RemoveAllScenarios(userID string) error {
 
 	var firstErr error
 	for _, sc := range scenarios {
-		if err := RemoveScenario(userID, sc); err != nil && firstErr == nil {
+		if err := RemoveScenario(guildID, userID, sc); err != nil && firstErr == nil {
 			firstErr = err
 		}
 	}
@@ -630,11 +734,11 @@ func RemoveAllScenarios(userID string) error {
 }
 
 // RemoveTasksForScenario removes all tasks associated with a specific user and scenario
-func RemoveTasksForScenario(userID string, scenario models.MonitoringScenario) error {
-	ctx := context.Background()
+func RemoveTasksForScenario(guildID, userID string, scenario models.MonitoringScenario) error {
+	ctx := db.WithGuild(context.Background(), guildID)
 
-	// Get all tasks from the queue (including future tasks)
-	allTasks, err := db.FetchAllTasks(ctx)
+	// Get the user's tasks from their index
+	userTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "remove_tasks_for_scenario",
@@ -654,12 +758,11 @@ func RemoveTasksForScenario(userID string, scenario models.MonitoringScenario) e
 		"message":     "Starting task removal for scenario",
 		"user_id":     userID,
 		"scenario":    scenarioStr,
-		"total_tasks": len(allTasks),
+		"total_tasks": len(userTasks),
 	})
 
-	for _, task := range allTasks {
-		// Check if this task is for the user and scenario using the new generic methods
-		if !task.IsForUser(userID) || !task.IsForScenario(scenarioStr) {
+	for _, task := range userTasks {
+		if !task.IsForScenario(scenarioStr) {
 			continue
 		}
 
@@ -673,7 +776,7 @@ func RemoveTasksForScenario(userID string, scenario models.MonitoringScenario) e
 		})
 
 		// Remove the task
-		err = db.DeleteTaskFromRedis(ctx, task.TaskID)
+		err = db.GetStore().DeleteTask(ctx, task.TaskID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "remove_tasks_for_scenario",
@@ -707,11 +810,11 @@ func RemoveTasksForScenario(userID string, scenario models.MonitoringScenario) e
 }
 
 // RemoveAllTasksForUser removes all tasks for a specific user regardless of scenario
-func RemoveAllTasksForUser(userID string) error {
-	ctx := context.Background()
+func RemoveAllTasksForUser(guildID, userID string) error {
+	ctx := db.WithGuild(context.Background(), guildID)
 
-	// Get all tasks from the queue (including future tasks)
-	allTasks, err := db.FetchAllTasks(ctx)
+	// Get the user's tasks from their index
+	userTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 	if err != nil {
 		logger.Error(logger.LogData{
 			"action":  "remove_all_tasks_for_user",
@@ -724,14 +827,9 @@ func RemoveAllTasksForUser(userID string) error {
 
 	// Find and remove all tasks for this user
 	tasksRemoved := 0
-	for _, task := range allTasks {
-		// Check if this task is for the user
-		if !task.IsForUser(userID) {
-			continue
-		}
-
+	for _, task := range userTasks {
 		// Remove the task
-		err = db.DeleteTaskFromRedis(ctx, task.TaskID)
+		err = db.GetStore().DeleteTask(ctx, task.TaskID)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":  "remove_all_tasks_for_user",
@@ -765,11 +863,11 @@ func RemoveAllTasksForUser(userID string) error {
 
 // RecreateTasksForUser recreates tasks for a user's monitoring scenarios
 // This can be called during startup or manually via command
-func RecreateTasksForUser(userID string, monitoringData *models.UserMonitoring) error {
-	ctx := context.Background()
+func RecreateTasksForUser(guildID, userID string, monitoringData *models.UserMonitoring) error {
+	ctx := db.WithGuild(context.Background(), guildID)
 
 	// Check if user already has tasks
-	existingTasks, err := db.GetTasksForUser(ctx, userID)
+	existingTasks, err := db.GetStore().GetTasksForUser(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("failed to get existing tasks: %w", err)
 	}
@@ -795,7 +893,7 @@ func RecreateTasksForUser(userID string, monitoringData *models.UserMonitoring)
 			"scenario": scenario,
 		})
 
-		err := recreateTaskForScenario(userID, scenario, monitoringData)
+		err := recreateTaskForScenario(guildID, userID, scenario, monitoringData)
 		if err != nil {
 			logger.Error(logger.LogData{
 				"action":   "recreate_tasks_for_user",
@@ -807,7 +905,7 @@ func RecreateTasksForUser(userID string, monitoringData *models.UserMonitoring)
 			// Continue with other scenarios even if one fails
 		} else {
 			// Check if scenario was removed (expired)
-			updatedMonitoringData, err := db.GetUserMonitoring(ctx, userID)
+			updatedMonitoringData, err := db.GetStore().GetUserMonitoring(ctx, userID)
 			if err == nil && updatedMonitoringData != nil {
 				if _, exists := updatedMonitoringData.Scenarios[scenario]; !exists {
 					scenariosRemoved++
@@ -835,8 +933,8 @@ func RecreateTasksForUser(userID string, monitoringData *models.UserMonitoring)
 }
 
 // recreateTaskForScenario recreates a task for a specific scenario
-func recreateTaskForScenario(userID string, scenario models.MonitoringScenario, monitoringData *models.UserMonitoring) error {
-	ctx := context.Background()
+func recreateTaskForScenario(guildID, userID string, scenario models.MonitoringScenario, monitoringData *models.UserMonitoring) error {
+	ctx := db.WithGuild(context.Background(), guildID)
 
 	// Get task functions for this scenario
 	taskFunctions := models.GetTaskFunctionsForScenario(scenario)
@@ -860,7 +958,7 @@ func recreateTaskForScenario(userID string, scenario models.MonitoringScenario,
 
 	// Calculate remaining time until monitoring expires
 	var scheduledTime int64
-	now := time.Now().Unix()
+	now := clock.Now().Unix()
 
 	if monitoringData.ExpiresAt > 0 {
 		// Use the original expiration time, but ensure it's not in the past
@@ -878,7 +976,7 @@ func recreateTaskForScenario(userID string, scenario models.MonitoringScenario,
 			})
 
 			// Remove the expired scenario
-			err := RemoveScenario(userID, scenario)
+			err := RemoveScenario(guildID, userID, scenario)
 			if err != nil {
 				logger.Error(logger.LogData{
 					"action":   "recreate_task_for_scenario",
@@ -898,9 +996,9 @@ func recreateTaskForScenario(userID string, scenario models.MonitoringScenario,
 		var defaultDelay int64
 		switch scenario {
 		case models.MonitoringScenarioRecruitmentProcess:
-			defaultDelay = int64(globals.GetRecruitmentCleanupDelay()) * 24 * 60 * 60 // Convert days to seconds
+			defaultDelay = int64(globals.GetRecruitmentCleanupDelay(guildID)) * 24 * 60 * 60 // Convert days to seconds
 		case models.MonitoringScenarioNewRecruit:
-			defaultDelay = int64(globals.GetNewRecruitTrackingDays()) * 24 * 60 * 60 // Convert days to seconds
+			defaultDelay = int64(globals.GetNewRecruitTrackingDays(guildID)) * 24 * 60 * 60 // Convert days to seconds
 		default:
 			// Fallback to 7 days for unknown scenarios
 			defaultDelay = 7 * 24 * 60 * 60
@@ -917,6 +1015,7 @@ func recreateTaskForScenario(userID string, scenario models.MonitoringScenario,
 		case "ProcessRecruitmentCleanup":
 			params := &models.RecruitmentCleanupParams{UserID: userID}
 			task, err = models.NewTaskWithScenario(
+				guildID,
 				models.TaskRecruitmentCleanup,
 				params,
 				scheduledTime,
@@ -938,6 +1037,7 @@ func recreateTaskForScenario(userID string, scenario models.MonitoringScenario,
 		case "ProcessUserCheckin":
 			params := &models.UserCheckinParams{UserID: userID}
 			task, err = models.NewTaskWithScenario(
+				guildID,
 				models.TaskUserCheckin,
 				params,
 				scheduledTime,
@@ -959,7 +1059,7 @@ func recreateTaskForScenario(userID string, scenario models.MonitoringScenario,
 		}
 
 		// Save the task to Redis
-		err = db.SaveTaskToRedis(ctx, *task)
+		err = db.GetStore().SaveTask(ctx, *task)
 		if err != nil {
 			return fmt.Errorf("failed to save task to Redis: %w", err)
 		}
@@ -1000,6 +1100,17 @@ func SubmitEvent(event any) {
 		"type":    fmt.Sprintf("%T", event),
 	})
 
+	select {
+	case <-mon.quit:
+		logger.Debug(logger.LogData{
+			"action":  "submit_event",
+			"message": "Monitoring is stopping - dropping event",
+			"type":    fmt.Sprintf("%T", event),
+		})
+		return
+	default:
+	}
+
 	select {
 	case mon.eventChan <- event:
 		logger.Debug(logger.LogData{
@@ -1016,11 +1127,11 @@ func SubmitEvent(event any) {
 	}
 }
 
-func GetUserAnalytics(userID string) (models.UserAnalytics, error) {
+func GetUserAnalytics(guildID, userID string) (models.UserAnalytics, error) {
 	if mon == nil {
 		return models.UserAnalytics{}, nil
 	}
 
-	ctx := context.Background()
-	return db.GetUserAnalytics(ctx, userID)
+	ctx := db.WithGuild(context.Background(), guildID)
+	return db.GetStore().GetUserAnalytics(ctx, userID)
 }
@@ -0,0 +1,197 @@
+package monitoring_test
+
+import (
+	"astralHRBot/db"
+	"astralHRBot/harness"
+	"astralHRBot/models"
+	"astralHRBot/workers/monitoring"
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// h runs the tracker against a fake guild, an in-memory store and a fake clock
+var h *harness.Harness
+
+// probeUserID is tracked for the whole run; a message from it marks how far the tracker has got
+const probeUserID = "700000000000000001"
+
+func TestMain(m *testing.M) {
+	var err error
+	h, err = harness.Start(harness.Config{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start harness: %v\n", err)
+		os.Exit(1)
+	}
+	monitoring.AddUserTracking(harness.GuildID, probeUserID, models.MonitoringScenarioNewRecruit, 0)
+	os.Exit(m.Run())
+}
+
+var probes int64
+
+// drain waits until the tracker has handled every event submitted before it was called. Events
+// are handled in order, so once a probe message is counted the earlier events have been too.
+func drain(t *testing.T) {
+	t.Helper()
+	probes++
+	monitoring.SubmitEvent(messageFrom(probeUserID, harness.GeneralChannelID))
+
+	ctx := db.WithGuild(context.Background(), harness.GuildID)
+	err := h.Eventually(func() error {
+		analytics, err := h.Store.GetScenarioAnalytics(ctx, probeUserID, models.MonitoringScenarioNewRecruit)
+		if err != nil {
+			return err
+		}
+		if analytics.Messages != probes {
+			return fmt.Errorf("probe messages = %d, want %d", analytics.Messages, probes)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("tracker did not catch up: %v", err)
+	}
+}
+
+func messageFrom(userID, channelID string) *discordgo.MessageCreate {
+	return &discordgo.MessageCreate{Message: &discordgo.Message{
+		GuildID:   harness.GuildID,
+		ChannelID: channelID,
+		Author:    &discordgo.User{ID: userID},
+	}}
+}
+
+func TestTrackerCountsEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		scenario models.MonitoringScenario // tracked scenario, empty for an untracked user
+		event    func(userID string) any
+		checked  models.MonitoringScenario
+		want     models.UserAnalytics
+	}{
+		{
+			name:     "recruit message in the recruitment channel",
+			userID:   "700000000000000101",
+			scenario: models.MonitoringScenarioRecruitmentProcess,
+			event:    func(userID string) any { return messageFrom(userID, harness.RecruitmentChannelID) },
+			checked:  models.MonitoringScenarioRecruitmentProcess,
+			want:     models.UserAnalytics{Messages: 1, TopChannelID: harness.RecruitmentChannelID},
+		},
+		{
+			name:     "recruit message outside the recruitment channel is filtered out",
+			userID:   "700000000000000102",
+			scenario: models.MonitoringScenarioRecruitmentProcess,
+			event:    func(userID string) any { return messageFrom(userID, harness.GeneralChannelID) },
+			checked:  models.MonitoringScenarioRecruitmentProcess,
+			want:     models.UserAnalytics{},
+		},
+		{
+			name:     "new member message anywhere",
+			userID:   "700000000000000103",
+			scenario: models.MonitoringScenarioNewRecruit,
+			event:    func(userID string) any { return messageFrom(userID, harness.GeneralChannelID) },
+			checked:  models.MonitoringScenarioNewRecruit,
+			want:     models.UserAnalytics{Messages: 1, TopChannelID: harness.GeneralChannelID},
+		},
+		{
+			name:     "new member voice join",
+			userID:   "700000000000000104",
+			scenario: models.MonitoringScenarioNewRecruit,
+			event: func(userID string) any {
+				return &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{GuildID: harness.GuildID, UserID: userID, ChannelID: "voice"}}
+			},
+			checked: models.MonitoringScenarioNewRecruit,
+			want:    models.UserAnalytics{VoiceJoins: 1},
+		},
+		{
+			name:     "recruit voice join is not tracked",
+			userID:   "700000000000000105",
+			scenario: models.MonitoringScenarioRecruitmentProcess,
+			event: func(userID string) any {
+				return &discordgo.VoiceStateUpdate{VoiceState: &discordgo.VoiceState{GuildID: harness.GuildID, UserID: userID, ChannelID: "voice"}}
+			},
+			checked: models.MonitoringScenarioRecruitmentProcess,
+			want:    models.UserAnalytics{},
+		},
+		{
+			name:     "recruit invite",
+			userID:   "700000000000000106",
+			scenario: models.MonitoringScenarioRecruitmentProcess,
+			event: func(userID string) any {
+				return &discordgo.InviteCreate{GuildID: harness.GuildID, Invite: &discordgo.Invite{Inviter: &discordgo.User{ID: userID}}}
+			},
+			checked: models.MonitoringScenarioRecruitmentProcess,
+			want:    models.UserAnalytics{Invites: 1},
+		},
+		{
+			name:    "untracked user",
+			userID:  "700000000000000107",
+			event:   func(userID string) any { return messageFrom(userID, harness.GeneralChannelID) },
+			checked: models.MonitoringScenarioNewRecruit,
+			want:    models.UserAnalytics{},
+		},
+		{
+			name:     "bot message",
+			userID:   "700000000000000108",
+			scenario: models.MonitoringScenarioNewRecruit,
+			event: func(userID string) any {
+				message := messageFrom(userID, harness.GeneralChannelID)
+				message.Author.Bot = true
+				return message
+			},
+			checked: models.MonitoringScenarioNewRecruit,
+			want:    models.UserAnalytics{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.scenario != "" {
+				monitoring.AddUserTracking(harness.GuildID, tt.userID, tt.scenario, 24*time.Hour)
+			}
+
+			monitoring.SubmitEvent(tt.event(tt.userID))
+			drain(t)
+
+			got, err := h.Store.GetScenarioAnalytics(db.WithGuild(context.Background(), harness.GuildID), tt.userID, tt.checked)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.want.UserID = tt.userID
+			if got != tt.want {
+				t.Errorf("analytics = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddUserTrackingExpiresOnTheClock(t *testing.T) {
+	const userID = "700000000000000201"
+	ctx := db.WithGuild(context.Background(), harness.GuildID)
+
+	monitoring.AddUserTracking(harness.GuildID, userID, models.MonitoringScenarioNewRecruit, 7*24*time.Hour)
+	if !monitoring.IsUserMonitored(harness.GuildID, userID) {
+		t.Fatal("user is not monitored after AddUserTracking")
+	}
+
+	stored, err := h.Store.GetUserMonitoring(ctx, userID)
+	if err != nil || stored == nil {
+		t.Fatalf("monitoring was not saved: %v", err)
+	}
+	if want := h.Clock.Now().Add(7 * 24 * time.Hour).Unix(); stored.ExpiresAt != want {
+		t.Errorf("ExpiresAt = %d, want %d", stored.ExpiresAt, want)
+	}
+
+	h.Clock.Advance(6 * 24 * time.Hour)
+	if stored.IsExpired() {
+		t.Error("monitoring expired a day early")
+	}
+	h.Clock.Advance(2 * 24 * time.Hour)
+	if !stored.IsExpired() {
+		t.Error("monitoring has not expired after its tracking window")
+	}
+}
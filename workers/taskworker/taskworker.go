@@ -1,55 +1,299 @@
 package taskworker
 
 import (
+	"astralHRBot/automation"
 	"astralHRBot/bot"
+	"astralHRBot/clock"
 	"astralHRBot/db"
+	"astralHRBot/guilds"
 	"astralHRBot/logger"
+	"astralHRBot/metrics"
 	"astralHRBot/models"
 	"context"
+	"fmt"
+	"sync"
 	"time"
 )
 
+const (
+	// taskLeaseDuration is how long a claimed task may run before it is requeued
+	taskLeaseDuration = 10 * time.Minute
+	// taskClaimBatchSize is the maximum number of tasks claimed per poll
+	taskClaimBatchSize = 100
+	// taskLeaseMargin is kept free at the end of the lease so the outcome is recorded before it expires
+	taskLeaseMargin = time.Minute
+)
+
+// processor is the polling loop started by StartTaskProcessor
+type processor struct {
+	quit     chan struct{}
+	done     chan struct{} // closed when the polling loop returns
+	tasks    sync.WaitGroup
+	stopOnce sync.Once
+}
+
+var taskProcessor *processor
+
 // StartTaskProcessor starts a background goroutine that processes tasks every 5 seconds
 func StartTaskProcessor() {
+	p := &processor{
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	taskProcessor = p
+
 	go func() {
+		defer close(p.done)
+
 		// Wait for Discord to be ready
-		<-bot.ReadyChan
+		select {
+		case <-bot.ReadyChan:
+		case <-p.quit:
+			return
+		}
 		logger.Info(logger.LogData{
 			"action":  "task_processor",
 			"message": "Discord connection established, starting task processing",
 		})
 
 		for {
-			taskList, err := db.FetchLatestTasks(context.Background())
-
-			if err != nil {
-				logger.Error(logger.LogData{
-					"action":  "start_task_processor",
-					"message": "Failed to get tasks",
-					"error":   err.Error(),
-				})
-				time.Sleep(5 * time.Second)
-				continue
-			}
-
-			for _, task := range taskList {
-				// Get handler for this task type from models
-				handler, exists := models.TaskHandlers[task.FunctionName]
-				if !exists {
+			for _, guildID := range guilds.IDs() {
+				// Due tasks stay queued while the guild's automation is paused and run once it resumes
+				if automation.Paused(guildID) {
+					continue
+				}
+				if err := pollTasks(context.Background(), guildID, &p.tasks); err != nil {
 					logger.Error(logger.LogData{
-						"action":    "process_task",
-						"task_id":   task.TaskID,
-						"task_type": string(task.FunctionName),
-						"message":   "No handler found for task type",
+						"action":   "start_task_processor",
+						"message":  "Failed to get tasks",
+						"guild_id": guildID,
+						"error":    err.Error(),
 					})
-					continue
 				}
+			}
 
-				// Execute handler in goroutine
-				go handler(task)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-p.quit:
+				return
 			}
+		}
+	}()
+}
+
+// StopTaskProcessor stops polling and waits until ctx ends for the running tasks to finish. Tasks
+// still running when ctx ends keep their lease and are requeued once it expires.
+func StopTaskProcessor(ctx context.Context) error {
+	p := taskProcessor
+	if p == nil {
+		return nil
+	}
+	p.stopOnce.Do(func() { close(p.quit) })
+
+	finished := make(chan struct{})
+	go func() {
+		<-p.done
+		p.tasks.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ProcessDueTasks claims the tasks that are due in every guild and runs them, returning once all of them have finished.
+// It lets callers that control time, such as the test harness, drive the processor without polling.
+func ProcessDueTasks(ctx context.Context) error {
+	var wg sync.WaitGroup
+	var err error
+	for _, guildID := range guilds.IDs() {
+		if pollErr := pollTasks(ctx, guildID, &wg); pollErr != nil && err == nil {
+			err = pollErr
+		}
+	}
+	wg.Wait()
+	return err
+}
+
+// pollTasks requeues a guild's expired leases, claims its due tasks and starts a goroutine for each, tracking them in wg
+func pollTasks(ctx context.Context, guildID string, wg *sync.WaitGroup) error {
+	ctx = db.WithGuild(ctx, guildID)
+
+	requeued, err := db.GetStore().RequeueExpiredTasks(ctx)
+	if err == nil && len(requeued) > 0 {
+		logger.Warn(logger.LogData{
+			"action":   "start_task_processor",
+			"message":  "Requeued tasks with expired leases",
+			"guild_id": guildID,
+			"task_ids": requeued,
+		})
+	}
+
+	taskList, err := db.GetStore().ClaimDueTasks(ctx, taskLeaseDuration, taskClaimBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, task := range taskList {
+		// Tasks queued before the bot served several guilds belong to the guild whose queue they are in
+		if task.GuildID == "" {
+			task.GuildID = guildID
+		}
+
+		// Get the registered definition for this task type
+		def, exists := models.GetTaskDefinition(task.FunctionName)
+		if !exists {
+			logger.Error(logger.LogData{
+				"action":    "process_task",
+				"task_id":   task.TaskID,
+				"task_type": string(task.FunctionName),
+				"message":   "No handler found for task type",
+			})
+			task.LastError = "no handler registered for task type"
+			db.GetStore().DeadLetterTask(ctx, task)
+			continue
+		}
+
+		// Execute handler in goroutine
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			runTask(def, task)
+		}()
+	}
+
+	return nil
+}
+
+// runTask executes a claimed task and records the outcome.
+// Successful tasks are acknowledged; failed or timed-out tasks are retried with
+// backoff until their retry policy is exhausted, then moved to the dead-letter queue.
+func runTask(def models.TaskDefinition, task models.Task) {
+	ctx := db.WithGuild(context.Background(), task.GuildID)
+	taskType := string(task.FunctionName)
+	metrics.TaskStarted(taskType, time.Unix(task.ScheduledTime, 0), clock.Now())
+
+	started := time.Now()
+	result, err := runWithTimeout(def, task)
+	duration := time.Since(started)
+	if err == nil {
+		metrics.TaskFinished(taskType, "completed", duration)
+		logger.Debug(logger.LogData{
+			"action":    "process_task",
+			"message":   "Task completed",
+			"task_id":   task.TaskID,
+			"task_type": string(task.FunctionName),
+			"guild_id":  task.GuildID,
+			"result":    result.Summary,
+		})
+		if ackErr := db.GetStore().AckTask(ctx, task.TaskID, result); ackErr != nil {
+			logger.Error(logger.LogData{
+				"action":  "process_task",
+				"message": "Failed to acknowledge completed task",
+				"error":   ackErr.Error(),
+				"task_id": task.TaskID,
+			})
+		}
+		return
+	}
+
+	policy := def.RetryPolicy
+	task.Retries++
+	task.LastError = err.Error()
+
+	if task.Retries >= policy.MaxAttempts {
+		metrics.TaskFinished(taskType, "dead_lettered", duration)
+		logger.Error(logger.LogData{
+			"action":    "process_task",
+			"message":   "Task exhausted its retries, moving to dead-letter queue",
+			"error":     err.Error(),
+			"task_id":   task.TaskID,
+			"task_type": string(task.FunctionName),
+			"guild_id":  task.GuildID,
+			"attempts":  task.Retries,
+		})
+		db.GetStore().DeadLetterTask(ctx, task)
+		return
+	}
+
+	metrics.TaskFinished(taskType, "retried", duration)
+	delay := policy.Backoff(task.Retries)
+	logger.Warn(logger.LogData{
+		"action":    "process_task",
+		"message":   "Task failed, scheduling retry",
+		"error":     err.Error(),
+		"task_id":   task.TaskID,
+		"task_type": string(task.FunctionName),
+		"guild_id":  task.GuildID,
+		"attempts":  task.Retries,
+		"retry_in":  delay.String(),
+	})
+	db.GetStore().RetryTask(ctx, task, clock.Now().Add(delay))
+}
+
+type taskOutcome struct {
+	result models.TaskResult
+	err    error
+}
+
+// runWithTimeout runs the task handler and cancels its context once the definition's timeout
+// elapses. It then waits for the handler to return, since work the handler has already started
+// (such as a job queued on the user's event worker) would otherwise be repeated by the retry. A
+// handler that finishes its work successfully after the timeout counts as completed. The wait
+// ends taskLeaseMargin before the lease expires, so a handler that ignores cancellation cannot
+// hold the task past its lease and have it claimed again while it is still running here.
+func runWithTimeout(def models.TaskDefinition, task models.Task) (models.TaskResult, error) {
+	ctx, cancel := context.WithTimeout(db.WithGuild(context.Background(), task.GuildID), def.Timeout)
+	defer cancel()
+	leaseDeadline := time.After(taskLeaseDuration - taskLeaseMargin)
+
+	outcome := make(chan taskOutcome, 1)
+	go func() {
+		result, err := safeRun(ctx, def.Handler, task)
+		outcome <- taskOutcome{result: result, err: err}
+	}()
+
+	select {
+	case out := <-outcome:
+		return out.result, out.err
+	case <-ctx.Done():
+	}
+
+	select {
+	case out := <-outcome:
+		if out.err == nil {
+			return out.result, nil
+		}
+		return models.TaskResult{}, fmt.Errorf("task timed out after %s: %w", def.Timeout, out.err)
+	case <-leaseDeadline:
+		logger.Error(logger.LogData{
+			"action":    "process_task",
+			"message":   "Task handler ignored cancellation, giving up before its lease expires",
+			"task_id":   task.TaskID,
+			"task_type": string(task.FunctionName),
+			"guild_id":  task.GuildID,
+		})
+		return models.TaskResult{}, fmt.Errorf("task timed out after %s and its handler did not stop", def.Timeout)
+	}
+}
 
-			time.Sleep(5 * time.Second)
+// safeRun executes a task handler, converting a panic into an error
+func safeRun(ctx context.Context, handler models.TaskHandlerFunc, task models.Task) (result models.TaskResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(logger.LogData{
+				"action":    "handler_panic",
+				"message":   "Recovered from panic in task handler",
+				"task_id":   task.TaskID,
+				"task_type": string(task.FunctionName),
+				"error":     r,
+			})
+			err = fmt.Errorf("handler panic: %v", r)
 		}
 	}()
+	return handler(ctx, task)
 }